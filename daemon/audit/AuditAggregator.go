@@ -0,0 +1,184 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+// Package audit
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 4:20 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package audit
+
+import (
+	common2 "minlib/common"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxAggregatedAuditEvents 一个聚合周期内最多区分的事件个数，超过之后的事件只计数
+const maxAggregatedAuditEvents = 1024
+
+// aggregatedAuditEvent 一个聚合周期内同一个键的事件
+type aggregatedAuditEvent struct {
+	fields map[string]string // 周期内第一个事件的详细信息
+	count  uint64            // 周期内的事件个数
+}
+
+// AuditAggregator
+// 将高频的审计事件聚合之后异步写入审计日志
+//
+// @Description:
+//  1. 签名验证失败等事件可以由网络上的任意一方触发，逐条同步写入会让写审计日志（签名、写文件）成为转发的瓶颈，并且审计日志会被
+//     无限制的撑大；
+//  2. Add 只在内存中计数，不做任何 I/O ；后台协程每个聚合周期写一次，同一个键在一个周期内最多写一条记录，记录中包含周期内第一个
+//     事件的详细信息以及事件个数 count ；
+//  3. 一个周期内不同的键最多 maxAggregatedAuditEvents 个，超过之后的事件只累加到一条 overflow 记录中。
+//
+type AuditAggregator struct {
+	auditLog  *AuditLog
+	eventType string
+	events    map[string]*aggregatedAuditEvent // 键 => 本周期内的事件
+	overflow  uint64                           // 本周期内因为键太多而没有单独统计的事件个数
+	lock      sync.Mutex
+	stopChan  chan struct{}
+	doneChan  chan struct{}
+}
+
+// CreateAuditAggregator
+// 创建一个审计事件聚合器，并启动后台写入协程
+//
+// @Description:
+// @param auditLog
+// @param eventType		聚合的事件类型
+// @param interval		聚合周期
+// @return *AuditAggregator
+//
+func CreateAuditAggregator(auditLog *AuditLog, eventType string, interval time.Duration) *AuditAggregator {
+	a := &AuditAggregator{
+		auditLog:  auditLog,
+		eventType: eventType,
+		events:    make(map[string]*aggregatedAuditEvent),
+		stopChan:  make(chan struct{}),
+		doneChan:  make(chan struct{}),
+	}
+	go a.run(interval)
+	return a
+}
+
+// Add
+// 添加一个事件
+//
+// @Description:
+// @receiver a
+// @param key		聚合使用的键，键相同的事件在一个周期内只写一条记录
+// @param fields	事件的详细信息
+//
+func (a *AuditAggregator) Add(key string, fields map[string]string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if event, ok := a.events[key]; ok {
+		event.count++
+		return
+	}
+	if len(a.events) >= maxAggregatedAuditEvents {
+		a.overflow++
+		return
+	}
+	a.events[key] = &aggregatedAuditEvent{fields: fields, count: 1}
+}
+
+// Close
+// 停止后台写入协程，并写入最后一个周期内的事件
+//
+// @Description:
+//  应该在关闭审计日志之前调用
+// @receiver a
+//
+func (a *AuditAggregator) Close() {
+	close(a.stopChan)
+	<-a.doneChan
+}
+
+//
+// 后台写入协程，每个聚合周期写一次
+//
+// @Description:
+// @receiver a
+// @param interval
+//
+func (a *AuditAggregator) run(interval time.Duration) {
+	defer close(a.doneChan)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for true {
+		select {
+		case <-ticker.C:
+			a.flush()
+		case <-a.stopChan:
+			a.flush()
+			return
+		}
+	}
+}
+
+//
+// 将本周期内的事件写入审计日志
+//
+// @Description:
+//  先在锁内换出本周期的事件，写审计日志时不持有锁，不会阻塞 Add
+// @receiver a
+//
+func (a *AuditAggregator) flush() {
+	a.lock.Lock()
+	events, overflow := a.events, a.overflow
+	a.events = make(map[string]*aggregatedAuditEvent)
+	a.overflow = 0
+	a.lock.Unlock()
+
+	keys := make([]string, 0, len(events))
+	for key := range events {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		event := events[key]
+		fields := make(map[string]string, len(event.fields)+1)
+		for name, value := range event.fields {
+			fields[name] = value
+		}
+		fields["count"] = strconv.FormatUint(event.count, 10)
+		a.record(fields)
+	}
+	if overflow > 0 {
+		a.record(map[string]string{
+			"overflow": "true",
+			"count":    strconv.FormatUint(overflow, 10),
+		})
+	}
+}
+
+//
+// 写入一条记录，失败时只输出日志
+//
+// @Description:
+// @receiver a
+// @param fields
+//
+func (a *AuditAggregator) record(fields map[string]string) {
+	if err := a.auditLog.Record(a.eventType, fields); err != nil {
+		common2.LogError("Record aggregated audit event failed: ", err)
+	}
+}
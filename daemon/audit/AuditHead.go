@@ -0,0 +1,137 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+// Package audit
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 4:10 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// auditHeadDomain 计算审计日志头部摘要时使用的前缀，避免头部的签名被当成某条记录的签名
+const auditHeadDomain = "mir-audit-head"
+
+// AuditHead
+// 审计日志的头部，记录最后一条记录的序号和哈希值
+//
+// @Description:
+//  1. 哈希链只能发现被修改、删除或者插入的记录，从末尾截掉若干条记录之后剩下的哈希链依然是完整的，所以每次追加记录之后都会更新
+//     一个签名的头部；
+//  2. 校验时审计日志中的记录数少于头部记录的序号，或者对应序号的记录哈希值和头部不一致，都说明审计日志被截断或者替换过。
+//
+type AuditHead struct {
+	Sequence  uint64 // 最后一条记录的序号
+	Hash      string // 最后一条记录的哈希值（十六进制）
+	Signature string // 对头部摘要的签名（十六进制）
+}
+
+// ComputeDigest
+// 计算头部的摘要
+//
+// @Description:
+// @receiver h
+// @return []byte
+//
+func (h *AuditHead) ComputeDigest() []byte {
+	digest := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s", auditHeadDomain, h.Sequence, h.Hash)))
+	return digest[:]
+}
+
+// Verify
+// 校验头部的签名
+//
+// @Description:
+// @receiver h
+// @param verifier
+// @return bool
+//
+func (h *AuditHead) Verify(verifier IAuditVerifier) bool {
+	signature, err := hex.DecodeString(h.Signature)
+	return err == nil && verifier.Verify(h.ComputeDigest(), signature)
+}
+
+//
+// 创建一个签名的头部
+//
+// @Description:
+// @param record		最后一条记录
+// @param signer
+// @return *AuditHead
+// @return error
+//
+func createAuditHead(record *AuditRecord, signer IAuditSigner) (*AuditHead, error) {
+	head := &AuditHead{
+		Sequence: record.Sequence,
+		Hash:     record.Hash,
+	}
+	signature, err := signer.Sign(head.ComputeDigest())
+	if err != nil {
+		return nil, err
+	}
+	head.Signature = hex.EncodeToString(signature)
+	return head, nil
+}
+
+//
+// 读取头部文件
+//
+// @Description:
+// @param path
+// @return *AuditHead		文件不存在时返回 nil
+// @return error
+//
+func readAuditHead(path string) (*AuditHead, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	head := new(AuditHead)
+	if err := json.Unmarshal(content, head); err != nil {
+		return nil, createAuditErrorByType(InvalidAuditHeadError, path)
+	}
+	return head, nil
+}
+
+//
+// 写入头部文件
+//
+// @Description:
+//  先写入临时文件再重命名，保证头部文件总是完整的
+// @param path
+// @param head
+// @return error
+//
+func writeAuditHead(path string, head *AuditHead) error {
+	content, err := json.Marshal(head)
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,268 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package audit
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 3:40 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package audit
+
+import (
+	"encoding/hex"
+	"fmt"
+	"mir-go/daemon/common"
+	"sync"
+)
+
+// AuditHeadFileSuffix 审计日志头部文件的后缀
+const AuditHeadFileSuffix = ".head"
+
+// AuditLog
+// 只能追加的、哈希链式的审计日志
+//
+// @Description:
+//  1. 用于记录安全相关的事件：网络包签名验证失败、管理命令以及 LogicFace 的创建；
+//  2. 每条记录都包含前一条记录的哈希值，并使用路由器的网络身份签名，之后可以使用 miraudit 校验整条哈希链，发现被篡改、删除或者
+//     插入的记录；写入本地文件时还会维护一个签名的头部（ AuditHead ），用来发现从末尾被截断的审计日志；
+//  3. 记录通过 IAuditSink 持久化，默认写入本地文件，可以替换成区块链等存储后端；
+//  4. 可以被多个协程并发调用，记录按照调用的顺序串行同步写入，高频的事件应该通过 AuditAggregator 聚合之后异步写入。
+//
+type AuditLog struct {
+	sink     IAuditSink
+	signer   IAuditSigner
+	headPath string // 头部文件的路径，为空时不维护头部
+	sequence uint64 // 最后一条记录的序号
+	lastHash string // 最后一条记录的哈希值
+	lock     sync.Mutex
+}
+
+// CreateAuditLog
+// 创建一个审计日志，并从存储后端的最后一条记录继续哈希链
+//
+// @Description:
+//  最后一条记录的哈希值不正确，或者不是由 signer 签名的时候返回错误，不会在别人的哈希链后面继续追加记录
+// @param sink
+// @param signer
+// @return *AuditLog
+// @return error
+//
+func CreateAuditLog(sink IAuditSink, signer IAuditSigner) (*AuditLog, error) {
+	auditLog := &AuditLog{
+		sink:     sink,
+		signer:   signer,
+		lastHash: GenesisHash,
+	}
+	last, err := sink.Last()
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		if hash, err := last.ComputeHash(); err != nil || hash != last.Hash {
+			return nil, createAuditErrorByType(InvalidAuditHashError, fmt.Sprintf("record %d", last.Sequence))
+		}
+		digest, _ := hex.DecodeString(last.Hash)
+		signature, err := hex.DecodeString(last.Signature)
+		if err != nil || !signer.Verify(digest, signature) {
+			return nil, createAuditErrorByType(AuditSignerMismatchError,
+				fmt.Sprintf("record %d is not signed by %s", last.Sequence, signer.Name()))
+		}
+		auditLog.sequence = last.Sequence
+		auditLog.lastHash = last.Hash
+	}
+	return auditLog, nil
+}
+
+// OpenFileAuditLog
+// 打开一个写入本地文件的审计日志
+//
+// @Description:
+//  1. 头部保存在 path + AuditHeadFileSuffix 中，审计日志中已经有记录但是头部不存在、头部的签名无效，或者审计日志的记录数少于头部
+//     记录的序号时，认为审计日志被破坏，直接返回错误，不会重新开始一条新的哈希链；
+//  2. 打开之后会记录一条 AuditEventAuditLogOpened 事件，其中记录了签名者的网络身份。
+// @param path		审计日志文件的路径
+// @param signer		审计记录的签名者，一般使用路由器的网络身份
+// @return *AuditLog
+// @return error
+//
+func OpenFileAuditLog(path string, signer IAuditSigner) (*AuditLog, error) {
+	headPath := path + AuditHeadFileSuffix
+	head, err := readAuditHead(headPath)
+	if err != nil {
+		return nil, err
+	}
+	sink, err := CreateFileAuditSink(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAuditHead(sink, head, signer); err != nil {
+		_ = sink.Close()
+		return nil, err
+	}
+	auditLog, err := CreateAuditLog(sink, signer)
+	if err != nil {
+		_ = sink.Close()
+		return nil, err
+	}
+	auditLog.headPath = headPath
+	if err := auditLog.Record(AuditEventAuditLogOpened, map[string]string{"identity": signer.Name()}); err != nil {
+		_ = sink.Close()
+		return nil, err
+	}
+	return auditLog, nil
+}
+
+//
+// 检查存储后端中的记录和头部是否一致
+//
+// @Description:
+// @param sink
+// @param head		为 nil 时表示头部文件不存在
+// @param signer
+// @return error
+//
+func checkAuditHead(sink IAuditSink, head *AuditHead, signer IAuditSigner) error {
+	last, err := sink.Last()
+	if err != nil {
+		return err
+	}
+	if head == nil {
+		if last != nil {
+			return createAuditErrorByType(MissingAuditHeadError, "audit log has records but no head")
+		}
+		return nil
+	}
+	if !head.Verify(signer) {
+		return createAuditErrorByType(InvalidAuditHeadError, "head is not signed by "+signer.Name())
+	}
+	if last == nil || last.Sequence < head.Sequence {
+		return createAuditErrorByType(TruncatedAuditLogError, fmt.Sprintf("head sequence %d", head.Sequence))
+	}
+	return nil
+}
+
+// Record
+// 追加一条审计记录
+//
+// @Description:
+// @receiver a
+// @param eventType		事件类型
+// @param fields			事件的详细信息
+// @return error
+//
+func (a *AuditLog) Record(eventType string, fields map[string]string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	record := &AuditRecord{
+		Sequence:  a.sequence + 1,
+		Timestamp: common.GetCurrentTime(),
+		Type:      eventType,
+		Fields:    fields,
+		PrevHash:  a.lastHash,
+	}
+	hash, err := record.ComputeHash()
+	if err != nil {
+		return err
+	}
+	digest, _ := hex.DecodeString(hash)
+	signature, err := a.signer.Sign(digest)
+	if err != nil {
+		return err
+	}
+	record.Hash = hash
+	record.Signature = hex.EncodeToString(signature)
+	if err := a.sink.Append(record); err != nil {
+		return err
+	}
+	a.sequence = record.Sequence
+	a.lastHash = record.Hash
+
+	if a.headPath != "" {
+		head, err := createAuditHead(record, a.signer)
+		if err != nil {
+			return err
+		}
+		return writeAuditHead(a.headPath, head)
+	}
+	return nil
+}
+
+// Close
+// 关闭审计日志
+//
+// @Description:
+// @receiver a
+// @return error
+//
+func (a *AuditLog) Close() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.sink.Close()
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+///// 错误处理
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	InvalidAuditKeyError = iota
+	InvalidAuditRecordError
+	BrokenAuditSequenceError
+	BrokenAuditHashChainError
+	InvalidAuditHashError
+	InvalidAuditSignatureError
+	AuditSignerMismatchError
+	InvalidAuditHeadError
+	MissingAuditHeadError
+	TruncatedAuditLogError
+)
+
+type AuditError struct {
+	msg string
+}
+
+func (a AuditError) Error() string {
+	return fmt.Sprintf("AuditError: %s", a.msg)
+}
+
+func createAuditErrorByType(errorType int, detail string) (err AuditError) {
+	switch errorType {
+	case InvalidAuditKeyError:
+		err.msg = "invalid audit key: " + detail
+	case InvalidAuditRecordError:
+		err.msg = "invalid audit record: " + detail
+	case BrokenAuditSequenceError:
+		err.msg = "broken audit sequence: " + detail
+	case BrokenAuditHashChainError:
+		err.msg = "broken audit hash chain: " + detail
+	case InvalidAuditHashError:
+		err.msg = "invalid audit record hash: " + detail
+	case InvalidAuditSignatureError:
+		err.msg = "invalid audit record signature: " + detail
+	case AuditSignerMismatchError:
+		err.msg = "audit signer mismatch: " + detail
+	case InvalidAuditHeadError:
+		err.msg = "invalid audit head: " + detail
+	case MissingAuditHeadError:
+		err.msg = "missing audit head: " + detail
+	case TruncatedAuditLogError:
+		err.msg = "audit log truncated: " + detail
+	default:
+		err.msg = "Unknown error"
+	}
+	return
+}
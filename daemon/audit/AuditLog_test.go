@@ -0,0 +1,193 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package audit
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 4:00 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package audit
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ed25519AuditSigner 测试使用的签名者，私钥只保存在内存中
+type ed25519AuditSigner struct {
+	name       string
+	privateKey ed25519.PrivateKey
+}
+
+func newEd25519AuditSigner(t *testing.T, name string) *ed25519AuditSigner {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ed25519AuditSigner{name: name, privateKey: privateKey}
+}
+
+func (e *ed25519AuditSigner) Sign(digest []byte) ([]byte, error) {
+	return ed25519.Sign(e.privateKey, digest), nil
+}
+
+func (e *ed25519AuditSigner) Verify(digest []byte, signature []byte) bool {
+	return ed25519.Verify(e.privateKey.Public().(ed25519.PublicKey), digest, signature)
+}
+
+func (e *ed25519AuditSigner) Name() string {
+	return e.name
+}
+
+func TestAuditLog_RecordAndVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mir-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	signer := newEd25519AuditSigner(t, "/mir/router/0")
+
+	// 写入几条记录，重新打开之后哈希链继续
+	for i := 0; i < 2; i++ {
+		auditLog, err := OpenFileAuditLog(path, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = auditLog.Record(AuditEventFaceCreated, map[string]string{"faceId": fmt.Sprint(i)})
+		_ = auditLog.Record(AuditEventMgmtCommand, map[string]string{"command": "/fib-mgmt/add", "code": "200"})
+		_ = auditLog.Close()
+	}
+	n, err := VerifyAuditLogFile(path, signer)
+	fmt.Println("verified", n, err)
+	if err != nil || n != 6 {
+		t.Fatal("expect 6 verified records, got", n, err)
+	}
+
+	content, _ := ioutil.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+
+	// 篡改一条记录的内容
+	tampered := append([]string{}, lines...)
+	tampered[2] = strings.Replace(tampered[2], "/fib-mgmt/add", "/fib-mgmt/del", 1)
+	_ = ioutil.WriteFile(path, []byte(strings.Join(tampered, "\n")), 0600)
+	if n, err := VerifyAuditLogFile(path, signer); err == nil || n != 2 {
+		t.Fatal("expect tampered record to be detected", n, err)
+	} else {
+		fmt.Println(err)
+	}
+
+	// 删除一条记录
+	removed := append(append([]string{}, lines[:3]...), lines[4:]...)
+	_ = ioutil.WriteFile(path, []byte(strings.Join(removed, "\n")), 0600)
+	if n, err := VerifyAuditLogFile(path, signer); err == nil || n != 3 {
+		t.Fatal("expect removed record to be detected", n, err)
+	} else {
+		fmt.Println(err)
+	}
+
+	// 从末尾截掉两条记录，剩下的哈希链是完整的，但是和头部不一致
+	truncated := lines[:4]
+	_ = ioutil.WriteFile(path, []byte(strings.Join(truncated, "\n")+"\n"), 0600)
+	if n, err := VerifyAuditLogFile(path, signer); err == nil || n != 4 {
+		t.Fatal("expect truncated audit log to be detected", n, err)
+	} else {
+		fmt.Println(err)
+	}
+	if _, err := OpenFileAuditLog(path, signer); err == nil {
+		t.Fatal("expect opening a truncated audit log to fail")
+	} else {
+		fmt.Println(err)
+	}
+}
+
+func TestAuditLog_OpenExisting(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mir-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	signer := newEd25519AuditSigner(t, "/mir/router/0")
+
+	auditLog, err := OpenFileAuditLog(path, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = auditLog.Close()
+
+	// 使用其它网络身份打开已有的审计日志时失败，不会在别人的哈希链后面继续追加记录
+	if _, err := OpenFileAuditLog(path, newEd25519AuditSigner(t, "/mir/router/1")); err == nil {
+		t.Fatal("expect opening with another signer to fail")
+	} else {
+		fmt.Println(err)
+	}
+
+	// 审计日志存在但是头部缺失时失败，不会重新开始一条新的哈希链
+	_ = os.Remove(path + AuditHeadFileSuffix)
+	if _, err := OpenFileAuditLog(path, signer); err == nil {
+		t.Fatal("expect opening without head to fail")
+	} else {
+		fmt.Println(err)
+	}
+	if _, err := VerifyAuditLogFile(path, signer); err == nil {
+		t.Fatal("expect verifying without head to fail")
+	}
+}
+
+func TestAuditAggregator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mir-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	signer := newEd25519AuditSigner(t, "/mir/router/0")
+	auditLog, err := OpenFileAuditLog(path, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同一个键的事件在一个周期内只写一条记录
+	aggregator := CreateAuditAggregator(auditLog, AuditEventVerifyFailed, time.Hour)
+	for i := 0; i < 100; i++ {
+		aggregator.Add("1|invalid-signature", map[string]string{"faceId": "1", "reason": "invalid-signature"})
+	}
+	aggregator.Add("2|invalid-signature", map[string]string{"faceId": "2", "reason": "invalid-signature"})
+	for i := 0; i < maxAggregatedAuditEvents+5; i++ {
+		aggregator.Add(fmt.Sprint("overflow-", i), map[string]string{})
+	}
+	aggregator.Close()
+	_ = auditLog.Close()
+
+	n, err := VerifyAuditLogFile(path, signer)
+	if err != nil || n != 1+maxAggregatedAuditEvents+1 {
+		t.Fatal("unexpected number of records", n, err)
+	}
+	content, _ := ioutil.ReadFile(path)
+	if !strings.Contains(string(content), `"count":"100","faceId":"1"`) ||
+		!strings.Contains(string(content), `"count":"7","overflow":"true"`) {
+		t.Fatal("unexpected aggregated records")
+	}
+}
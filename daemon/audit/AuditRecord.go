@@ -0,0 +1,89 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package audit
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 3:00 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+const (
+	AuditEventVerifyFailed   = "verify-failed"    // 网络包签名验证失败
+	AuditEventMgmtCommand    = "mgmt-command"     // 执行了一个管理命令
+	AuditEventMgmtReject     = "mgmt-reject"      // 管理命令权限验证失败
+	AuditEventFaceCreated    = "face-created"     // 创建了一个 LogicFace
+	AuditEventAuditLogOpened = "audit-log-opened" // 审计日志被打开，每次路由器启动时记录一次
+)
+
+// GenesisHash 审计日志中第一条记录的 PrevHash
+var GenesisHash = hex.EncodeToString(make([]byte, sha256.Size))
+
+// AuditRecord
+// 审计日志中的一条记录
+//
+// @Description:
+//  1. 每条记录都保存了前一条记录的哈希值，所有记录构成一条哈希链，修改、删除或者插入任意一条记录都会导致之后的哈希链断开；
+//  2. Hash 为 (Sequence, Timestamp, Type, Fields, PrevHash) 序列化之后的 SHA-256 摘要， Signature 为路由器对 Hash 的签名。
+//
+type AuditRecord struct {
+	Sequence  uint64            // 序号，从 1 开始连续递增
+	Timestamp uint64            // 记录的时间，单位 ms
+	Type      string            // 事件类型
+	Fields    map[string]string // 事件的详细信息
+	PrevHash  string            // 前一条记录的哈希值（十六进制）
+	Hash      string            // 本条记录的哈希值（十六进制）
+	Signature string            // 对本条记录哈希值的签名（十六进制）
+}
+
+// auditRecordContent 参与哈希计算的记录内容
+type auditRecordContent struct {
+	Sequence  uint64
+	Timestamp uint64
+	Type      string
+	Fields    map[string]string
+	PrevHash  string
+}
+
+// ComputeHash
+// 计算记录的哈希值
+//
+// @Description:
+//  encoding/json 序列化 map 时会对键排序，所以同样的记录内容总是得到同样的哈希值
+// @receiver r
+// @return string		十六进制的 SHA-256 摘要
+// @return error
+//
+func (r *AuditRecord) ComputeHash() (string, error) {
+	content, err := json.Marshal(auditRecordContent{
+		Sequence:  r.Sequence,
+		Timestamp: r.Timestamp,
+		Type:      r.Type,
+		Fields:    r.Fields,
+		PrevHash:  r.PrevHash,
+	})
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(content)
+	return hex.EncodeToString(digest[:]), nil
+}
@@ -0,0 +1,162 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+// Package audit
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 3:30 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package audit
+
+import (
+	"minlib/security"
+)
+
+// IAuditVerifier
+// 审计记录签名的校验者
+//
+// @Description:
+//
+type IAuditVerifier interface {
+	// Verify 校验 signature 是否是对 digest 的有效签名
+	Verify(digest []byte, signature []byte) bool
+}
+
+// IAuditSigner
+// 审计记录的签名者
+//
+// @Description:
+//  签名者同时可以校验自己的签名，打开已有的审计日志时，用来确认之前的记录是由同一个签名者写入的
+//
+type IAuditSigner interface {
+	IAuditVerifier
+
+	// Sign 对记录的哈希值进行签名
+	Sign(digest []byte) ([]byte, error)
+
+	// Name 获取签名者的网络身份，校验审计日志时需要使用该网络身份的公钥
+	Name() string
+}
+
+// IdentityAuditSigner
+// 使用路由器网络身份的私钥对审计记录签名
+//
+// @Description:
+//  私钥由 KeyChain 加密保存，只有在 MIRStarter.initKeyChain 使用密码解锁网络身份之后才能签名，审计日志旁边不保存任何密钥
+//
+type IdentityAuditSigner struct {
+	identity *security.Identity
+}
+
+// CreateIdentityAuditSigner
+// 使用 KeyChain 的当前网络身份创建一个审计记录签名者
+//
+// @Description:
+// @param keyChain		当前网络身份需要已经被解锁
+// @return *IdentityAuditSigner
+// @return error		当前网络身份不存在或者没有被解锁时返回错误
+//
+func CreateIdentityAuditSigner(keyChain *security.KeyChain) (*IdentityAuditSigner, error) {
+	identity := keyChain.GetCurrentIdentity()
+	if identity == nil || identity.IsLocked() {
+		return nil, createAuditErrorByType(InvalidAuditKeyError, "current identity is not unlocked")
+	}
+	return &IdentityAuditSigner{identity: identity}, nil
+}
+
+// Sign
+// 对记录的哈希值进行签名
+//
+// @Description:
+// @receiver i
+// @param digest
+// @return []byte
+// @return error
+//
+func (i *IdentityAuditSigner) Sign(digest []byte) ([]byte, error) {
+	return i.identity.Prikey.Sign(digest)
+}
+
+// Verify
+// 校验 signature 是否是本网络身份对 digest 的有效签名
+//
+// @Description:
+// @receiver i
+// @param digest
+// @param signature
+// @return bool
+//
+func (i *IdentityAuditSigner) Verify(digest []byte, signature []byte) bool {
+	return verifyByIdentity(i.identity, digest, signature)
+}
+
+// Name
+// 获取签名者的网络身份
+//
+// @Description:
+// @receiver i
+// @return string
+//
+func (i *IdentityAuditSigner) Name() string {
+	return i.identity.Name
+}
+
+// IdentityAuditVerifier
+// 使用网络身份的公钥校验审计记录的签名
+//
+// @Description:
+//  只需要公钥，网络身份不需要被解锁，供 miraudit 离线校验使用
+//
+type IdentityAuditVerifier struct {
+	identity *security.Identity
+}
+
+// CreateIdentityAuditVerifier
+// 创建一个使用网络身份公钥的审计记录校验者
+//
+// @Description:
+// @param identity
+// @return *IdentityAuditVerifier
+//
+func CreateIdentityAuditVerifier(identity *security.Identity) *IdentityAuditVerifier {
+	return &IdentityAuditVerifier{identity: identity}
+}
+
+// Verify
+// 校验 signature 是否是网络身份对 digest 的有效签名
+//
+// @Description:
+// @receiver i
+// @param digest
+// @param signature
+// @return bool
+//
+func (i *IdentityAuditVerifier) Verify(digest []byte, signature []byte) bool {
+	return verifyByIdentity(i.identity, digest, signature)
+}
+
+//
+// 使用网络身份的公钥校验签名
+//
+// @Description:
+// @param identity
+// @param digest
+// @param signature
+// @return bool
+//
+func verifyByIdentity(identity *security.Identity, digest []byte, signature []byte) bool {
+	ok, err := identity.Pubkey.Verify(digest, signature)
+	return err == nil && ok
+}
@@ -0,0 +1,127 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package audit
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 3:50 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package audit
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// VerifyAuditLog
+// 校验审计日志的哈希链以及每条记录的签名
+//
+// @Description:
+//  依次检查每条记录：
+//   1. 序号从 1 开始连续递增；
+//   2. PrevHash 等于前一条记录的 Hash ，第一条记录的 PrevHash 为 GenesisHash ；
+//   3. Hash 等于根据记录内容重新计算得到的哈希值；
+//   4. Signature 是 verifier 对应的网络身份对 Hash 的有效签名；
+//   5. head 不为 nil 时，其签名有效，并且序号为 head.Sequence 的记录存在，哈希值等于 head.Hash ，否则说明审计日志从末尾被截断了。
+//  任意一项检查失败都会返回错误，错误信息中包含出错的行号
+// @param reader			审计日志的内容，每行一条记录
+// @param verifier		签名的校验者
+// @param head			审计日志的头部，为 nil 时不检查截断
+// @return uint64			校验通过的记录数
+// @return error
+//
+func VerifyAuditLog(reader io.Reader, verifier IAuditVerifier, head *AuditHead) (uint64, error) {
+	if head != nil && !head.Verify(verifier) {
+		return 0, createAuditErrorByType(InvalidAuditHeadError, "invalid head signature")
+	}
+	verified := uint64(0)
+	prevHash := GenesisHash
+	lineNum := 0
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 4096), maxAuditRecordSize)
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		location := fmt.Sprintf("line %d", lineNum)
+
+		record := new(AuditRecord)
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return verified, createAuditErrorByType(InvalidAuditRecordError, location)
+		}
+		if record.Sequence != verified+1 {
+			return verified, createAuditErrorByType(BrokenAuditSequenceError,
+				fmt.Sprintf("%s, expect %d, got %d", location, verified+1, record.Sequence))
+		}
+		if record.PrevHash != prevHash {
+			return verified, createAuditErrorByType(BrokenAuditHashChainError, location)
+		}
+		if hash, err := record.ComputeHash(); err != nil || hash != record.Hash {
+			return verified, createAuditErrorByType(InvalidAuditHashError, location)
+		}
+		digest, _ := hex.DecodeString(record.Hash)
+		signature, err := hex.DecodeString(record.Signature)
+		if err != nil || !verifier.Verify(digest, signature) {
+			return verified, createAuditErrorByType(InvalidAuditSignatureError, location)
+		}
+		if head != nil && record.Sequence == head.Sequence && record.Hash != head.Hash {
+			return verified, createAuditErrorByType(InvalidAuditHeadError, location+" does not match head")
+		}
+
+		verified++
+		prevHash = record.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return verified, err
+	}
+	if head != nil && verified < head.Sequence {
+		return verified, createAuditErrorByType(TruncatedAuditLogError,
+			fmt.Sprintf("expect at least %d records, got %d", head.Sequence, verified))
+	}
+	return verified, nil
+}
+
+// VerifyAuditLogFile
+// 校验审计日志文件
+//
+// @Description:
+//  头部从 path + AuditHeadFileSuffix 中读取，审计日志中有记录但是头部不存在时校验失败
+// @param path				审计日志文件的路径
+// @param verifier			签名的校验者
+// @return uint64				校验通过的记录数
+// @return error
+//
+func VerifyAuditLogFile(path string, verifier IAuditVerifier) (uint64, error) {
+	head, err := readAuditHead(path + AuditHeadFileSuffix)
+	if err != nil {
+		return 0, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	verified, err := VerifyAuditLog(file, verifier, head)
+	if err == nil && head == nil && verified > 0 {
+		return verified, createAuditErrorByType(MissingAuditHeadError, path+AuditHeadFileSuffix)
+	}
+	return verified, err
+}
@@ -0,0 +1,155 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package audit
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 3:20 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const maxAuditRecordSize = 1024 * 1024 // 单条审计记录的最大长度，单位字节
+
+// FileAuditSink
+// 将审计记录以 JSON Lines 的格式追加到本地文件的存储后端
+//
+// @Description:
+//  文件以追加模式打开，每条记录占一行，记录一旦写入就不会被修改
+//
+type FileAuditSink struct {
+	file *os.File
+	last *AuditRecord // 最后一条记录
+	lock sync.Mutex
+}
+
+// CreateFileAuditSink
+// 打开（不存在时创建）一个审计日志文件
+//
+// @Description:
+//  打开时会读取文件中的最后一条记录，以便继续哈希链
+// @param path		审计日志文件的路径
+// @return *FileAuditSink
+// @return error	最后一条记录无法解析时返回错误，此时应该先使用校验工具检查审计日志
+//
+func CreateFileAuditSink(path string) (*FileAuditSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	last, err := readLastAuditRecord(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileAuditSink{file: file, last: last}, nil
+}
+
+// Append
+// 追加一条记录
+//
+// @Description:
+// @receiver f
+// @param record
+// @return error
+//
+func (f *FileAuditSink) Append(record *AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	f.last = record
+	return nil
+}
+
+// Last
+// 获取最后一条记录
+//
+// @Description:
+// @receiver f
+// @return *AuditRecord
+// @return error
+//
+func (f *FileAuditSink) Last() (*AuditRecord, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.last, nil
+}
+
+// Close
+// 关闭审计日志文件
+//
+// @Description:
+// @receiver f
+// @return error
+//
+func (f *FileAuditSink) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.file.Close()
+}
+
+//
+// 读取审计日志文件中的最后一条记录
+//
+// @Description:
+// @param path
+// @return *AuditRecord		文件不存在或者为空时返回 nil
+// @return error
+//
+func readLastAuditRecord(path string) (*AuditRecord, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lastLine []byte
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 4096), maxAuditRecordSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) > 0 {
+			lastLine = append(lastLine[:0], scanner.Bytes()...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if lastLine == nil {
+		return nil, nil
+	}
+	record := new(AuditRecord)
+	if err := json.Unmarshal(lastLine, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
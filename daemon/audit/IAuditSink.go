@@ -0,0 +1,40 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package audit
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 3:10 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package audit
+
+// IAuditSink
+// 审计日志的存储后端
+//
+// @Description:
+//  存储后端只需要按顺序追加记录，并能取回最后一条记录以便在重启之后继续哈希链。本地文件是默认的实现，之后可以实现本接口接入区块链等
+//  存储后端
+//
+type IAuditSink interface {
+	// Append 追加一条已经签名的记录
+	Append(record *AuditRecord) error
+
+	// Last 获取最后一条记录，没有任何记录时返回 nil
+	Last() (*AuditRecord, error)
+
+	// Close 关闭存储后端
+	Close() error
+}
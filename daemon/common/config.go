@@ -68,6 +68,14 @@ func (mirConfig *MIRConfig) Init() {
 	mirConfig.TableConfig.CSSize = 500
 	mirConfig.TableConfig.CSReplaceStrategy = "LRU"
 	mirConfig.TableConfig.CacheUnsolicitedData = false
+	mirConfig.TableConfig.CSAdmissionPolicy = "admit-all"
+	mirConfig.TableConfig.CSAdmissionProbability = 0.1
+	mirConfig.TableConfig.CSAdmissionAllowPrefixes = []string{}
+	mirConfig.TableConfig.CSAdmissionDenyPrefixes = []string{}
+	mirConfig.TableConfig.CSStorage = "memory"
+	mirConfig.TableConfig.CSMemoryCapacity = 64 * 1024 * 1024
+	mirConfig.TableConfig.CSDiskCapacity = 1024 * 1024 * 1024
+	mirConfig.TableConfig.CSDiskPath = "/usr/local/.mir/cs"
 
 	// LogicFace
 	mirConfig.LogicFaceConfig.SupportTCP = true
@@ -87,9 +95,18 @@ func (mirConfig *MIRConfig) Init() {
 	mirConfig.MaxRouterSignatureNum = 4
 	mirConfig.SecurityConfig.ParallelVerifyNum = 10
 	mirConfig.SecurityConfig.IdentityDBPath = security.DefaultIdentityDBPath
+	mirConfig.SecurityConfig.AuditLogPath = "/usr/local/.mir/audit/audit.log"
+	mirConfig.SecurityConfig.TrustSchemaPath = ""
+	mirConfig.SecurityConfig.FetchCertificate = false
+	mirConfig.SecurityConfig.CertificateCacheLifetime = 3600000
+	mirConfig.SecurityConfig.CertificateFetchTimeout = 4000
+	mirConfig.SecurityConfig.CertificateTrustAnchors = ""
 
 	// Forwarder
 	mirConfig.ForwarderConfig.PacketQueueSize = 100
+	mirConfig.ForwarderConfig.ShardNum = 1
+	mirConfig.ForwarderConfig.GPPktDedupCacheSize = 10000
+	mirConfig.ForwarderConfig.GPPktDedupLifetime = 2000
 }
 
 // Save 保存当前配置状态到配置文件当中
@@ -112,7 +129,7 @@ type GeneralConfig struct {
 	////////////////////////////////////////////////////////////////////////////////////////////////
 	DefaultId               string `ini:"DefaultId"`               // 默认网络身份
 	EncryptedPasswdSavePath string `ini:"EncryptedPasswdSavePath"` // 加密秘钥保存位置
-	IdentifierType          []int  `ini:"IdentifierType"`          // 当前路由器支持的标识类型，102 => GPPkt | 103 => 内容兴趣标识（Interest）| 104 => 内容兴趣标识（Interest）
+	IdentifierType          []int  `ini:"IdentifierType"`          // 当前路由器支持的标识类型，102 => GPPkt | 103 => 内容兴趣标识（Interest）| 104 => 内容数据标识（Data），同时也是转发器处理的标识类型白名单
 	DefaultRouteConfigPath  string `ini:"DefaultRouteConfigPath"`  // 静态路由配置文件路径
 }
 
@@ -130,9 +147,17 @@ type TableConfig struct {
 	////////////////////////////////////////////////////////////////////////////////////////////////
 	//// Table
 	////////////////////////////////////////////////////////////////////////////////////////////////
-	CSSize               int    `ini:"CSSize"`               // CS缓存大小，包为单位
-	CSReplaceStrategy    string `ini:"CSReplaceStrategy"`    // 缓存替换策略
-	CacheUnsolicitedData bool   `ini:"CacheUnsolicitedData"` // 是否缓存未请求的数据（Unsolicited Data）
+	CSSize                   int      `ini:"CSSize"`                   // CS缓存大小，包为单位
+	CSReplaceStrategy        string   `ini:"CSReplaceStrategy"`        // 缓存替换策略
+	CacheUnsolicitedData     bool     `ini:"CacheUnsolicitedData"`     // 是否缓存未请求的数据（Unsolicited Data）
+	CSAdmissionPolicy        string   `ini:"CSAdmissionPolicy"`        // 缓存准入策略 admit-all | probabilistic | prefix | second-hit
+	CSAdmissionProbability   float64  `ini:"CSAdmissionProbability"`   // probabilistic 准入策略的缓存概率，取值范围 (0, 1]
+	CSAdmissionAllowPrefixes []string `ini:"CSAdmissionAllowPrefixes"` // prefix 准入策略的前缀白名单，为空表示允许所有前缀
+	CSAdmissionDenyPrefixes  []string `ini:"CSAdmissionDenyPrefixes"`  // prefix 准入策略的前缀黑名单，优先于白名单
+	CSStorage                string   `ini:"CSStorage"`                // 缓存的存储方式 memory | two-tier
+	CSMemoryCapacity         int64    `ini:"CSMemoryCapacity"`         // two-tier 缓存内存层的容量，单位 Byte
+	CSDiskCapacity           int64    `ini:"CSDiskCapacity"`           // two-tier 缓存磁盘层的容量，单位 Byte
+	CSDiskPath               string   `ini:"CSDiskPath"`               // two-tier 缓存磁盘层的存储目录，重启之后从中恢复缓存
 }
 
 type LogicFaceConfig struct {
@@ -157,19 +182,28 @@ type SecurityConfig struct {
 	////////////////////////////////////////////////////////////////////////////////////////////////
 	//// Security
 	////////////////////////////////////////////////////////////////////////////////////////////////
-	VerifyPacket          bool   `ini:"VerifyPacket"`          // 是否开启包签名验证
-	Log2BlockChain        bool   `ini:"Log2BlockChain"`        // 是否发送日志到区块链
-	MiddleRouterSignature bool   `ini:"MiddleRouterSignature"` //是否开启中间路由器签名
-	MaxRouterSignatureNum int    `ini:"MaxRouterSignatureNum"` // 最大中间路由器签名数量
-	ParallelVerifyNum     int    `ini:"ParallelVerifyNum"`     // 并行包验证协程数量
-	IdentityDBPath        string `ini:"IdentityDBPath"`        // 身份持久化sqlite数据库存储位置
+	VerifyPacket             bool   `ini:"VerifyPacket"`             // 是否开启包签名验证
+	Log2BlockChain           bool   `ini:"Log2BlockChain"`           // 是否发送日志到区块链（接入区块链之前写入本地的审计日志）
+	MiddleRouterSignature    bool   `ini:"MiddleRouterSignature"`    //是否开启中间路由器签名
+	MaxRouterSignatureNum    int    `ini:"MaxRouterSignatureNum"`    // 最大中间路由器签名数量
+	ParallelVerifyNum        int    `ini:"ParallelVerifyNum"`        // 并行包验证协程数量
+	IdentityDBPath           string `ini:"IdentityDBPath"`           // 身份持久化sqlite数据库存储位置
+	AuditLogPath             string `ini:"AuditLogPath"`             // 审计日志文件的存储位置
+	TrustSchemaPath          string `ini:"TrustSchemaPath"`          // 信任模式配置文件的存储位置，为空时不使用信任模式
+	FetchCertificate         bool   `ini:"FetchCertificate"`         // 是否从网络中获取未知签名者的证书
+	CertificateCacheLifetime int    `ini:"CertificateCacheLifetime"` // 获取到的证书的缓存时间，单位 ms
+	CertificateFetchTimeout  int    `ini:"CertificateFetchTimeout"`  // 获取证书的超时时间，单位 ms
+	CertificateTrustAnchors  string `ini:"CertificateTrustAnchors"`  // 获取证书时使用的信任锚，多个网络身份以逗号分隔
 }
 
 type ForwarderConfig struct {
 	////////////////////////////////////////////////////////////////////////////////////////////////
 	//// Forwarder
 	////////////////////////////////////////////////////////////////////////////////////////////////
-	PacketQueueSize int `ini:"PacketQueueSize"` // 包缓冲队列大小
+	PacketQueueSize     int `ini:"PacketQueueSize"`     // 包缓冲队列大小
+	ShardNum            int `ini:"ShardNum"`            // 转发分片数，每个分片使用一个协程处理网络包，小于等于0时使用CPU核数
+	GPPktDedupCacheSize int `ini:"GPPktDedupCacheSize"` // 每个分片的 GPPkt 去重缓存最多记录的包数，小于等于0时不进行去重
+	GPPktDedupLifetime  int `ini:"GPPktDedupLifetime"`  // GPPkt 去重缓存中记录的存活时间，单位 ms
 }
 
 type ManagementConfig struct {
@@ -0,0 +1,71 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package common
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 2:40 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package common
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+)
+
+// TrustSchemaConfig
+// 信任模式配置文件的内容
+//
+// @Description:
+//  配置文件为 XML 格式，根元素为 TrustSchema ，示例见仓库根目录下的 trustSchema.xml
+//
+type TrustSchemaConfig struct {
+	XMLName       xml.Name          `xml:"TrustSchema"`
+	DefaultAction string            `xml:"DefaultAction"` // 没有规则匹配时的处理方式 accept | reject ，为空时为 accept
+	Rules         []TrustSchemaRule `xml:"Rule"`          // 规则列表，按照顺序匹配
+}
+
+// TrustSchemaRule
+// 信任模式中的一条规则
+//
+// @Description:
+//
+type TrustSchemaRule struct {
+	Id         string   `xml:"Id"`             // 规则的名字，用于日志和丢包原因
+	Identifier string   `xml:"Identifier"`     // 网络包标识的模式
+	Signers    []string `xml:"Signers>Signer"` // 允许的签名者网络身份的模式
+	Anchors    []string `xml:"Anchors>Anchor"` // 信任锚，这些网络身份可以为匹配本规则的所有网络包签名，只和签名者完全相同的比较，不沿证书链查找
+}
+
+// ParseTrustSchemaConfig
+// 解析信任模式配置文件
+//
+// @Description:
+// @param configPath	配置文件的路径
+// @return *TrustSchemaConfig
+// @return error
+//
+func ParseTrustSchemaConfig(configPath string) (*TrustSchemaConfig, error) {
+	content, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	var trustSchemaConfig TrustSchemaConfig
+	if err := xml.Unmarshal(content, &trustSchemaConfig); err != nil {
+		return nil, err
+	}
+	return &trustSchemaConfig, nil
+}
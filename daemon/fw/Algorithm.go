@@ -90,3 +90,22 @@ func HasPendingOutRecords(entry *table.PITEntry) bool {
 	}
 	return false
 }
+
+// FindLatestInRecord
+// 找到 PIT 条目中最晚过期的、仍未过期的 in-record
+//
+// @Description:
+//  策略需要在没有收到下游兴趣包的情况下重新转发时（例如收到 Nack 或者上游被关闭），使用其中保存的兴趣包
+// @param entry
+// @param now			当前时间，单位 ms
+// @return *table.InRecord		不存在未过期的 in-record 时返回 nil
+//
+func FindLatestInRecord(entry *table.PITEntry, now uint64) *table.InRecord {
+	var latest *table.InRecord
+	for _, inRecord := range entry.GetInRecords() {
+		if inRecord.ExpireTime > now && (latest == nil || inRecord.ExpireTime > latest.ExpireTime) {
+			latest = inRecord
+		}
+	}
+	return latest
+}
@@ -0,0 +1,355 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 11:03 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"math"
+	"strings"
+	"sync"
+)
+
+const (
+	asfRttAlpha = 0.125 // 计算平滑 RTT 时新样本所占的权重（RFC 6298）
+	asfRttBeta  = 0.25  // 计算 RTT 偏差时新样本所占的权重（RFC 6298）
+)
+
+// FaceMeasurement
+// 记录某个前缀下，某个 LogicFace 的测量信息
+//
+// @Description:
+//
+type FaceMeasurement struct {
+	LogicFaceId   uint64            // 逻辑接口号
+	SRtt          float64           // 平滑 RTT，单位 ms
+	RttVar        float64           // RTT 偏差，单位 ms
+	LastRtt       float64           // 最近一次测得的 RTT，单位 ms
+	NTimeouts     uint64            // 连续超时的次数，收到 data 之后清零
+	hasRtt        bool              // 是否已经有过 RTT 样本
+	pendingProbes map[string]uint64 // 还没有收到 data 的探测兴趣包，探测兴趣包对应 PIT 条目的标识 => 发送时间，单位 ms
+}
+
+// HasRtt
+// 判断是否已经测得过 RTT
+//
+// @Description:
+// @receiver fm
+// @return bool
+//
+func (fm *FaceMeasurement) HasRtt() bool {
+	return fm.hasRtt
+}
+
+//
+// 记录一个 RTT 样本
+//
+// @Description:
+// @receiver fm
+// @param rtt		单位 ms
+//
+func (fm *FaceMeasurement) addRttSample(rtt float64) {
+	if !fm.hasRtt {
+		fm.SRtt = rtt
+		fm.RttVar = rtt / 2
+		fm.hasRtt = true
+	} else {
+		fm.RttVar = (1-asfRttBeta)*fm.RttVar + asfRttBeta*math.Abs(fm.SRtt-rtt)
+		fm.SRtt = (1-asfRttAlpha)*fm.SRtt + asfRttAlpha*rtt
+	}
+	fm.LastRtt = rtt
+	fm.NTimeouts = 0
+}
+
+// NamespaceMeasurement
+// 记录某个前缀下所有 LogicFace 的测量信息
+//
+// @Description:
+//
+type NamespaceMeasurement struct {
+	faces         map[uint64]*FaceMeasurement // LogicFaceId => 测量信息
+	lastProbeTime uint64                      // 上一次探测的时间，单位 ms
+}
+
+// AsfMeasurements
+// 自适应转发策略使用的测量表，以（前缀，LogicFace）为键保存测量信息
+//
+// @Description:
+//
+type AsfMeasurements struct {
+	lock       sync.Mutex
+	namespaces map[string]*NamespaceMeasurement // 前缀 => 测量信息
+}
+
+// NewAsfMeasurements
+// 新建一个测量表
+//
+// @Description:
+// @return *AsfMeasurements
+//
+func NewAsfMeasurements() *AsfMeasurements {
+	return &AsfMeasurements{
+		namespaces: make(map[string]*NamespaceMeasurement),
+	}
+}
+
+//
+// 获取某个前缀的测量信息，不存在则创建
+//
+// @Description:
+//  调用者需要持有 a.lock
+// @receiver a
+// @param prefix
+// @return *NamespaceMeasurement
+//
+func (a *AsfMeasurements) getOrCreateNamespace(prefix string) *NamespaceMeasurement {
+	ns, ok := a.namespaces[prefix]
+	if !ok {
+		ns = &NamespaceMeasurement{
+			faces: make(map[uint64]*FaceMeasurement),
+		}
+		a.namespaces[prefix] = ns
+	}
+	return ns
+}
+
+//
+// 获取某个前缀下某个 LogicFace 的测量信息
+//
+// @Description:
+//  调用者需要持有 a.lock
+// @receiver a
+// @param prefix
+// @param logicFaceId
+// @return *FaceMeasurement		不存在时返回 nil
+//
+func (a *AsfMeasurements) getFace(prefix string, logicFaceId uint64) *FaceMeasurement {
+	if ns, ok := a.namespaces[prefix]; ok {
+		return ns.faces[logicFaceId]
+	}
+	return nil
+}
+
+//
+// 获取某个前缀下某个 LogicFace 的测量信息，不存在则创建
+//
+// @Description:
+//  调用者需要持有 a.lock
+// @receiver a
+// @param prefix
+// @param logicFaceId
+// @return *FaceMeasurement
+//
+func (a *AsfMeasurements) getOrCreateFace(prefix string, logicFaceId uint64) *FaceMeasurement {
+	ns := a.getOrCreateNamespace(prefix)
+	fm, ok := ns.faces[logicFaceId]
+	if !ok {
+		fm = &FaceMeasurement{LogicFaceId: logicFaceId, pendingProbes: make(map[string]uint64)}
+		ns.faces[logicFaceId] = fm
+	}
+	return fm
+}
+
+// AddRttSample
+// 记录一个 RTT 样本
+//
+// @Description:
+// @receiver a
+// @param prefix
+// @param logicFaceId
+// @param rtt			单位 ms
+//
+func (a *AsfMeasurements) AddRttSample(prefix string, logicFaceId uint64, rtt float64) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.getOrCreateFace(prefix, logicFaceId).addRttSample(rtt)
+}
+
+// RecordTimeout
+// 记录一次超时
+//
+// @Description:
+// @receiver a
+// @param prefix
+// @param logicFaceId
+//
+func (a *AsfMeasurements) RecordTimeout(prefix string, logicFaceId uint64) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.getOrCreateFace(prefix, logicFaceId).NTimeouts++
+}
+
+// GetFaceMeasurement
+// 获取某个前缀下某个 LogicFace 的测量信息的一份拷贝
+//
+// @Description:
+// @receiver a
+// @param prefix
+// @param logicFaceId
+// @return FaceMeasurement
+// @return bool			不存在测量信息时返回 false
+//
+func (a *AsfMeasurements) GetFaceMeasurement(prefix string, logicFaceId uint64) (FaceMeasurement, bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if fm := a.getFace(prefix, logicFaceId); fm != nil {
+		measurement := *fm
+		measurement.pendingProbes = nil
+		return measurement, true
+	}
+	return FaceMeasurement{}, false
+}
+
+// RemoveFace
+// 移除某个 LogicFace 在所有前缀下的测量信息
+//
+// @Description:
+// @receiver a
+// @param logicFaceId
+//
+func (a *AsfMeasurements) RemoveFace(logicFaceId uint64) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for _, ns := range a.namespaces {
+		delete(ns.faces, logicFaceId)
+	}
+}
+
+// TryStartProbe
+// 判断某个前缀是否到了需要探测的时间，如果是则记录本次探测时间并返回 true
+//
+// @Description:
+// @receiver a
+// @param prefix
+// @param now			当前时间，单位 ms
+// @param interval		探测间隔，单位 ms
+// @return bool
+//
+func (a *AsfMeasurements) TryStartProbe(prefix string, now uint64, interval uint64) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	ns := a.getOrCreateNamespace(prefix)
+	if ns.lastProbeTime+interval > now {
+		return false
+	}
+	ns.lastProbeTime = now
+	return true
+}
+
+// AddPendingProbe
+// 记录一个已经发出的探测兴趣包
+//
+// @Description:
+//  发送时间保存在探测路径的测量信息中，而不是依赖 PIT 条目的 out-record ：主路径的 data 满足并移除了 PIT 条目之后，探测路径晚到的
+//  data 依然可以通过 TakePendingProbe 找到发送时间并计算 RTT 。探测兴趣包的超时由策略在分片的堆定时器中调用 ExpirePendingProbe 处理。
+// @receiver a
+// @param prefix
+// @param name			探测兴趣包对应 PIT 条目的标识
+// @param logicFaceId	探测兴趣包发往的 LogicFace
+// @param sendTime		发送时间，单位 ms
+//
+func (a *AsfMeasurements) AddPendingProbe(prefix string, name string, logicFaceId uint64, sendTime uint64) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.getOrCreateFace(prefix, logicFaceId).pendingProbes[name] = sendTime
+}
+
+// TakePendingProbe
+// 找到并移除一个可以被 data 满足的探测兴趣包的记录
+//
+// @Description:
+//  探测兴趣包的标识等于 data 的标识，或者是 data 的标识的前缀（ CanBePrefix ）时可以被满足，优先精确匹配
+// @receiver a
+// @param prefix
+// @param dataName		data 的标识
+// @param logicFaceId	收到 data 的 LogicFace
+// @return uint64		探测兴趣包的发送时间，单位 ms
+// @return bool			不存在对应的探测记录时返回 false
+//
+func (a *AsfMeasurements) TakePendingProbe(prefix string, dataName string, logicFaceId uint64) (uint64, bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	fm := a.getFace(prefix, logicFaceId)
+	if fm == nil {
+		return 0, false
+	}
+	if sendTime, ok := fm.pendingProbes[dataName]; ok {
+		delete(fm.pendingProbes, dataName)
+		return sendTime, true
+	}
+	for name, sendTime := range fm.pendingProbes {
+		if strings.HasPrefix(dataName, strings.TrimSuffix(name, "/")+"/") {
+			delete(fm.pendingProbes, name)
+			return sendTime, true
+		}
+	}
+	return 0, false
+}
+
+// RemovePendingProbe
+// 移除一个探测兴趣包的记录（收到了 Nack 或者已经由调用者记录了超时）
+//
+// @Description:
+// @receiver a
+// @param prefix
+// @param name
+// @param logicFaceId
+// @return bool			存在对应的探测记录时返回 true
+//
+func (a *AsfMeasurements) RemovePendingProbe(prefix string, name string, logicFaceId uint64) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	fm := a.getFace(prefix, logicFaceId)
+	if fm == nil {
+		return false
+	}
+	if _, ok := fm.pendingProbes[name]; !ok {
+		return false
+	}
+	delete(fm.pendingProbes, name)
+	return true
+}
+
+// ExpirePendingProbe
+// 将一个超时的探测兴趣包记为对应 LogicFace 的一次超时，并移除其记录
+//
+// @Description:
+//  只有发送时间一致时才处理，所以同一个标识之后发出的探测兴趣包不会被之前的超时事件误判
+// @receiver a
+// @param prefix
+// @param name
+// @param logicFaceId
+// @param sendTime		探测兴趣包的发送时间，单位 ms
+// @return bool			记录了一次超时时返回 true
+//
+func (a *AsfMeasurements) ExpirePendingProbe(prefix string, name string, logicFaceId uint64, sendTime uint64) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	fm := a.getFace(prefix, logicFaceId)
+	if fm == nil {
+		return false
+	}
+	if pendingSendTime, ok := fm.pendingProbes[name]; !ok || pendingSendTime != sendTime {
+		return false
+	}
+	delete(fm.pendingProbes, name)
+	fm.NTimeouts++
+	return true
+}
@@ -0,0 +1,339 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 11:20 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"github.com/sirupsen/logrus"
+	common2 "minlib/common"
+	"minlib/component"
+	"minlib/packet"
+	"mir-go/daemon/common"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/table"
+	"sort"
+	"strconv"
+)
+
+// AsfStrategyName 自适应转发策略的名字
+const AsfStrategyName = "/strategy/asf"
+
+const (
+	asfDefaultProbingInterval = 60000 // 默认的探测间隔，单位 ms
+	asfDefaultMaxTimeouts     = 3     // 连续超时达到该次数的 LogicFace 会被排到最后
+)
+
+// AsfStrategy
+// 自适应转发策略（ASF-style）实现
+//
+// @Description:
+//  1. 在收到 data 时根据 out-record 的发送时间计算 RTT，在 PIT 条目超时移除时记录超时，为每个（前缀，LogicFace）维护平滑 RTT；
+//  2. 转发 Interest 时优先选择测量结果最好的 LogicFace，没有测量结果时退化为按 Cost 选择；
+//  3. 每隔一个探测周期，额外往一个备选的 LogicFace 发送一份 Interest 的拷贝，以更新备选路径的测量结果。探测兴趣包的发送时间单独记录
+//     在备选路径的测量信息中：PIT 条目被主路径满足并移除之后，探测路径晚到的 data 会作为未经请求的 data 交给
+//     AfterReceiveUnsolicitedData ，依然可以计算 RTT ；在兴趣包生存期内没有返回的探测兴趣包由分片的堆定时器记为备选路径的一次超时。
+//
+type AsfStrategy struct {
+	StrategyBase
+	measurements    *AsfMeasurements // 测量表
+	probingInterval uint64           // 探测间隔，单位 ms
+	maxTimeouts     uint64           // 最大连续超时次数
+}
+
+// NewAsfStrategy
+// 新建一个自适应转发策略
+//
+// @Description:
+// @return *AsfStrategy
+//
+func NewAsfStrategy() *AsfStrategy {
+	return &AsfStrategy{
+		measurements:    NewAsfMeasurements(),
+		probingInterval: asfDefaultProbingInterval,
+		maxTimeouts:     asfDefaultMaxTimeouts,
+	}
+}
+
+// SetProbingInterval
+// 设置探测间隔
+//
+// @Description:
+// @receiver as
+// @param interval		单位 ms
+//
+func (as *AsfStrategy) SetProbingInterval(interval uint64) {
+	as.probingInterval = interval
+}
+
+// SetMaxTimeouts
+// 设置最大连续超时次数
+//
+// @Description:
+// @receiver as
+// @param maxTimeouts
+//
+func (as *AsfStrategy) SetMaxTimeouts(maxTimeouts uint64) {
+	as.maxTimeouts = maxTimeouts
+}
+
+// GetMeasurements
+// 获取测量表
+//
+// @Description:
+// @receiver as
+// @return *AsfMeasurements
+//
+func (as *AsfStrategy) GetMeasurements() *AsfMeasurements {
+	return as.measurements
+}
+
+//
+// 对所有可用的下一跳进行排序，排在越前面的下一跳越好
+//
+// @Description:
+//  排序规则：
+//   1. 连续超时次数未达到上限且有 RTT 测量结果的下一跳排在最前面，按平滑 RTT 从小到大排序；
+//   2. 接着是还没有测量结果的下一跳，按 Cost 从小到大排序；
+//   3. 连续超时次数达到上限的下一跳排在最后，按 Cost 从小到大排序。
+// @receiver as
+// @param ingress
+// @param fibEntry
+// @return []*table.NextHop
+//
+func (as *AsfStrategy) rankNextHops(ingress *lf.LogicFace, fibEntry *table.FIBEntry) []*table.NextHop {
+	type rankedNextHop struct {
+		nextHop *table.NextHop
+		class   int
+		srtt    float64
+	}
+
+	ranked := make([]rankedNextHop, 0)
+	prefix := fibEntry.GetIdentifier().ToUri()
+	for _, nextHop := range fibEntry.GetNextHops() {
+		if nextHop.LogicFace.LogicFaceId == ingress.LogicFaceId {
+			continue
+		}
+		item := rankedNextHop{nextHop: nextHop, class: 1}
+		if fm, ok := as.measurements.GetFaceMeasurement(prefix, nextHop.LogicFace.LogicFaceId); ok {
+			if fm.NTimeouts >= as.maxTimeouts {
+				item.class = 2
+			} else if fm.HasRtt() {
+				item.class = 0
+				item.srtt = fm.SRtt
+			}
+		}
+		ranked = append(ranked, item)
+	}
+
+	// GetNextHops 返回的下一跳已经按 Cost 排好序，这边使用稳定排序保持同一类别内部的 Cost 顺序
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].class != ranked[j].class {
+			return ranked[i].class < ranked[j].class
+		}
+		if ranked[i].class == 0 {
+			return ranked[i].srtt < ranked[j].srtt
+		}
+		return false
+	})
+
+	nextHops := make([]*table.NextHop, len(ranked))
+	for i, item := range ranked {
+		nextHops[i] = item.nextHop
+	}
+	return nextHops
+}
+
+//
+// 从备选的下一跳中选出一个用于探测的下一跳
+//
+// @Description:
+//  优先选择还没有测量结果的下一跳，否则选择排名紧随其后的下一跳
+// @receiver as
+// @param prefix
+// @param rankedNextHops		排好序的下一跳，第一个为正在使用的下一跳
+// @return *table.NextHop
+//
+func (as *AsfStrategy) findProbeNextHop(prefix string, rankedNextHops []*table.NextHop) *table.NextHop {
+	if len(rankedNextHops) < 2 {
+		return nil
+	}
+	for _, nextHop := range rankedNextHops[1:] {
+		if fm, ok := as.measurements.GetFaceMeasurement(prefix, nextHop.LogicFace.LogicFaceId); !ok || !fm.HasRtt() {
+			return nextHop
+		}
+	}
+	return rankedNextHops[1]
+}
+
+//
+// 记录并发出一个探测兴趣包，并在分片的堆定时器中设置其超时事件
+//
+// @Description:
+// @receiver as
+// @param prefix
+// @param probeFace		探测的下一跳
+// @param interest
+// @param pitEntry
+// @param now			当前时间，单位 ms
+//
+func (as *AsfStrategy) sendProbe(prefix string, probeFace *lf.LogicFace, interest *packet.Interest, pitEntry *table.PITEntry,
+	now uint64) {
+	common2.LogDebugWithFields(logrus.Fields{
+		"interest": interest.ToUri(),
+		"probe":    probeFace.LogicFaceId,
+	}, "Asf send probe interest")
+	name := pitEntry.GetIdentifier().ToUri()
+	logicFaceId := probeFace.LogicFaceId
+	as.measurements.AddPendingProbe(prefix, name, logicFaceId, now)
+	as.addTimeoutEvent(pitEntry, int64(interest.InterestLifeTime.GetInterestLifeTime()),
+		"asf-probe:"+strconv.FormatUint(logicFaceId, 10)+":"+name, func() {
+			as.measurements.ExpirePendingProbe(prefix, name, logicFaceId, now)
+		})
+	as.sendInterest(probeFace, interest, pitEntry)
+}
+
+func (as *AsfStrategy) AfterReceiveInterest(ingress *lf.LogicFace, interest *packet.Interest, pitEntry *table.PITEntry) {
+	now := common.GetCurrentTime()
+
+	// 首先判断是否是仍然在重传抑制窗口内的下游重传，是则不转发被聚合
+	retxResult := as.decideRetxSuppression(pitEntry)
+	if retxResult == RetxSuppressionSuppress {
+		return
+	}
+
+	fibEntry := as.lookupFibForInterest(interest)
+	var rankedNextHops []*table.NextHop
+	if fibEntry != nil {
+		rankedNextHops = as.rankNextHops(ingress, fibEntry)
+	}
+
+	if len(rankedNextHops) == 0 {
+		if retxResult != RetxSuppressionNew {
+			// 下游的重传找不到下一跳时，继续等待 pending 的上游
+			return
+		}
+		// 如果没有找到下一跳路由信息，直接返回一个原因为 no-route 的 Nack
+		var nh component.NackHeader
+		nh.SetNackReason(component.NackReasonNoRoute)
+		as.sendNack(ingress, &nh, pitEntry)
+
+		// 同时触发 PITEntry 移除
+		as.rejectPendingInterest(pitEntry)
+		return
+	}
+
+	// 将兴趣包转发到测量结果最好的下一跳
+	as.sendInterest(rankedNextHops[0].LogicFace, interest, pitEntry)
+
+	// 到了探测时间，则额外往一个备选的下一跳发送一份 Interest，用于测量备选路径
+	prefix := fibEntry.GetIdentifier().ToUri()
+	if probeNextHop := as.findProbeNextHop(prefix, rankedNextHops); probeNextHop != nil &&
+		as.measurements.TryStartProbe(prefix, now, as.probingInterval) {
+		as.sendProbe(prefix, probeNextHop.LogicFace, interest, pitEntry, now)
+	}
+}
+
+func (as *AsfStrategy) AfterReceiveData(ingress *lf.LogicFace, data *packet.Data, pitEntry *table.PITEntry) {
+	now := common.GetCurrentTime()
+
+	// 根据对应 out-record 的发送时间计算 RTT ，没有 out-record 时使用探测记录中的发送时间
+	if fibEntry := as.forwarder.FIB.FindLongestPrefixMatch(pitEntry.GetIdentifier()); fibEntry != nil {
+		prefix := fibEntry.GetIdentifier().ToUri()
+		probeSendTime, isProbe := as.measurements.TakePendingProbe(prefix, pitEntry.GetIdentifier().ToUri(), ingress.LogicFaceId)
+		if outRecord, err := pitEntry.GetOutRecord(ingress); err == nil && outRecord.SendTime > 0 {
+			as.measurements.AddRttSample(prefix, ingress.LogicFaceId, float64(now-outRecord.SendTime))
+		} else if isProbe {
+			as.measurements.AddRttSample(prefix, ingress.LogicFaceId, float64(now-probeSendTime))
+		}
+	}
+	as.StrategyBase.AfterReceiveData(ingress, data, pitEntry)
+}
+
+func (as *AsfStrategy) AfterReceiveUnsolicitedData(ingress *lf.LogicFace, data *packet.Data) {
+	// PIT 条目已经被主路径满足并移除，探测路径晚到的 data 依然根据探测记录中的发送时间计算 RTT
+	fibEntry := as.forwarder.FIB.FindLongestPrefixMatch(data.GetName())
+	if fibEntry == nil {
+		return
+	}
+	prefix := fibEntry.GetIdentifier().ToUri()
+	if sendTime, ok := as.measurements.TakePendingProbe(prefix, data.GetName().ToUri(), ingress.LogicFaceId); ok {
+		rtt := common.GetCurrentTime() - sendTime
+		common2.LogDebugWithFields(logrus.Fields{
+			"data":  data.ToUri(),
+			"probe": ingress.LogicFaceId,
+			"rtt":   rtt,
+		}, "Asf receive late probe data")
+		as.measurements.AddRttSample(prefix, ingress.LogicFaceId, float64(rtt))
+	}
+}
+
+func (as *AsfStrategy) AfterReceiveNack(ingress *lf.LogicFace, nack *packet.Nack, pitEntry *table.PITEntry) {
+	// 探测路径返回了 Nack ，不再作为超时统计
+	if fibEntry := as.forwarder.FIB.FindLongestPrefixMatch(pitEntry.GetIdentifier()); fibEntry != nil {
+		as.measurements.RemovePendingProbe(fibEntry.GetIdentifier().ToUri(), pitEntry.GetIdentifier().ToUri(), ingress.LogicFaceId)
+	}
+	as.processNack(ingress, pitEntry)
+}
+
+func (as *AsfStrategy) AfterReceiveGPPkt(ingress *lf.LogicFace, gPPkt *packet.GPPkt) {
+	fibEntry := as.lookupFibForGPPkt(gPPkt)
+	if fibEntry == nil {
+		// 没有路由无法转发
+		common2.LogDebug("No Route")
+		return
+	}
+	rankedNextHops := as.rankNextHops(ingress, fibEntry)
+	if len(rankedNextHops) == 0 {
+		common2.LogDebug("No Route")
+		return
+	}
+	as.sendGPPkt(rankedNextHops[0].LogicFace, gPPkt)
+}
+
+func (as *AsfStrategy) BeforeExpirePendingInterest(pitEntry *table.PITEntry) {
+	fibEntry := as.forwarder.FIB.FindLongestPrefixMatch(pitEntry.GetIdentifier())
+	if fibEntry == nil {
+		return
+	}
+	prefix := fibEntry.GetIdentifier().ToUri()
+	name := pitEntry.GetIdentifier().ToUri()
+	now := common.GetCurrentTime()
+	// 没有被 Nack 且已经过期的 out-record 都认为是超时，对应的探测记录在这里一并移除，避免重复统计
+	for _, outRecord := range pitEntry.GetOutRecords() {
+		as.measurements.RemovePendingProbe(prefix, name, outRecord.LogicFace.LogicFaceId)
+		if outRecord.NackHeader == nil && outRecord.ExpireTime <= now {
+			as.measurements.RecordTimeout(prefix, outRecord.LogicFace.LogicFaceId)
+		}
+	}
+}
+
+// OnFaceDown
+// LogicFace 被关闭时，移除其测量信息，并将失去上游的兴趣包转发到排名次高的下一跳
+//
+// @Description:
+// @receiver as
+// @param face
+// @param pitEntries
+//
+func (as *AsfStrategy) OnFaceDown(face *lf.LogicFace, pitEntries []*table.PITEntry) {
+	as.measurements.RemoveFace(face.LogicFaceId)
+	as.reforwardPendingInterests(pitEntries, as.AfterReceiveInterest)
+}
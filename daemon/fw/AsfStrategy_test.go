@@ -0,0 +1,227 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 10:05 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package fw
+
+import (
+	"fmt"
+	"minlib/component"
+	"minlib/packet"
+	"mir-go/daemon/common"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/table"
+	"testing"
+)
+
+func newAsfTestInterest(downstream *lf.LogicFace, name *component.Identifier) (*packet.Interest, *table.PITEntry) {
+	interest := new(packet.Interest)
+	interest.SetName(name)
+	interest.InterestLifeTime.SetInterestLifeTime(4000)
+	pitEntry := table.CreatePITEntry()
+	pitEntry.Identifier = name
+	pitEntry.InsertOrUpdateInRecord(downstream, interest).ExpireTime = common.GetCurrentTime() + 4000
+	return interest, pitEntry
+}
+
+func TestAsfStrategy_RankNextHops(t *testing.T) {
+	as := NewAsfStrategy()
+	ingress := &lf.LogicFace{LogicFaceId: 1}
+	fibEntry := table.CreateFIBEntry()
+	prefix, _ := component.CreateIdentifierByString("/min/asf")
+	fibEntry.SetIdentifier(prefix)
+	for i := uint64(1); i <= 5; i++ {
+		fibEntry.AddOrUpdateNextHop(&lf.LogicFace{LogicFaceId: i}, i)
+	}
+	as.measurements.AddRttSample(prefix.ToUri(), 2, 50)
+	as.measurements.AddRttSample(prefix.ToUri(), 3, 10)
+	for i := uint64(0); i < asfDefaultMaxTimeouts; i++ {
+		as.measurements.RecordTimeout(prefix.ToUri(), 5)
+	}
+
+	// 有 RTT 的按 RTT 排序 => 没有测量结果的按 Cost 排序 => 连续超时达到上限的排在最后，入口 LogicFace 被排除
+	ranked := as.rankNextHops(ingress, fibEntry)
+	ids := make([]uint64, len(ranked))
+	for i, nextHop := range ranked {
+		ids[i] = nextHop.LogicFace.LogicFaceId
+	}
+	fmt.Println("ranked =>", ids)
+	if fmt.Sprint(ids) != "[3 2 4 5]" {
+		t.Fatal("unexpected rank", ids)
+	}
+
+	// 收到 data 之后连续超时次数清零
+	as.measurements.AddRttSample(prefix.ToUri(), 5, 1)
+	if fm, _ := as.measurements.GetFaceMeasurement(prefix.ToUri(), 5); fm.NTimeouts != 0 {
+		t.Fatal("expect timeouts reset after rtt sample, got", fm.NTimeouts)
+	}
+	if ranked = as.rankNextHops(ingress, fibEntry); ranked[0].LogicFace.LogicFaceId != 5 {
+		t.Fatal("expect face 5 ranked first, got", ranked[0].LogicFace.LogicFaceId)
+	}
+}
+
+func TestAsfStrategy_Probing(t *testing.T) {
+	forwarder, recorder := newRecordingTestForwarder(t)
+	as := NewAsfStrategy()
+	as.SetForwarder(forwarder)
+	name, _ := component.CreateIdentifierByString("/min/asf")
+	downstream := &lf.LogicFace{LogicFaceId: 1}
+	for _, face := range []*lf.LogicFace{downstream, {LogicFaceId: 2}, {LogicFaceId: 3}} {
+		forwarder.FIB.AddOrUpdate(name, face, 0)
+	}
+	as.measurements.AddRttSample(name.ToUri(), 2, 10)
+
+	// 第一个兴趣包转发到测量结果最好的 2 号，同时往没有测量结果的 3 号发送探测
+	interest, pitEntry := newAsfTestInterest(downstream, name)
+	as.AfterReceiveInterest(downstream, interest, pitEntry)
+	fmt.Println("first interest =>", recorder.interestFaces)
+	if fmt.Sprint(recorder.interestFaces) != "[2 3]" {
+		t.Fatal("expect primary 2 and probe 3, got", recorder.interestFaces)
+	}
+
+	// 探测间隔之内不再探测
+	recorder.interestFaces = nil
+	interest, pitEntry = newAsfTestInterest(downstream, name)
+	as.AfterReceiveInterest(downstream, interest, pitEntry)
+	if fmt.Sprint(recorder.interestFaces) != "[2]" {
+		t.Fatal("expect no probe within probing interval, got", recorder.interestFaces)
+	}
+
+	// 探测路径返回了 data => 探测记录被移除，并记录 RTT
+	as.SetProbingInterval(0)
+	recorder.interestFaces = nil
+	interest, pitEntry = newAsfTestInterest(downstream, name)
+	as.AfterReceiveInterest(downstream, interest, pitEntry)
+	probeRecord := pitEntry.InsertOrUpdateOutRecord(&lf.LogicFace{LogicFaceId: 3}, interest)
+	probeRecord.SendTime = common.GetCurrentTime() - 5
+	data := new(packet.Data)
+	data.SetName(name)
+	as.AfterReceiveData(&lf.LogicFace{LogicFaceId: 3}, data, pitEntry)
+	if as.measurements.RemovePendingProbe(name.ToUri(), name.ToUri(), 3) {
+		t.Fatal("expect answered probe removed")
+	}
+	if fm, ok := as.measurements.GetFaceMeasurement(name.ToUri(), 3); !ok || !fm.HasRtt() {
+		t.Fatal("expect rtt measured on probe face")
+	}
+	if fmt.Sprint(recorder.dataFaces) != "[1]" {
+		t.Fatal("expect data sent to downstream, got", recorder.dataFaces)
+	}
+}
+
+func TestAsfStrategy_ProbeTimeout(t *testing.T) {
+	forwarder, recorder := newRecordingTestForwarder(t)
+	as := NewAsfStrategy()
+	as.SetForwarder(forwarder)
+	as.SetProbingInterval(0)
+	as.SetMaxTimeouts(2)
+	name, _ := component.CreateIdentifierByString("/min/asf")
+	downstream := &lf.LogicFace{LogicFaceId: 1}
+	primary, probe := &lf.LogicFace{LogicFaceId: 2}, &lf.LogicFace{LogicFaceId: 3}
+	for _, face := range []*lf.LogicFace{downstream, primary, probe} {
+		forwarder.FIB.AddOrUpdate(name, face, 0)
+	}
+	as.measurements.AddRttSample(name.ToUri(), primary.LogicFaceId, 10)
+	as.measurements.AddRttSample(name.ToUri(), probe.LogicFaceId, 20)
+
+	for i := 0; i < 2; i++ {
+		// 主路径返回 data 满足了 PIT 条目，探测路径一直没有返回 => BeforeExpirePendingInterest 不会被触发
+		// 兴趣包生存期为0，探测兴趣包的超时事件在分片的堆定时器中立即到期
+		recorder.interestFaces = nil
+		interest, pitEntry := newAsfTestInterest(downstream, name)
+		interest.InterestLifeTime.SetInterestLifeTime(0)
+		as.AfterReceiveInterest(downstream, interest, pitEntry)
+		if fmt.Sprint(recorder.interestFaces) != "[2 3]" {
+			t.Fatal("expect primary 2 and probe 3, got", recorder.interestFaces)
+		}
+		pitEntry.InsertOrUpdateOutRecord(primary, interest).SendTime = common.GetCurrentTime()
+		data := new(packet.Data)
+		data.SetName(name)
+		as.AfterReceiveData(primary, data, pitEntry)
+
+		// 探测兴趣包超时之后依然记为探测路径的一次超时
+		forwarder.shardTimer(name).DealEvent()
+		if fm, _ := as.measurements.GetFaceMeasurement(name.ToUri(), probe.LogicFaceId); fm.NTimeouts != uint64(i+1) {
+			t.Fatal("expect probe timeout recorded, got", fm.NTimeouts)
+		}
+	}
+	fm, _ := as.measurements.GetFaceMeasurement(name.ToUri(), probe.LogicFaceId)
+	fmt.Println("probe face timeouts =>", fm.NTimeouts)
+	if fm.NTimeouts != 2 {
+		t.Fatal("expect 2 timeouts on probe face, got", fm.NTimeouts)
+	}
+
+	// 连续超时达到上限的探测路径被排到最后
+	ranked := as.rankNextHops(downstream, forwarder.FIB.FindExactMatch(name))
+	if ranked[len(ranked)-1].LogicFace.LogicFaceId != probe.LogicFaceId {
+		t.Fatal("expect probe face ranked last")
+	}
+
+	// 未被满足的 PIT 条目超时移除时，探测 out-record 只统计一次超时
+	interest, pitEntry := newAsfTestInterest(downstream, name)
+	as.AfterReceiveInterest(downstream, interest, pitEntry)
+	pitEntry.InsertOrUpdateOutRecord(probe, interest).ExpireTime = 0
+	as.BeforeExpirePendingInterest(pitEntry)
+	fm, _ = as.measurements.GetFaceMeasurement(name.ToUri(), probe.LogicFaceId)
+	if fm.NTimeouts != 3 {
+		t.Fatal("expect probe timeout counted once, got", fm.NTimeouts)
+	}
+}
+
+func TestAsfStrategy_LateProbeData(t *testing.T) {
+	forwarder, recorder := newRecordingTestForwarder(t)
+	as := NewAsfStrategy()
+	as.SetForwarder(forwarder)
+	as.SetProbingInterval(0)
+	name, _ := component.CreateIdentifierByString("/min/asf")
+	forwarder.StrategyTable.Insert(name, AsfStrategyName, as)
+	downstream := &lf.LogicFace{LogicFaceId: 1}
+	primary, probe := &lf.LogicFace{LogicFaceId: 2}, &lf.LogicFace{LogicFaceId: 3}
+	for _, face := range []*lf.LogicFace{downstream, primary, probe} {
+		forwarder.FIB.AddOrUpdate(name, face, 0)
+	}
+	as.measurements.AddRttSample(name.ToUri(), primary.LogicFaceId, 10)
+
+	// 主路径的 data 满足了 PIT 条目
+	interest, pitEntry := newAsfTestInterest(downstream, name)
+	interest.InterestLifeTime.SetInterestLifeTime(0)
+	as.AfterReceiveInterest(downstream, interest, pitEntry)
+	if fmt.Sprint(recorder.interestFaces) != "[2 3]" {
+		t.Fatal("expect primary 2 and probe 3, got", recorder.interestFaces)
+	}
+	pitEntry.InsertOrUpdateOutRecord(primary, interest).SendTime = common.GetCurrentTime()
+	data := new(packet.Data)
+	data.SetName(name)
+	as.AfterReceiveData(primary, data, pitEntry)
+
+	// PIT 条目移除之后探测路径才返回 data => 经过 data unsolicited 管道，依然记录探测路径的 RTT
+	lateData := new(packet.Data)
+	lateData.SetName(name)
+	forwarder.OnDataUnsolicited(probe, lateData)
+	fm, ok := as.measurements.GetFaceMeasurement(name.ToUri(), probe.LogicFaceId)
+	if !ok || !fm.HasRtt() {
+		t.Fatal("expect rtt measured from late probe data")
+	}
+
+	// 已经返回的探测兴趣包不会再被记为超时
+	forwarder.shardTimer(name).DealEvent()
+	if fm, _ = as.measurements.GetFaceMeasurement(name.ToUri(), probe.LogicFaceId); fm.NTimeouts != 0 {
+		t.Fatal("expect no timeout for answered probe, got", fm.NTimeouts)
+	}
+}
@@ -22,7 +22,6 @@
 package fw
 
 import (
-	"github.com/sirupsen/logrus"
 	common2 "minlib/common"
 	"minlib/component"
 	"minlib/packet"
@@ -30,6 +29,9 @@ import (
 	"mir-go/daemon/table"
 )
 
+// BestRouteStrategyName 最佳路由转发策略的名字
+const BestRouteStrategyName = "/strategy/best-route"
+
 // BestRouteStrategy
 // 最佳路由转发策略实现
 //
@@ -63,14 +65,9 @@ func (brs *BestRouteStrategy) findLowestCostNextHop(ingress *lf.LogicFace, fibEn
 }
 
 func (brs *BestRouteStrategy) AfterReceiveInterest(ingress *lf.LogicFace, interest *packet.Interest, pitEntry *table.PITEntry) {
-	// 首先判断是否有正在pending的 out-record
-	if HasPendingOutRecords(pitEntry) {
-		// 不是新的 Interest ，不转发被聚合
-		common2.LogDebugWithFields(logrus.Fields{
-			"ingress":  ingress.LogicFaceId,
-			"interest": interest.ToUri(),
-			"pitEntry": pitEntry.Identifier.ToUri(),
-		}, "PITEntry already has pending interest, drop")
+	// 首先判断是否是仍然在重传抑制窗口内的下游重传，是则不转发被聚合
+	retxResult := brs.decideRetxSuppression(pitEntry)
+	if retxResult == RetxSuppressionSuppress {
 		return
 	}
 
@@ -81,6 +78,10 @@ func (brs *BestRouteStrategy) AfterReceiveInterest(ingress *lf.LogicFace, intere
 	miniHop := brs.findLowestCostNextHop(ingress, fibEntry)
 
 	if miniHop == nil {
+		if retxResult != RetxSuppressionNew {
+			// 下游的重传找不到下一跳时，继续等待 pending 的上游
+			return
+		}
 		// 如果没有找到下一跳路由信息，直接返回一个原因为 no-route 的 Nack
 		var nh component.NackHeader
 		nh.SetNackReason(component.NackReasonNoRoute)
@@ -91,37 +92,12 @@ func (brs *BestRouteStrategy) AfterReceiveInterest(ingress *lf.LogicFace, intere
 		return
 	}
 
-	// 将兴趣包转发到可用的下一跳
+	// 将兴趣包转发到可用的下一跳，下游的重传同样转发到开销最小的下一跳，以便从上游的丢包中恢复
 	brs.sendInterest(miniHop.LogicFace, interest, pitEntry)
 }
 
 func (brs *BestRouteStrategy) AfterReceiveNack(ingress *lf.LogicFace, nack *packet.Nack, pitEntry *table.PITEntry) {
-	// 保存最不严重的 Nack 原因
-	leastSevereReason := component.NackReasonUnknown
-	// 保存没有被 Nack 的出记录的数量
-	notNackedOutRecordNums := 0
-
-	// 在 Strategy.AfterReceiveNack 之前，OnIncomingNack 管道已经将 Nack 头部信息保存到了对应的 out-record 里面
-	// 所以下面的步骤肯定至少能从一个 out-record 中得到 NackHeader
-	for _, outRecord := range pitEntry.GetOutRecords() {
-		if outRecord.NackHeader != nil {
-			if int(outRecord.NackHeader.GetNackReason()) > leastSevereReason {
-				leastSevereReason = int(outRecord.NackHeader.GetNackReason())
-			}
-		} else {
-			notNackedOutRecordNums++
-		}
-	}
-
-	// 如果还有 out-record 没有被 Nack，则不转发nack，等待其它上游返回的nack
-	if notNackedOutRecordNums > 0 {
-		return
-	}
-
-	var nh component.NackHeader
-	nh.SetNackReason(uint64(leastSevereReason))
-
-	brs.sendNackToAll(ingress, &nh, pitEntry)
+	brs.processNack(ingress, pitEntry)
 }
 
 func (brs *BestRouteStrategy) AfterReceiveGPPkt(ingress *lf.LogicFace, gPPkt *packet.GPPkt) {
@@ -134,3 +110,17 @@ func (brs *BestRouteStrategy) AfterReceiveGPPkt(ingress *lf.LogicFace, gPPkt *pa
 	}
 	brs.sendGPPkt(miniHop.LogicFace, gPPkt)
 }
+
+// OnFaceDown
+// LogicFace 被关闭时，将失去上游的兴趣包立即转发到开销次小的下一跳
+//
+// @Description:
+//  此时 FIB 中指向被关闭 LogicFace 的下一跳已经被清除，重新执行一遍 AfterReceiveInterest 即可选出新的下一跳，没有可用的下一跳时
+//  会向下游返回 no-route 的 Nack 。
+// @receiver brs
+// @param face
+// @param pitEntries
+//
+func (brs *BestRouteStrategy) OnFaceDown(face *lf.LogicFace, pitEntries []*table.PITEntry) {
+	brs.reforwardPendingInterests(pitEntries, brs.AfterReceiveInterest)
+}
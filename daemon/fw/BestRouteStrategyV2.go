@@ -0,0 +1,253 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 11:40 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"github.com/sirupsen/logrus"
+	common2 "minlib/common"
+	"minlib/component"
+	"minlib/packet"
+	"mir-go/daemon/common"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/table"
+	"sort"
+)
+
+// bestRouteV2Info
+// best-route v=2 保存在 PIT 条目上的状态
+//
+// @Description:
+//
+type bestRouteV2Info struct {
+	RetxSuppressionInfo                 // 重传抑制的状态
+	triedFaces          map[uint64]bool // 已经尝试过的上游 LogicFaceId
+}
+
+// BestRouteStrategyV2
+// 最佳路由转发策略（v=2）实现
+//
+// @Description:
+//  在 v=1 的基础上增加了往备选下一跳的重试：
+//   1. 为每个 PIT 条目记录已经尝试过的下一跳；
+//   2. 收到上游的 Nack 时，立即将兴趣包转发到开销次小的、还没有尝试过的下一跳，所有下一跳都尝试过之后才向下游返回最不严重的 Nack ；
+//   3. 上游仍然 pending 时收到下游重传的兴趣包，由 StrategyBase 的重传抑制决定是否转发，需要转发时转发到下一个还没有尝试过的
+//      下一跳（都尝试过时转发到最久没有使用的下一跳）。
+//
+type BestRouteStrategyV2 struct {
+	BestRouteStrategy
+}
+
+// NewBestRouteStrategyV2
+// 新建一个最佳路由转发策略（v=2）
+//
+// @Description:
+// @return *BestRouteStrategyV2
+//
+func NewBestRouteStrategyV2() *BestRouteStrategyV2 {
+	return &BestRouteStrategyV2{}
+}
+
+//
+// 获取 PIT 条目上保存的状态，不存在时新建一个
+//
+// @Description:
+// @receiver brs
+// @param pitEntry
+// @return *bestRouteV2Info
+//
+func (brs *BestRouteStrategyV2) getInfo(pitEntry *table.PITEntry) *bestRouteV2Info {
+	if info, ok := pitEntry.GetStrategyInfo().(*bestRouteV2Info); ok {
+		return info
+	}
+	info := &bestRouteV2Info{triedFaces: make(map[uint64]bool)}
+	pitEntry.SetStrategyInfo(info)
+	return info
+}
+
+//
+// 按开销从小到大列出所有可以用来转发的下一跳
+//
+// @Description:
+//  排除所有下游（即 PIT 条目中存在 in-record 的 LogicFace ），开销相同时按 LogicFaceId 排序，保证结果是确定的
+// @receiver brs
+// @param fibEntry
+// @param pitEntry
+// @return []*table.NextHop
+//
+func (brs *BestRouteStrategyV2) eligibleNextHops(fibEntry *table.FIBEntry, pitEntry *table.PITEntry) []*table.NextHop {
+	nextHops := make([]*table.NextHop, 0)
+	if fibEntry == nil {
+		return nextHops
+	}
+	for _, nextHop := range fibEntry.GetNextHops() {
+		if _, err := pitEntry.GetInRecord(nextHop.LogicFace); err == nil {
+			continue
+		}
+		nextHops = append(nextHops, nextHop)
+	}
+	sort.SliceStable(nextHops, func(i, j int) bool {
+		if nextHops[i].Cost != nextHops[j].Cost {
+			return nextHops[i].Cost < nextHops[j].Cost
+		}
+		return nextHops[i].LogicFace.LogicFaceId < nextHops[j].LogicFace.LogicFaceId
+	})
+	return nextHops
+}
+
+//
+// 找到开销最小的、还没有尝试过的下一跳
+//
+// @Description:
+// @param nextHops		按开销从小到大排序的下一跳
+// @param info
+// @return *table.NextHop		都已经尝试过时返回 nil
+//
+func findFirstUntriedNextHop(nextHops []*table.NextHop, info *bestRouteV2Info) *table.NextHop {
+	for _, nextHop := range nextHops {
+		if !info.triedFaces[nextHop.LogicFace.LogicFaceId] {
+			return nextHop
+		}
+	}
+	return nil
+}
+
+//
+// 为下游的重传选择一个下一跳
+//
+// @Description:
+//  优先选择还没有尝试过的下一跳；都尝试过时选择最久没有转发过的下一跳，以便从上游的丢包中恢复
+// @param nextHops		按开销从小到大排序的下一跳，不能为空
+// @param info
+// @param pitEntry
+// @return *table.NextHop
+//
+func selectRetxNextHop(nextHops []*table.NextHop, info *bestRouteV2Info, pitEntry *table.PITEntry) *table.NextHop {
+	if nextHop := findFirstUntriedNextHop(nextHops, info); nextHop != nil {
+		return nextHop
+	}
+	var selected *table.NextHop
+	selectedSendTime := uint64(0)
+	for _, nextHop := range nextHops {
+		sendTime := uint64(0)
+		if outRecord, err := pitEntry.GetOutRecord(nextHop.LogicFace); err == nil {
+			sendTime = outRecord.SendTime
+		}
+		if selected == nil || sendTime < selectedSendTime {
+			selected = nextHop
+			selectedSendTime = sendTime
+		}
+	}
+	return selected
+}
+
+//
+// 将兴趣包转发到指定的下一跳，并记录到 PIT 条目的状态中
+//
+// @Description:
+// @receiver brs
+// @param nextHop
+// @param interest
+// @param pitEntry
+// @param info
+//
+func (brs *BestRouteStrategyV2) forwardTo(nextHop *table.NextHop, interest *packet.Interest, pitEntry *table.PITEntry,
+	info *bestRouteV2Info) {
+	info.triedFaces[nextHop.LogicFace.LogicFaceId] = true
+	brs.sendInterest(nextHop.LogicFace, interest, pitEntry)
+}
+
+func (brs *BestRouteStrategyV2) AfterReceiveInterest(ingress *lf.LogicFace, interest *packet.Interest, pitEntry *table.PITEntry) {
+	// 先在 PIT 条目上保存自己的状态，重传抑制和策略共用这份状态
+	info := brs.getInfo(pitEntry)
+	retxResult := brs.decideRetxSuppression(pitEntry)
+	if retxResult == RetxSuppressionSuppress {
+		return
+	}
+	nextHops := brs.eligibleNextHops(brs.lookupFibForInterest(interest), pitEntry)
+
+	if len(nextHops) == 0 {
+		if retxResult != RetxSuppressionNew {
+			// 没有其它可用的下一跳，继续等待 pending 的上游
+			return
+		}
+		// 如果没有找到下一跳路由信息，直接返回一个原因为 no-route 的 Nack
+		var nh component.NackHeader
+		nh.SetNackReason(component.NackReasonNoRoute)
+		brs.sendNack(ingress, &nh, pitEntry)
+
+		// 同时触发 PITEntry 移除
+		brs.rejectPendingInterest(pitEntry)
+		return
+	}
+
+	if retxResult == RetxSuppressionForward {
+		// 上游仍然 pending ，并且已经超过了重传抑制窗口的下游重传
+		brs.forwardTo(selectRetxNextHop(nextHops, info, pitEntry), interest, pitEntry, info)
+		return
+	}
+
+	// 没有 pending 的上游：新的兴趣包，或者之前尝试过的上游都已经 Nack 或者超时
+	nextHop := findFirstUntriedNextHop(nextHops, info)
+	if nextHop == nil {
+		nextHop = nextHops[0]
+	}
+	brs.forwardTo(nextHop, interest, pitEntry, info)
+}
+
+func (brs *BestRouteStrategyV2) AfterReceiveNack(ingress *lf.LogicFace, nack *packet.Nack, pitEntry *table.PITEntry) {
+	now := common.GetCurrentTime()
+	info := brs.getInfo(pitEntry)
+	nextHops := brs.eligibleNextHops(brs.forwarder.FIB.FindLongestPrefixMatch(pitEntry.GetIdentifier()), pitEntry)
+	nextHop := findFirstUntriedNextHop(nextHops, info)
+
+	// 重试时使用下游发来的兴趣包，而不是上游返回的 Nack 中携带的兴趣包
+	latest := FindLatestInRecord(pitEntry, now)
+
+	if nextHop == nil || latest == nil {
+		// 所有的下一跳都已经尝试过，向下游返回最不严重的 Nack
+		brs.processNack(ingress, pitEntry)
+		return
+	}
+
+	common2.LogDebugWithFields(logrus.Fields{
+		"ingress":  ingress.LogicFaceId,
+		"reason":   nack.GetNackReason(),
+		"pitEntry": pitEntry.GetIdentifier().ToUri(),
+		"retry":    nextHop.LogicFace.LogicFaceId,
+	}, "Retry nacked interest on alternate next hop")
+	brs.forwardTo(nextHop, latest.Interest, pitEntry, info)
+	// 所有 out-record 都被 Nack 或者超时的时候， Incoming Nack 管道已经把 PIT 条目设置为立即过期，
+	// 重试之后将其存活期延长到下游的兴趣包过期为止
+	brs.setExpiryTimer(pitEntry, int64(latest.ExpireTime-now))
+}
+
+// OnFaceDown
+// LogicFace 被关闭时，将失去上游的兴趣包立即转发到下一个还没有尝试过的下一跳
+//
+// @Description:
+// @receiver brs
+// @param face
+// @param pitEntries
+//
+func (brs *BestRouteStrategyV2) OnFaceDown(face *lf.LogicFace, pitEntries []*table.PITEntry) {
+	brs.reforwardPendingInterests(pitEntries, brs.AfterReceiveInterest)
+}
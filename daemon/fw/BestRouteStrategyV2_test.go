@@ -0,0 +1,173 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 11:40 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package fw
+
+import (
+	"fmt"
+	"minlib/component"
+	"minlib/packet"
+	"mir-go/daemon/common"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/table"
+	"testing"
+)
+
+func TestBestRouteStrategyV2_NextHopOrder(t *testing.T) {
+	brs := NewBestRouteStrategyV2()
+	downstream := &lf.LogicFace{LogicFaceId: 1}
+	fibEntry := table.CreateFIBEntry()
+	fibEntry.AddOrUpdateNextHop(downstream, 0)
+	fibEntry.AddOrUpdateNextHop(&lf.LogicFace{LogicFaceId: 4}, 20)
+	fibEntry.AddOrUpdateNextHop(&lf.LogicFace{LogicFaceId: 3}, 10)
+	fibEntry.AddOrUpdateNextHop(&lf.LogicFace{LogicFaceId: 2}, 10)
+	pitEntry := table.CreatePITEntry()
+	pitEntry.InRecordList[downstream.LogicFaceId] = &table.InRecord{LogicFace: downstream}
+
+	// 排除下游，开销相同时按 LogicFaceId 排序 => 2, 3, 4
+	nextHops := brs.eligibleNextHops(fibEntry, pitEntry)
+	expected := []uint64{2, 3, 4}
+	if len(nextHops) != len(expected) {
+		t.Fatal("expect", len(expected), "next hops, got", len(nextHops))
+	}
+	for i, nextHop := range nextHops {
+		if nextHop.LogicFace.LogicFaceId != expected[i] {
+			t.Fatal("unexpected next hop order at", i, nextHop.LogicFace.LogicFaceId)
+		}
+	}
+
+	// 依次尝试每个下一跳，都尝试过之后返回 nil
+	info := brs.getInfo(pitEntry)
+	for _, id := range expected {
+		nextHop := findFirstUntriedNextHop(nextHops, info)
+		if nextHop == nil || nextHop.LogicFace.LogicFaceId != id {
+			t.Fatal("expect untried next hop", id)
+		}
+		info.triedFaces[id] = true
+	}
+	if findFirstUntriedNextHop(nextHops, info) != nil {
+		t.Fatal("expect all next hops tried")
+	}
+	fmt.Println(info.triedFaces)
+}
+
+func TestBestRouteStrategyV2_AfterReceiveNack(t *testing.T) {
+	forwarder, recorder := newRecordingTestForwarder(t)
+	brs := NewBestRouteStrategyV2()
+	brs.SetForwarder(forwarder)
+	name, _ := component.CreateIdentifierByString("/min/best-route")
+	downstream := &lf.LogicFace{LogicFaceId: 1}
+	upstream2, upstream3 := &lf.LogicFace{LogicFaceId: 2}, &lf.LogicFace{LogicFaceId: 3}
+	forwarder.FIB.AddOrUpdate(name, downstream, 0)
+	forwarder.FIB.AddOrUpdate(name, upstream2, 10)
+	forwarder.FIB.AddOrUpdate(name, upstream3, 20)
+	interest := new(packet.Interest)
+	interest.SetName(name)
+	interest.InterestLifeTime.SetInterestLifeTime(4000)
+	pitEntry := table.CreatePITEntry()
+	pitEntry.Identifier = name
+	pitEntry.InsertOrUpdateInRecord(downstream, interest).ExpireTime = common.GetCurrentTime() + 4000
+
+	// 新的兴趣包转发到开销最小的 2 号
+	brs.AfterReceiveInterest(downstream, interest, pitEntry)
+	if fmt.Sprint(recorder.interestFaces) != "[2]" {
+		t.Fatal("expect interest sent to face 2, got", recorder.interestFaces)
+	}
+
+	// 2 号返回 Nack => 立即重试还没有尝试过的 3 号，不向下游返回 Nack
+	nack := new(packet.Nack)
+	nack.Interest = interest
+	nack.SetNackReason(component.NackReasonNoRoute)
+	pitEntry.InsertOrUpdateOutRecord(upstream2, interest).NackHeader = &nack.Interest.NackHeader
+	brs.AfterReceiveNack(upstream2, nack, pitEntry)
+	fmt.Println("after first nack =>", recorder.interestFaces, recorder.nackFaces)
+	if fmt.Sprint(recorder.interestFaces) != "[2 3]" || len(recorder.nackFaces) != 0 {
+		t.Fatal("expect retry on face 3 without nacking downstream, got", recorder.interestFaces, recorder.nackFaces)
+	}
+
+	// 3 号也返回 Nack => 所有下一跳都尝试过，向下游返回最不严重的 Nack
+	var nh component.NackHeader
+	nh.SetNackReason(component.NackReasonDuplicate)
+	pitEntry.InsertOrUpdateOutRecord(upstream3, interest).NackHeader = &nh
+	brs.AfterReceiveNack(upstream3, nack, pitEntry)
+	expectedReason := uint64(component.NackReasonNoRoute)
+	if uint64(component.NackReasonDuplicate) > expectedReason {
+		expectedReason = uint64(component.NackReasonDuplicate)
+	}
+	fmt.Println("after last nack =>", recorder.interestFaces, recorder.nackFaces, recorder.nackReasons)
+	if fmt.Sprint(recorder.interestFaces) != "[2 3]" {
+		t.Fatal("expect no more retries, got", recorder.interestFaces)
+	}
+	if fmt.Sprint(recorder.nackFaces) != "[1]" || recorder.nackReasons[0] != expectedReason {
+		t.Fatal("expect final nack to downstream, got", recorder.nackFaces, recorder.nackReasons)
+	}
+}
+
+func TestBestRouteStrategyV2_IncomingNackPipeline(t *testing.T) {
+	forwarder, recorder := newRecordingTestForwarder(t)
+	brs := NewBestRouteStrategyV2()
+	brs.SetForwarder(forwarder)
+	name, _ := component.CreateIdentifierByString("/min/best-route")
+	forwarder.StrategyTable.Insert(name, BestRouteStrategyName, brs)
+	downstream := &lf.LogicFace{LogicFaceId: 1}
+	upstream2, upstream3 := &lf.LogicFace{LogicFaceId: 2}, &lf.LogicFace{LogicFaceId: 3}
+	forwarder.FIB.AddOrUpdate(name, downstream, 0)
+	forwarder.FIB.AddOrUpdate(name, upstream2, 10)
+	forwarder.FIB.AddOrUpdate(name, upstream3, 20)
+	interest := new(packet.Interest)
+	interest.SetName(name)
+	interest.InterestLifeTime.SetInterestLifeTime(4000)
+	pitEntry := forwarder.PIT.Insert(interest)
+	pitEntry.InsertOrUpdateInRecord(downstream, interest).ExpireTime = common.GetCurrentTime() + 4000
+
+	// 测试插件拦截了 Outgoing Interest 管道，这里手动插入 out-record
+	forward := func(upstream *lf.LogicFace) {
+		pitEntry.InsertOrUpdateOutRecord(upstream, interest).ExpireTime = common.GetCurrentTime() + 4000
+	}
+	brs.AfterReceiveInterest(downstream, interest, pitEntry)
+	forward(upstream2)
+
+	// 2 号返回 Nack => 重试 3 号，PIT 条目的存活期被延长，不会被立即移除
+	nack := new(packet.Nack)
+	nack.Interest = interest
+	nack.SetNackReason(component.NackReasonNoRoute)
+	forwarder.OnIncomingNack(upstream2, nack)
+	forwarder.shardTimer(name).DealEvent()
+	if fmt.Sprint(recorder.interestFaces) != "[2 3]" || len(recorder.nackFaces) != 0 {
+		t.Fatal("expect retry on face 3 without nacking downstream, got", recorder.interestFaces, recorder.nackFaces)
+	}
+	if pitEntry.IsDeleted() {
+		t.Fatal("expect pit entry kept while retrying")
+	}
+	forward(upstream3)
+
+	// 3 号也返回 Nack => 所有下一跳都被 Nack ，向下游返回 Nack 并立即移除 PIT 条目
+	forwarder.OnIncomingNack(upstream3, nack)
+	fmt.Println("after all nacked =>", recorder.interestFaces, recorder.nackFaces, recorder.nackReasons)
+	if fmt.Sprint(recorder.nackFaces) != "[1]" || recorder.nackReasons[0] != component.NackReasonNoRoute {
+		t.Fatal("expect nack to downstream, got", recorder.nackFaces, recorder.nackReasons)
+	}
+	forwarder.shardTimer(name).DealEvent()
+	if !pitEntry.IsDeleted() {
+		t.Fatal("expect pit entry expired after all next hops nacked")
+	}
+}
@@ -0,0 +1,669 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 4:00 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"math/rand"
+	common2 "minlib/common"
+	"minlib/component"
+	"minlib/encoding"
+	"minlib/logicface"
+	"minlib/minsecurity"
+	"minlib/minsecurity/crypto/cert"
+	"minlib/packet"
+	"minlib/security"
+	"mir-go/daemon/common"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/utils"
+	"sync"
+	"time"
+)
+
+const (
+	CertificateIdentifierSuffix      = "cert"  // 证书标识为签名者的网络身份加上该组件，例如 /min/pku/alice/cert
+	DefaultCertificateCacheLifetime  = 3600000 // 获取到的证书默认的缓存时间，单位 ms
+	DefaultCertificateFetchTimeout   = 4000    // 获取证书默认的超时时间，单位 ms
+	DefaultMaxParkedPacketNum        = 1000    // 默认最多暂存的等待证书的网络包的个数
+	DefaultMaxCertificateChainDepth  = 4       // 默认最多连续获取的证书的层数
+	certificateFetchTimeoutCheckTime = 500     // 检查证书获取是否超时的周期，单位 ms
+)
+
+// ICertificateStore
+// 证书的本地存储
+//
+// @Description:
+//  包验证器只能验证本地存储中存在证书的签名者的签名
+//
+type ICertificateStore interface {
+	// HasCertificate 本地是否存在 identity 的证书
+	HasCertificate(identity string) bool
+
+	// AddCertificate 将获取到的 identity 的证书加入本地存储，issuer 为证书数据包的签名者，content 为 PEM 格式的证书，
+	// 证书必须是由 issuer 签发给 identity 的，并且证书的签名可以被 issuer 的公钥验证
+	AddCertificate(identity string, issuer string, content []byte) error
+}
+
+// keyChainCertificateStore
+// 使用 KeyChain 作为证书的本地存储
+//
+// @Description:
+//
+type keyChainCertificateStore struct {
+	keyChain *security.KeyChain
+	imported map[string]bool // 由本存储导入的网络身份，过期之后重新获取时会被替换
+	lock     sync.Mutex      // 多个验证协程会并发的查询和导入证书，这边串行化对 KeyChain 的访问
+}
+
+// CreateKeyChainCertificateStore
+// 创建一个使用 KeyChain 作为本地存储的证书存储
+//
+// @Description:
+// @param keyChain
+// @return ICertificateStore
+//
+func CreateKeyChainCertificateStore(keyChain *security.KeyChain) ICertificateStore {
+	return &keyChainCertificateStore{keyChain: keyChain, imported: make(map[string]bool)}
+}
+
+// HasCertificate
+// 本地是否存在 identity 的证书
+//
+// @Description:
+// @receiver k
+// @param identity
+// @return bool
+//
+func (k *keyChainCertificateStore) HasCertificate(identity string) bool {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	return k.keyChain.GetIdentityByName(identity) != nil
+}
+
+// AddCertificate
+// 将获取到的 identity 的证书导入 KeyChain
+//
+// @Description:
+//  1. 证书的签发对象必须是 identity ，签发者必须是证书数据包的签名者 issuer ，并且证书的签名可以被 issuer 的公钥验证；
+//  2. 之前由本存储导入的 identity 的证书（缓存过期之后重新获取）会被新的证书替换，本地预先存在的 identity 不会被替换
+// @receiver k
+// @param identity
+// @param issuer
+// @param content
+// @return error
+//
+func (k *keyChainCertificateStore) AddCertificate(identity string, issuer string, content []byte) error {
+	certificate := cert.Certificate{}
+	if err := certificate.FromPem(string(content), nil, minsecurity.SM4ECB); err != nil {
+		return err
+	}
+	if normalizeTrustUri(certificate.IssueTo) != normalizeTrustUri(identity) {
+		return createCertificateFetcherErrorByType(CertificateIdentityMismatchError,
+			fmt.Sprintf("expect %s, got %s", identity, certificate.IssueTo))
+	}
+	if normalizeTrustUri(certificate.Issuer) != normalizeTrustUri(issuer) {
+		return createCertificateFetcherErrorByType(CertificateIssuerMismatchError,
+			fmt.Sprintf("certificate of %s signed by %s, issued by %s", identity, issuer, certificate.Issuer))
+	}
+
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	issuerIdentity := k.keyChain.GetIdentityByName(issuer)
+	if issuerIdentity == nil {
+		return createCertificateFetcherErrorByType(InvalidCertificateSignatureError, "unknown issuer "+issuer)
+	}
+	if ok, err := certificate.Verify(issuerIdentity.Pubkey); err != nil || !ok {
+		return createCertificateFetcherErrorByType(InvalidCertificateSignatureError, identity)
+	}
+	if k.keyChain.GetIdentityByName(identity) != nil {
+		if !k.imported[identity] {
+			// 本地预先存在的证书一直被信任，不需要替换
+			return nil
+		}
+		// 替换之前获取到的已经过期的证书
+		if _, err := k.keyChain.DeleteIdentityByName(identity, ""); err != nil {
+			return err
+		}
+		delete(k.imported, identity)
+	}
+	if err := k.keyChain.IdentityManager.ImportCert(content); err != nil {
+		return err
+	}
+	k.imported[identity] = true
+	return nil
+}
+
+// certificateFetch
+// 一个正在进行的证书获取
+//
+// @Description:
+//
+type certificateFetch struct {
+	identity string                   // 签名者的网络身份
+	deadline uint64                   // 超时时间
+	depth    int                      // 证书链的层数，由网络包的签名者触发的获取为第1层，由第 n 层证书的签名者触发的获取为第 n+1 层
+	parked   []*lf.IncomingPacketData // 等待本证书的网络包
+}
+
+// CertificateFetcher
+// 未知签名者的证书获取器
+//
+// @Description:
+//  1. 包验证器收到一个签名者的证书不在本地的网络包时，将其暂存在获取器中，由获取器通过转发器发送一个获取签名者证书的兴趣包，
+//     同一个签名者的多个网络包只会触发一次获取；
+//  2. 包含证书的数据包和其它网络包一样需要经过包验证器的验证，如果它的签名者也是未知的，则会继续获取上一级的证书，
+//     直到某个签名者的证书已经存在于本地；
+//  3. 证书只有在以下条件都满足时才会被导入：证书由证书数据包的签名者签发，且签名有效；证书不是自签发的；
+//     签发者是配置的信任锚，或者是缓存时间内获取到的、可以沿着签发者一直追溯到信任锚的证书。
+//     只是本地存在但不是信任锚的签名者不能签发证书；
+//  4. 证书导入成功之后，暂存的网络包被重新交给包验证器验证；获取超时、被 Nack 或者证书无效时，暂存的网络包被丢弃；
+//  5. 获取到的证书在缓存时间内被信任，过期之后同一个签名者的网络包会重新触发获取，新的证书会替换本地过期的证书，
+//     本地预先存在的证书一直被信任。
+//
+type CertificateFetcher struct {
+	store         ICertificateStore                                // 证书的本地存储
+	fetches       map[string]*certificateFetch                     // 证书标识 => 正在进行的获取
+	cache         map[string]uint64                                // 获取到的证书的签名者 => 过期时间
+	issuers       map[string]string                                // 获取到的证书的签名者 => 证书的签发者
+	anchors       map[string]bool                                  // 信任锚，只有可以追溯到信任锚的证书才会被导入
+	parkedNum     int                                              // 暂存的网络包的个数
+	maxParkedNum  int                                              // 最多暂存的网络包的个数
+	maxDepth      int                                              // 最多连续获取的证书的层数
+	cacheLifetime uint64                                           // 获取到的证书的缓存时间，单位 ms
+	fetchTimeout  uint64                                           // 获取证书的超时时间，单位 ms
+	sendInterest  func(certIdentifier string) error                // 发送获取证书的兴趣包
+	resume        func(data *lf.IncomingPacketData)                // 证书导入之后，将暂存的网络包重新交给包验证器
+	drop          func(data *lf.IncomingPacketData, reason string) // 丢弃暂存的网络包
+	closed        bool
+	stop          chan struct{}
+	lock          sync.Mutex
+}
+
+// CreateCertificateFetcher
+// 创建一个证书获取器
+//
+// @Description:
+// @param store				证书的本地存储
+// @param cacheLifetime		获取到的证书的缓存时间，单位 ms
+// @param fetchTimeout		获取证书的超时时间，单位 ms
+// @return *CertificateFetcher
+//
+func CreateCertificateFetcher(store ICertificateStore, cacheLifetime uint64, fetchTimeout uint64) *CertificateFetcher {
+	return &CertificateFetcher{
+		store:         store,
+		fetches:       make(map[string]*certificateFetch),
+		cache:         make(map[string]uint64),
+		issuers:       make(map[string]string),
+		anchors:       make(map[string]bool),
+		maxParkedNum:  DefaultMaxParkedPacketNum,
+		maxDepth:      DefaultMaxCertificateChainDepth,
+		cacheLifetime: cacheLifetime,
+		fetchTimeout:  fetchTimeout,
+		resume:        func(data *lf.IncomingPacketData) {},
+		drop:          func(data *lf.IncomingPacketData, reason string) {},
+		stop:          make(chan struct{}),
+	}
+}
+
+// SetLimits
+// 设置最多暂存的网络包的个数以及最多连续获取的证书的层数
+//
+// @Description:
+// @receiver c
+// @param maxParkedNum
+// @param maxDepth
+//
+func (c *CertificateFetcher) SetLimits(maxParkedNum int, maxDepth int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.maxParkedNum = maxParkedNum
+	c.maxDepth = maxDepth
+}
+
+// SetTrustAnchors
+// 设置信任锚
+//
+// @Description:
+//  信任锚的证书必须已经存在于本地，没有设置信任锚时所有获取到的证书都会被拒绝
+// @receiver c
+// @param anchors		信任锚的网络身份
+//
+func (c *CertificateFetcher) SetTrustAnchors(anchors []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.anchors = make(map[string]bool)
+	for _, anchor := range anchors {
+		c.anchors[normalizeTrustUri(anchor)] = true
+	}
+}
+
+// Start
+// 启动证书获取器
+//
+// @Description:
+//  通过一对内部 LogicFace 与转发器通信：从 faceClient 发出获取证书的兴趣包，并从 faceClient 接收证书数据包或者 Nack ；
+//  同时启动一个协程定期检查获取是否超时
+// @receiver c
+// @param faceClient		内部 LogicFace 中其它模块使用的一端
+//
+func (c *CertificateFetcher) Start(faceClient *logicface.LogicFace) {
+	c.sendInterest = func(certIdentifier string) error {
+		identifier, err := component.CreateIdentifierByString(certIdentifier)
+		if err != nil {
+			return err
+		}
+		interest := new(packet.Interest)
+		interest.SetName(identifier)
+		interest.SetCanBePrefix(false)
+		interest.SetNonce(rand.Uint64())
+		interest.InterestLifeTime.SetInterestLifeTime(c.fetchTimeout)
+		return faceClient.SendInterest(interest)
+	}
+	utils.GoroutineNoPanic(func() {
+		c.receiveLoop(faceClient)
+	})
+	utils.GoroutineNoPanic(c.timeoutLoop)
+}
+
+// IsTrusted
+// 判断本地是否有可用的 identity 的证书
+//
+// @Description:
+//  获取到的证书只在缓存时间内可用，本地预先存在的证书一直可用
+// @receiver c
+// @param identity
+// @return bool
+//
+func (c *CertificateFetcher) IsTrusted(identity string) bool {
+	c.lock.Lock()
+	expireTime, ok := c.cache[normalizeTrustUri(identity)]
+	c.lock.Unlock()
+	if ok {
+		return common.GetCurrentTime() < expireTime
+	}
+	return c.store.HasCertificate(identity)
+}
+
+// Park
+// 暂存一个签名者的证书不可用的网络包，并获取签名者的证书
+//
+// @Description:
+//  暂存的网络包过多、证书链过长或者获取器已经关闭时，直接丢弃
+// @receiver c
+// @param data
+// @param identifier		网络包的第一个标识
+// @param signer			网络包的签名者
+//
+func (c *CertificateFetcher) Park(data *lf.IncomingPacketData, identifier string, signer string) {
+	now := common.GetCurrentTime()
+	certIdentifier := makeCertificateIdentifier(signer)
+	c.lock.Lock()
+	if c.closed {
+		c.lock.Unlock()
+		c.drop(data, createCertificateFetcherErrorByType(CertificateFetcherClosedError, signer).Error())
+		return
+	}
+	if c.parkedNum >= c.maxParkedNum {
+		c.lock.Unlock()
+		c.drop(data, createCertificateFetcherErrorByType(TooManyParkedPacketsError, signer).Error())
+		return
+	}
+
+	// 本网络包是另一个正在进行的获取的证书数据包时，本次获取是证书链的下一层
+	depth, deadline := 1, now+c.fetchTimeout
+	if parent, ok := c.fetches[normalizeTrustUri(identifier)]; ok {
+		// 下一层的获取必须在上一层超时之前完成，否则上一层的兴趣包已经过期，证书数据包无法再被转发给获取器
+		depth, deadline = parent.depth+1, parent.deadline
+	}
+	fetch, ok := c.fetches[certIdentifier]
+	if !ok {
+		if depth > c.maxDepth {
+			c.lock.Unlock()
+			c.drop(data, createCertificateFetcherErrorByType(CertificateChainTooLongError, signer).Error())
+			return
+		}
+		fetch = &certificateFetch{identity: normalizeTrustUri(signer), deadline: deadline, depth: depth}
+		c.fetches[certIdentifier] = fetch
+	}
+	fetch.parked = append(fetch.parked, data)
+	c.parkedNum++
+	c.lock.Unlock()
+
+	if ok {
+		// 已经在获取，等待即可
+		return
+	}
+	common2.LogDebugWithFields(logrus.Fields{
+		"certIdentifier": certIdentifier,
+		"depth":          depth,
+	}, "Fetch certificate")
+	if c.sendInterest == nil {
+		c.onFetchFailed(certIdentifier, createCertificateFetcherErrorByType(CertificateFetcherClosedError, signer))
+		return
+	}
+	if err := c.sendInterest(certIdentifier); err != nil {
+		c.onFetchFailed(certIdentifier, err)
+	}
+}
+
+// OnCertificateData
+// 收到一个证书数据包
+//
+// @Description:
+//  证书数据包已经通过包验证器的验证，这边检查证书链可以追溯到信任锚之后将证书导入本地存储，
+//  成功则恢复所有等待该证书的网络包，否则丢弃它们
+// @receiver c
+// @param certIdentifier		证书数据包的标识
+// @param signer				证书数据包的签名者
+// @param content				证书的内容
+//
+func (c *CertificateFetcher) OnCertificateData(certIdentifier string, signer string, content []byte) {
+	certIdentifier = normalizeTrustUri(certIdentifier)
+	signer = normalizeTrustUri(signer)
+	c.lock.Lock()
+	fetch, ok := c.fetches[certIdentifier]
+	if !ok {
+		// 已经超时或者不是我们请求的证书
+		c.lock.Unlock()
+		return
+	}
+	err := c.checkIssuer(fetch.identity, signer, common.GetCurrentTime())
+	c.lock.Unlock()
+	if err != nil {
+		c.onFetchFailed(certIdentifier, err)
+		return
+	}
+	if err := c.store.AddCertificate(fetch.identity, signer, content); err != nil {
+		c.onFetchFailed(certIdentifier, err)
+		return
+	}
+
+	c.lock.Lock()
+	if c.fetches[certIdentifier] != fetch {
+		c.lock.Unlock()
+		return
+	}
+	delete(c.fetches, certIdentifier)
+	c.cache[fetch.identity] = common.GetCurrentTime() + c.cacheLifetime
+	c.issuers[fetch.identity] = signer
+	c.parkedNum -= len(fetch.parked)
+	c.lock.Unlock()
+
+	common2.LogDebugWithFields(logrus.Fields{
+		"certIdentifier": certIdentifier,
+		"parked":         len(fetch.parked),
+	}, "Certificate fetched")
+	for _, data := range fetch.parked {
+		c.resume(data)
+	}
+}
+
+// OnCertificateNack
+// 获取证书的兴趣包被 Nack
+//
+// @Description:
+// @receiver c
+// @param certIdentifier
+//
+func (c *CertificateFetcher) OnCertificateNack(certIdentifier string) {
+	c.onFetchFailed(normalizeTrustUri(certIdentifier), createCertificateFetcherErrorByType(CertificateFetchNackedError, certIdentifier))
+}
+
+// CheckTimeout
+// 丢弃所有在 now 时刻已经超时的获取所暂存的网络包
+//
+// @Description:
+// @receiver c
+// @param now
+//
+func (c *CertificateFetcher) CheckTimeout(now uint64) {
+	c.lock.Lock()
+	timeouts := make([]string, 0)
+	for certIdentifier, fetch := range c.fetches {
+		if fetch.deadline <= now {
+			timeouts = append(timeouts, certIdentifier)
+		}
+	}
+	c.lock.Unlock()
+	for _, certIdentifier := range timeouts {
+		c.onFetchFailed(certIdentifier, createCertificateFetcherErrorByType(CertificateFetchTimeoutError, certIdentifier))
+	}
+}
+
+// GetParkedNum
+// 获取暂存的网络包的个数
+//
+// @Description:
+// @receiver c
+// @return int
+//
+func (c *CertificateFetcher) GetParkedNum() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.parkedNum
+}
+
+// Close
+// 关闭证书获取器，丢弃所有暂存的网络包
+//
+// @Description:
+// @receiver c
+//
+func (c *CertificateFetcher) Close() {
+	c.lock.Lock()
+	if c.closed {
+		c.lock.Unlock()
+		return
+	}
+	c.closed = true
+	close(c.stop)
+	certIdentifiers := make([]string, 0, len(c.fetches))
+	for certIdentifier := range c.fetches {
+		certIdentifiers = append(certIdentifiers, certIdentifier)
+	}
+	c.lock.Unlock()
+	for _, certIdentifier := range certIdentifiers {
+		c.onFetchFailed(certIdentifier, createCertificateFetcherErrorByType(CertificateFetcherClosedError, certIdentifier))
+	}
+}
+
+//
+// 检查 issuer 是否可以为 identity 签发证书，调用时需要持有 c.lock
+//
+// @Description:
+//  自签发的证书总是被拒绝；issuer 必须是信任锚，或者是在 now 时刻仍在缓存时间内的获取到的证书，
+//  并且沿着签发者最多追溯 c.maxDepth 层可以到达信任锚
+// @receiver c
+// @param identity
+// @param issuer
+// @param now
+// @return error
+//
+func (c *CertificateFetcher) checkIssuer(identity string, issuer string, now uint64) error {
+	if issuer == identity {
+		return createCertificateFetcherErrorByType(SelfIssuedCertificateError, identity)
+	}
+	current := issuer
+	for depth := 0; depth <= c.maxDepth; depth++ {
+		if c.anchors[current] {
+			return nil
+		}
+		expireTime, ok := c.cache[current]
+		if !ok || expireTime <= now {
+			break
+		}
+		current = c.issuers[current]
+	}
+	return createCertificateFetcherErrorByType(UntrustedCertificateIssuerError,
+		fmt.Sprintf("certificate of %s issued by %s", identity, issuer))
+}
+
+//
+// 结束一个失败的获取，丢弃所有等待该证书的网络包
+//
+// @Description:
+// @receiver c
+// @param certIdentifier
+// @param err				失败的原因
+//
+func (c *CertificateFetcher) onFetchFailed(certIdentifier string, err error) {
+	c.lock.Lock()
+	fetch, ok := c.fetches[certIdentifier]
+	if !ok {
+		c.lock.Unlock()
+		return
+	}
+	delete(c.fetches, certIdentifier)
+	c.parkedNum -= len(fetch.parked)
+	c.lock.Unlock()
+
+	common2.LogDebugWithFields(logrus.Fields{
+		"certIdentifier": certIdentifier,
+		"parked":         len(fetch.parked),
+	}, "Fetch certificate failed: ", err)
+	for _, data := range fetch.parked {
+		c.drop(data, err.Error())
+	}
+}
+
+//
+// 从内部 LogicFace 接收证书数据包或者 Nack
+//
+// @Description:
+// @receiver c
+// @param faceClient
+//
+func (c *CertificateFetcher) receiveLoop(faceClient *logicface.LogicFace) {
+	for {
+		minPacket, err := faceClient.ReceivePacket(-1)
+		if err != nil {
+			common2.LogWarn("CertificateFetcher receive packet failed: ", err)
+			return
+		}
+		identifier, err := minPacket.GetIdentifier(0)
+		if err != nil {
+			continue
+		}
+		switch uint64(identifier.GetIdentifierType()) {
+		case uint64(encoding.TlvIdentifierContentData):
+			data, err := packet.NewDataByMINPacket(minPacket)
+			if err != nil {
+				continue
+			}
+			signer, err := GetMINPacketSignerIdentifier(minPacket)
+			if err != nil {
+				// 没有签名的证书数据包无法追溯签发者
+				c.onFetchFailed(normalizeTrustUri(data.GetName().ToUri()), err)
+				continue
+			}
+			c.OnCertificateData(data.GetName().ToUri(), signer.ToUri(), data.Payload.GetValue())
+		case uint64(encoding.TlvIdentifierContentInterest):
+			if interest, err := packet.NewInterestByMINPacket(minPacket); err == nil && interest.NackHeader.IsInitial() {
+				c.OnCertificateNack(interest.GetName().ToUri())
+			}
+		}
+	}
+}
+
+//
+// 定期检查获取是否超时，直到获取器被关闭
+//
+// @Description:
+// @receiver c
+//
+func (c *CertificateFetcher) timeoutLoop() {
+	ticker := time.NewTicker(certificateFetchTimeoutCheckTime * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.CheckTimeout(common.GetCurrentTime())
+		}
+	}
+}
+
+//
+// 获取签名者的证书标识
+//
+// @Description:
+// @param signer
+// @return string
+//
+func makeCertificateIdentifier(signer string) string {
+	return normalizeTrustUri(normalizeTrustUri(signer) + "/" + CertificateIdentifierSuffix)
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+///// 错误处理
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	CertificateIdentityMismatchError = iota
+	TooManyParkedPacketsError
+	CertificateChainTooLongError
+	CertificateFetchTimeoutError
+	CertificateFetchNackedError
+	CertificateFetcherClosedError
+	CertificateIssuerMismatchError
+	InvalidCertificateSignatureError
+	SelfIssuedCertificateError
+	UntrustedCertificateIssuerError
+)
+
+type CertificateFetcherError struct {
+	msg string
+}
+
+func (c CertificateFetcherError) Error() string {
+	return fmt.Sprintf("CertificateFetcherError: %s", c.msg)
+}
+
+func createCertificateFetcherErrorByType(errorType int, detail string) (err CertificateFetcherError) {
+	switch errorType {
+	case CertificateIdentityMismatchError:
+		err.msg = fmt.Sprintf("certificate identity mismatch: %s", detail)
+	case TooManyParkedPacketsError:
+		err.msg = fmt.Sprintf("too many packets waiting for certificate, drop packet signed by %s", detail)
+	case CertificateChainTooLongError:
+		err.msg = fmt.Sprintf("certificate chain too long when fetching certificate of %s", detail)
+	case CertificateFetchTimeoutError:
+		err.msg = fmt.Sprintf("fetch certificate timeout: %s", detail)
+	case CertificateFetchNackedError:
+		err.msg = fmt.Sprintf("fetch certificate nacked: %s", detail)
+	case CertificateFetcherClosedError:
+		err.msg = fmt.Sprintf("certificate fetcher closed: %s", detail)
+	case CertificateIssuerMismatchError:
+		err.msg = fmt.Sprintf("certificate issuer mismatch: %s", detail)
+	case InvalidCertificateSignatureError:
+		err.msg = fmt.Sprintf("invalid certificate signature: %s", detail)
+	case SelfIssuedCertificateError:
+		err.msg = fmt.Sprintf("self-issued certificate is not trusted: %s", detail)
+	case UntrustedCertificateIssuerError:
+		err.msg = fmt.Sprintf("certificate chain does not reach a trust anchor: %s", detail)
+	default:
+		err.msg = "Unknown error"
+	}
+	return
+}
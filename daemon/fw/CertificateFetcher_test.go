@@ -0,0 +1,170 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 4:40 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package fw
+
+import (
+	"fmt"
+	"mir-go/daemon/common"
+	"mir-go/daemon/lf"
+	"testing"
+)
+
+// fakeCertificateStore 中证书的内容为 "<签发对象> <签发者>"
+type fakeCertificateStore struct {
+	certificates map[string]bool
+	added        int
+}
+
+func (f *fakeCertificateStore) HasCertificate(identity string) bool {
+	return f.certificates[identity]
+}
+
+func (f *fakeCertificateStore) AddCertificate(identity string, issuer string, content []byte) error {
+	if string(content) != identity+" "+issuer {
+		return fmt.Errorf("invalid certificate for %s", identity)
+	}
+	f.certificates[identity] = true
+	f.added++
+	return nil
+}
+
+func createTestCertificateFetcher() (*CertificateFetcher, *fakeCertificateStore, *[]string, *[]*lf.IncomingPacketData, *[]string) {
+	store := &fakeCertificateStore{certificates: map[string]bool{"/min/anchor": true, "/min/local": true}}
+	fetcher := CreateCertificateFetcher(store, DefaultCertificateCacheLifetime, DefaultCertificateFetchTimeout)
+	fetcher.SetTrustAnchors([]string{"/min/anchor"})
+	sent := make([]string, 0)
+	resumed := make([]*lf.IncomingPacketData, 0)
+	dropped := make([]string, 0)
+	fetcher.sendInterest = func(certIdentifier string) error {
+		sent = append(sent, certIdentifier)
+		return nil
+	}
+	fetcher.resume = func(data *lf.IncomingPacketData) {
+		resumed = append(resumed, data)
+	}
+	fetcher.drop = func(data *lf.IncomingPacketData, reason string) {
+		dropped = append(dropped, reason)
+	}
+	return fetcher, store, &sent, &resumed, &dropped
+}
+
+func TestCertificateFetcher_FetchAndResume(t *testing.T) {
+	fetcher, store, sent, resumed, dropped := createTestCertificateFetcher()
+	if !fetcher.IsTrusted("/min/anchor") || fetcher.IsTrusted("/min/alice") {
+		t.Fatal("expect only the anchor is trusted")
+	}
+
+	// 同一个签名者的两个包只会触发一次获取
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/alice/file/1", "/min/alice")
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/alice/file/2", "/min/alice")
+	if len(*sent) != 1 || (*sent)[0] != "/min/alice/cert" || fetcher.GetParkedNum() != 2 {
+		t.Fatal("expect one fetch for /min/alice/cert, got", *sent, fetcher.GetParkedNum())
+	}
+
+	// 无效的证书 => 丢弃
+	fetcher.OnCertificateData("/min/alice/cert", "/min/anchor", []byte("/min/bob /min/anchor"))
+	if len(*dropped) != 2 || fetcher.GetParkedNum() != 0 || fetcher.IsTrusted("/min/alice") {
+		t.Fatal("expect parked packets dropped, got", *dropped)
+	}
+
+	// 有效的证书 => 恢复，并在缓存时间内被信任
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/alice/file/3", "/min/alice")
+	fetcher.OnCertificateData("/min/alice/cert", "/min/anchor", []byte("/min/alice /min/anchor"))
+	if len(*resumed) != 1 || !fetcher.IsTrusted("/min/alice") {
+		t.Fatal("expect parked packet resumed, got", len(*resumed))
+	}
+
+	// 缓存过期之后不再被信任，重新获取到的证书替换过期的证书
+	fetcher.cache["/min/alice"] = common.GetCurrentTime() - 1
+	if fetcher.IsTrusted("/min/alice") {
+		t.Fatal("expect /min/alice expired")
+	}
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/alice/file/4", "/min/alice")
+	fetcher.OnCertificateData("/min/alice/cert", "/min/anchor", []byte("/min/alice /min/anchor"))
+	if len(*sent) != 3 || store.added != 2 || len(*resumed) != 2 || !fetcher.IsTrusted("/min/alice") {
+		t.Fatal("expect expired certificate fetched again, got", *sent, store.added)
+	}
+	fmt.Println(*sent, *dropped)
+}
+
+func TestCertificateFetcher_TimeoutAndChain(t *testing.T) {
+	fetcher, _, sent, _, dropped := createTestCertificateFetcher()
+	fetcher.SetLimits(DefaultMaxParkedPacketNum, 2)
+
+	// 证书数据包的签名者也是未知的 => 获取证书链的下一层
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/alice/file/1", "/min/alice")
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/alice/cert", "/min/org")
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/org/cert", "/min/root")
+	if len(*sent) != 2 || len(*dropped) != 1 {
+		t.Fatal("expect the third level dropped, got", *sent, *dropped)
+	}
+
+	// 超时 => 丢弃
+	fetcher.CheckTimeout(common.GetCurrentTime() + DefaultCertificateFetchTimeout)
+	if len(*dropped) != 3 || fetcher.GetParkedNum() != 0 {
+		t.Fatal("expect all parked packets dropped, got", *dropped)
+	}
+
+	// 关闭之后不再暂存
+	fetcher.Close()
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/alice/file/1", "/min/alice")
+	if len(*dropped) != 4 {
+		t.Fatal("expect packet dropped after close")
+	}
+	fmt.Println(*dropped)
+}
+
+func TestCertificateFetcher_RejectUntrustedIssuer(t *testing.T) {
+	fetcher, store, _, resumed, dropped := createTestCertificateFetcher()
+
+	// 自签发的证书 => 丢弃
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/alice/file/1", "/min/alice")
+	fetcher.OnCertificateData("/min/alice/cert", "/min/alice", []byte("/min/alice /min/alice"))
+	// 伪造的证书：声称由信任锚签发，但证书数据包的签名者不是信任锚 => 丢弃
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/alice/file/2", "/min/alice")
+	fetcher.OnCertificateData("/min/alice/cert", "/min/mallory", []byte("/min/alice /min/anchor"))
+	// 本地存在但不是信任锚的签发者 => 丢弃
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/alice/file/3", "/min/alice")
+	fetcher.OnCertificateData("/min/alice/cert", "/min/local", []byte("/min/alice /min/local"))
+	if len(*dropped) != 3 || len(*resumed) != 0 || store.added != 0 || fetcher.IsTrusted("/min/alice") {
+		t.Fatal("expect untrusted certificates rejected, got", *dropped)
+	}
+
+	// 由信任锚签发的中间证书签发 => 导入
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/org/file/1", "/min/org")
+	fetcher.OnCertificateData("/min/org/cert", "/min/anchor", []byte("/min/org /min/anchor"))
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/alice/file/4", "/min/alice")
+	fetcher.OnCertificateData("/min/alice/cert", "/min/org", []byte("/min/alice /min/org"))
+	if len(*resumed) != 2 || !fetcher.IsTrusted("/min/alice") {
+		t.Fatal("expect certificate chain to the anchor accepted, got", *dropped)
+	}
+
+	// 中间证书过期之后，它不能再签发证书
+	fetcher.cache["/min/org"] = common.GetCurrentTime() - 1
+	fetcher.Park(&lf.IncomingPacketData{}, "/min/bob/file/1", "/min/bob")
+	fetcher.OnCertificateData("/min/bob/cert", "/min/org", []byte("/min/bob /min/org"))
+	if len(*dropped) != 4 || fetcher.IsTrusted("/min/bob") {
+		t.Fatal("expect certificate issued by an expired certificate rejected, got", *dropped)
+	}
+	fmt.Println(*dropped)
+}
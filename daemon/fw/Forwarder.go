@@ -29,7 +29,6 @@ import (
 	"minlib/component"
 	"minlib/encoding"
 	"minlib/packet"
-	utils2 "minlib/utils"
 	"mir-go/daemon/common"
 	"mir-go/daemon/lf"
 	"mir-go/daemon/plugin"
@@ -37,7 +36,16 @@ import (
 	"mir-go/daemon/utils"
 	"os"
 	"os/signal"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
+)
+
+const (
+	defaultShardQueueSize = 100 // 没有配置包队列大小时，每个分片包队列的默认大小
+	drainIdleTimeout      = 100 // 停止转发器时，包队列持续为空超过该时间则认为其中的网络包已经处理完了，单位 ms
 )
 
 // Forwarder MIR 转发器实例
@@ -45,15 +53,23 @@ import (
 // @Description:
 //
 type Forwarder struct {
-	table.PIT                                       // 内嵌一个PIT表
-	table.FIB                                       // 内嵌一个FIB表
-	table.ICS                                       // 内嵌一个CS表
-	table.StrategyTable                             // 内嵌一个策略选择表
-	config              *common.MIRConfig           // 记录配置文件信息
-	pluginManager       *plugin.GlobalPluginManager // 插件管理器
-	packetQueue         *utils2.BlockQueue          // 包队列
-	heapTimer           *utils2.HeapTimer           // 堆定时器，用来处理PIT的超时事件
-	interrupt           chan os.Signal              // 用来接收系统的信号，结束程序
+	PIT                 ShardedPIT                    // 按分片划分的PIT表
+	table.FIB                                         // 内嵌一个FIB表
+	table.ICS                                         // 内嵌一个CS表
+	table.StrategyTable                               // 内嵌一个策略选择表
+	config              *common.MIRConfig             // 记录配置文件信息
+	pluginManager       *plugin.GlobalPluginManager   // 插件管理器
+	packetQueue         <-chan *lf.IncomingPacketData // 包队列，所有 LogicFace 收到的包都先放入这个队列，再分发到各个分片
+	shards              []*forwarderShard             // 转发分片
+	interrupt           chan os.Signal                // 用来接收系统的信号，结束程序
+	stopChan            chan struct{}                 // 关闭之后分发协程开始排空包队列
+	dispatchDone        chan struct{}                 // 分发协程退出之后被关闭
+	drainDeadline       time.Time                     // 排空包队列的截止时间
+	shardWaitGroup      sync.WaitGroup                // 用来等待所有分片协程退出
+	counters            ForwarderCounters             // 转发器全局的统计信息
+	startTime           uint64                        // 转发器的启动时间，单位 ms
+	identifierTypes     *IdentifierTypeRegistry       // 标识类型 => 网络包处理函数
+	routerSignature     *RouterSignature              // 中间路由器签名，为 nil 时表示没有开启
 }
 
 // Init 初始化转发器
@@ -61,12 +77,14 @@ type Forwarder struct {
 // @Description:
 // @receiver f
 //
-func (f *Forwarder) Init(config *common.MIRConfig, pluginManager *plugin.GlobalPluginManager, packetQueue *utils2.BlockQueue) error {
+func (f *Forwarder) Init(config *common.MIRConfig, pluginManager *plugin.GlobalPluginManager, packetQueue <-chan *lf.IncomingPacketData) error {
 	f.config = config
+	f.startTime = common.GetCurrentTime()
 	f.interrupt = make(chan os.Signal, 1)
 	signal.Notify(f.interrupt, os.Interrupt, os.Kill, syscall.SIGTERM)
+	f.stopChan = make(chan struct{})
+	f.dispatchDone = make(chan struct{})
 	// 初始化各个表
-	f.PIT.Init()
 	f.FIB.Init()
 	// 初始化缓存
 	if ucs, err := table.NewUniversalCS(config); err != nil {
@@ -77,66 +95,303 @@ func (f *Forwarder) Init(config *common.MIRConfig, pluginManager *plugin.GlobalP
 	f.StrategyTable.Init()
 	f.pluginManager = pluginManager
 	f.packetQueue = packetQueue
-	// 初始化一个堆定时器
-	f.heapTimer = utils2.NewHeapTimer()
+
+	// 初始化转发分片，每个分片都有自己的PIT表和堆定时器
+	shardNum, shardQueueSize := 1, defaultShardQueueSize
+	dedupSize, dedupLifetime := table.DefaultGPPktDedupCacheSize, uint64(table.DefaultGPPktDedupLifetime)
+	if config != nil {
+		dedupSize = config.ForwarderConfig.GPPktDedupCacheSize
+		if config.ForwarderConfig.GPPktDedupLifetime > 0 {
+			dedupLifetime = uint64(config.ForwarderConfig.GPPktDedupLifetime)
+		}
+		shardNum = config.ForwarderConfig.ShardNum
+		if shardNum <= 0 {
+			shardNum = runtime.NumCPU()
+		}
+		if config.ForwarderConfig.PacketQueueSize > 0 {
+			shardQueueSize = config.ForwarderConfig.PacketQueueSize
+		}
+	}
+	f.shards = make([]*forwarderShard, shardNum)
+	for i := 0; i < shardNum; i++ {
+		f.shards[i] = newForwarderShard(i, shardQueueSize, dedupSize, dedupLifetime)
+	}
+	f.PIT.shards = f.shards
+
+	// 注册内置的标识类型处理函数，并使用配置文件中的标识类型作为白名单
+	f.identifierTypes = CreateIdentifierTypeRegistry()
+	_ = f.identifierTypes.Register(uint64(encoding.TlvIdentifierCommon), f.onReceiveGPPktMINPacket)
+	_ = f.identifierTypes.Register(uint64(encoding.TlvIdentifierContentInterest), f.onReceiveInterestMINPacket)
+	_ = f.identifierTypes.Register(uint64(encoding.TlvIdentifierContentData), f.onReceiveDataMINPacket)
+	if config != nil {
+		allowlist := make([]uint64, 0, len(config.GeneralConfig.IdentifierType))
+		for _, identifierType := range config.GeneralConfig.IdentifierType {
+			allowlist = append(allowlist, uint64(identifierType))
+		}
+		f.identifierTypes.SetAllowlist(allowlist)
+	}
+
 	identifier, err := component.CreateIdentifierByString("/")
 	if err != nil {
 		return err
 	}
-	bestRouteStrategy := new(BestRouteStrategy)
-	bestRouteStrategy.SetForwarder(f)
-	f.StrategyTable.Insert(identifier, "/strategy/best-route", bestRouteStrategy)
+	// 默认使用 best-route v=1 策略
+	return f.SetStrategy(identifier, BestRouteStrategyName+"/v=1")
+}
+
+// SetStrategy
+// 根据策略名新建一个策略实例，并设置为某个前缀的转发策略
+//
+// @Description:
+// @receiver f
+// @param identifier		前缀
+// @param strategyName		策略名，例如：/strategy/best-route、/strategy/asf/v=1/probing-interval=30000
+// @return error
+//
+func (f *Forwarder) SetStrategy(identifier *component.Identifier, strategyName string) error {
+	strategy, canonicalName, err := CreateStrategy(f, strategyName)
+	if err != nil {
+		return err
+	}
+	f.StrategyTable.Insert(identifier, canonicalName, strategy)
 	return nil
 }
 
 // Start 启动转发处理流程
 //
 // @Description:
+//  1. 为每个转发分片启动一个处理协程；
+//  2. 启动一个分发协程，从包队列读取网络包，根据网络包第一个标识的哈希值分发到对应的分片；
+//  3. 阻塞直到收到退出信号或者有协程 panic：
+//     - 收到退出信号时返回 nil 错误，此时分片协程和分发协程依然在运行，调用者应该在关闭监听器并让所有的 LogicFace 停止接收之后
+//       调用 Stop 排空包队列；
+//     - 有协程 panic 时返回对应的错误，此时转发器已经无法正常工作，不能再调用 Stop。
 //
 func (f *Forwarder) Start() (string, error) {
-	resMsg := ""
-	resErr := errors.New("")
-	utils.ProtectRun(func() {
-		for true {
-			select {
-			case killSignal := <-f.interrupt:
-				if killSignal == os.Interrupt {
-					resMsg = "Daemon was interrupted by system signal"
-					common2.LogFatal("Daemon was interrupted by system signal")
-				} else {
-					resMsg = "Daemon was killed"
-					common2.LogFatal("Daemon was killed")
-				}
-				resErr = nil
-				break
-			default:
-				// 在处理包之前
-				f.heapTimer.DealEvent()
-				// 此处读取包时，不采用阻塞操作，因为要保证超时事件能得到正确的处理
-				if data, err := f.packetQueue.ReadUntil(1); err != nil {
-					// 读取超时了
-				} else {
-					ipd, ok := data.(*lf.IncomingPacketData)
-					if !ok {
-						continue
-					}
-					f.OnReceiveMINPacket(ipd)
-				}
-			}
+	// 任意一个分片协程 panic 都会导致转发器退出
+	shardPanic := make(chan interface{}, len(f.shards)+1)
+	onPanic := func(err interface{}) {
+		shardPanic <- err
+	}
+	for _, shard := range f.shards {
+		f.shardWaitGroup.Add(1)
+		go func(shard *forwarderShard) {
+			defer f.shardWaitGroup.Done()
+			shard.run(f, onPanic)
+		}(shard)
+	}
+	go utils.ProtectRun(f.dispatchLoop, onPanic)
+
+	select {
+	case killSignal := <-f.interrupt:
+		if killSignal == os.Interrupt {
+			common2.LogInfo("Daemon was interrupted by system signal")
+			return "Daemon was interrupted by system signal", nil
 		}
-	}, func(err interface{}) {
+		common2.LogInfo("Daemon was killed")
+		return "Daemon was killed", nil
+	case err := <-shardPanic:
 		// Panic error
 		common2.LogError(err)
-		resMsg = "Daemon crash by panic"
-		resErr = nil
-	})
-	return resMsg, resErr
+		// 通知分发协程退出，同时避免其它协程在往分片投递任务时被永久阻塞
+		close(f.stopChan)
+		return "Daemon crash by panic", errors.New(fmt.Sprint(err))
+	}
+}
+
+// Stop 停止转发处理流程
+//
+// @Description:
+//  1. 通知分发协程开始排空包队列，分发协程会将包队列中剩余的网络包分发到各个分片之后再退出，最多等待 drainTimeout；
+//  2. 关闭所有分片的包队列，分片协程会处理完分片包队列中剩余的网络包之后再退出；
+//  3. 所有分片协程退出之后，向所有仍然 pending 的兴趣包的下游发送 Nack，并移除对应的 PIT 条目。
+// @receiver f
+// @param drainTimeout		排空包队列的最长时间
+//
+func (f *Forwarder) Stop(drainTimeout time.Duration) {
+	f.drainDeadline = time.Now().Add(drainTimeout)
+	close(f.stopChan)
+	<-f.dispatchDone
+
+	// 分发协程已经退出，不会再有网络包写入分片的包队列
+	for _, shard := range f.shards {
+		close(shard.packetChan)
+	}
+	f.shardWaitGroup.Wait()
+
+	// 分片协程都已经退出，可以在当前协程中安全的访问各个分片的 PIT
+	for _, shard := range f.shards {
+		f.rejectAllPendingInterests(shard)
+	}
+}
+
+//
+// 向某个分片中所有仍然 pending 的兴趣包的下游发送 Nack，并移除对应的 PIT 条目
+//
+// @Description:
+//  转发器即将退出，不会再有上游返回的数据，所以使用 no-route 作为 Nack 的原因，让下游尽快选择其它的路由重试
+// @receiver f
+// @param shard
+//
+func (f *Forwarder) rejectAllPendingInterests(shard *forwarderShard) {
+	var nh component.NackHeader
+	nh.SetNackReason(component.NackReasonNoRoute)
+	for _, pitEntry := range shard.pit.GetAllEntry() {
+		for _, inRecord := range pitEntry.GetInRecords() {
+			f.OnOutgoingNack(inRecord.LogicFace, pitEntry, &nh)
+		}
+		f.OnInterestFinalize(pitEntry)
+	}
+}
+
+//
+// 分发协程的处理循环，从包队列中读取网络包并分发到对应的分片
+//
+// @Description:
+//  在一个 select 中同时等待包队列和停止信号，没有网络包时协程一直阻塞，不会轮询；收到停止信号之后排空包队列再退出
+// @receiver f
+//
+func (f *Forwarder) dispatchLoop() {
+	defer close(f.dispatchDone)
+	for true {
+		select {
+		case ipd := <-f.packetQueue:
+			f.dispatch(ipd)
+		case <-f.stopChan:
+			f.drainPacketQueue()
+			return
+		}
+	}
+}
+
+//
+// 停止转发器时，将包队列中剩余的网络包分发到各个分片
+//
+// @Description:
+//  调用者应该先让所有的 LogicFace 停止接收，这样包队列中只剩下有限的已经收到的网络包（ LogicFace 接收队列中剩余的包和正在验签的包），
+//  包队列持续为空超过 drainIdleTimeout 时认为已经排空；到了 f.drainDeadline 还没有排空时，剩余的网络包会被丢弃
+// @receiver f
+//
+func (f *Forwarder) drainPacketQueue() {
+	deadline := time.NewTimer(time.Until(f.drainDeadline))
+	defer deadline.Stop()
+	idle := time.NewTimer(drainIdleTimeout * time.Millisecond)
+	defer idle.Stop()
+	for true {
+		select {
+		case ipd := <-f.packetQueue:
+			f.dispatch(ipd)
+			if !idle.Stop() {
+				<-idle.C
+			}
+			idle.Reset(drainIdleTimeout * time.Millisecond)
+		case <-idle.C:
+			return
+		case <-deadline.C:
+			common2.LogWarn("Drain packet queue timeout, the remaining packets will be dropped")
+			return
+		}
+	}
+}
+
+//
+// 将收到的网络包分发到对应的分片
+//
+// @Description:
+//  根据网络包第一个标识的哈希值选择分片，对于 Interest、Nack 和 Data 而言，第一个标识就是其名字，所以同名的 Interest 和 Data
+//  总是会被分发到同一个分片。
+//  如果分片的包队列已经满了，则直接丢弃该网络包并增加 NShardQueueDrops 计数，避免一个处理不过来的分片阻塞分发协程，进而阻塞所有
+//  其它分片。
+// @receiver f
+// @param ipd
+//
+func (f *Forwarder) dispatch(ipd *lf.IncomingPacketData) {
+	identifier, err := ipd.MinPacket.GetIdentifier(0)
+	if err != nil {
+		common2.LogWarnWithFields(logrus.Fields{
+			"faceId": ipd.LogicFace.LogicFaceId,
+		}, "Get Identifier failed")
+		return
+	}
+	shard := f.shards[shardIndex(identifier.ToUri(), len(f.shards))]
+	select {
+	case shard.packetChan <- ipd:
+	default:
+		atomic.AddUint64(&f.counters.NShardQueueDrops, 1)
+		common2.LogDebugWithFields(logrus.Fields{
+			"faceId": ipd.LogicFace.LogicFaceId,
+			"shard":  shard.index,
+		}, "Shard packet queue is full, drop packet")
+	}
+}
+
+// RegisterIdentifierTypeHandler
+// 为某种标识类型注册网络包处理函数
+//
+// @Description:
+//  应该在转发器启动之前调用，该标识类型同时需要出现在配置文件的 GeneralConfig.IdentifierType 中，对应的包才会被处理
+// @receiver f
+// @param identifierType
+// @param handler
+// @return error
+//
+func (f *Forwarder) RegisterIdentifierTypeHandler(identifierType uint64, handler IdentifierTypeHandler) error {
+	return f.identifierTypes.Register(identifierType, handler)
+}
+
+// GetIdentifierTypeDropCounts
+// 获取每种注册了处理函数的标识类型因为不在白名单中而被丢弃的包数
+//
+// @Description:
+// @receiver f
+// @return map[uint64]uint64
+//
+func (f *Forwarder) GetIdentifierTypeDropCounts() map[uint64]uint64 {
+	return f.identifierTypes.GetDropCounts()
+}
+
+// GetUnregisteredIdentifierTypeDropCount
+// 获取因为标识类型没有注册处理函数而被丢弃的包数
+//
+// @Description:
+// @receiver f
+// @return uint64
+//
+func (f *Forwarder) GetUnregisteredIdentifierTypeDropCount() uint64 {
+	return f.identifierTypes.GetUnregisteredDropCount()
+}
+
+// EnableRouterSignature
+// 开启中间路由器签名
+//
+// @Description:
+//  应该在转发器启动之前调用，签名在 Outgoing Interest 、 Outgoing Data 和 Outgoing GPPkt 管道中追加。传入的签名实现的个数
+//  最好和转发分片的个数（ GetShardNum ）相同，这样各个分片签名时不会互相等待
+// @receiver f
+// @param signers					签名的底层实现，其网络身份需要已经被解锁，每个签名实现同一时刻只会被一个分片使用
+// @param maxRouterSignatureNum		最多追加的中间路由器签名个数
+//
+func (f *Forwarder) EnableRouterSignature(signers []IRouterSigner, maxRouterSignatureNum int) {
+	f.routerSignature = CreateRouterSignature(signers, maxRouterSignatureNum)
+}
+
+// GetShardNum
+// 获取转发分片的个数
+//
+// @Description:
+// @receiver f
+// @return int
+//
+func (f *Forwarder) GetShardNum() int {
+	return len(f.shards)
 }
 
 // OnReceiveMINPacket 处理收到一个 MINPacket
 //
 // @Description:
-// 	1. 解析MINPacket，提取出标识区的第一个标识，根据第一个标识的类型对包进行分类
+// 	1. 解析MINPacket，提取出标识区的第一个标识；
+// 	2. 根据第一个标识的类型在 IdentifierTypeRegistry 中找到对应的处理函数，由处理函数将其解析成具体的网络包并传递给对应的转发管道；
+// 	3. 标识类型不在配置的白名单中或者没有注册处理函数时丢弃该包，并按照标识类型统计丢包数。
 // @receiver f
 // @param lf.IncomingPacketData
 //
@@ -157,73 +412,119 @@ func (f *Forwarder) OnReceiveMINPacket(ipd *lf.IncomingPacketData) {
 		return
 	}
 
-	// 根据标识的类型区分不同的网络包
-	switch identifyWrapper.GetIdentifierType() {
-	case encoding.TlvIdentifierCommon: // GPPkt
-		if gPPkt, err := packet.NewGPPktByMINPacket(minPacket); err != nil {
-			common2.LogWarnWithFields(logrus.Fields{
-				"faceId":     ingress.LogicFaceId,
-				"identifier": identifyWrapper.ToUri(),
-			}, "Create GPPkt by MINPacket failed")
-			return
-		} else {
-			f.OnIncomingGPPkt(ingress, gPPkt)
-		}
-	case encoding.TlvIdentifierContentInterest: // Interest
-		if interest, err := packet.NewInterestByMINPacket(minPacket); err != nil {
-			common2.LogWarnWithFields(logrus.Fields{
-				"faceId":     ingress.LogicFaceId,
-				"identifier": identifyWrapper.ToUri(),
-			}, "Create Interest by MINPacket failed")
-			return
-		} else {
-			if interest.NackHeader.IsInitial() {
-				nack := packet.NewNackByInterest(interest)
-				// Nack
-				f.OnIncomingNack(ingress, nack)
-			} else {
-				// Interest
-				f.OnIncomingInterest(ingress, interest)
-			}
-		}
-	case encoding.TlvIdentifierContentData: // data
-		if data, err := packet.NewDataByMINPacket(minPacket); err != nil {
-			common2.LogWarnWithFields(logrus.Fields{
-				"faceId":     ingress.LogicFaceId,
-				"identifier": identifyWrapper.ToUri(),
-			}, "Create data by MINPacket failed")
-			return
-		} else {
-			f.OnIncomingData(ingress, data)
+	// 根据标识的类型分发给不同的处理函数
+	identifierType := uint64(identifyWrapper.GetIdentifierType())
+	if !f.identifierTypes.Dispatch(identifierType, ingress, minPacket) {
+		common2.LogDebugWithFields(logrus.Fields{
+			"faceId":         ingress.LogicFaceId,
+			"identifier":     identifyWrapper.ToUri(),
+			"identifierType": identifierType,
+		}, "Identifier type not allowed, drop")
+		if counterType, ok := lf.GetMINPacketCounterType(minPacket); ok {
+			ingress.GetCounters().IncreaseDrop(counterType)
 		}
 	}
 }
 
+//
+// 处理第一个标识为通用标识的 MINPacket ，将其解析成 GPPkt
+//
+// @Description:
+// @receiver f
+// @param ingress
+// @param minPacket
+//
+func (f *Forwarder) onReceiveGPPktMINPacket(ingress *lf.LogicFace, minPacket *packet.MINPacket) {
+	gPPkt, err := packet.NewGPPktByMINPacket(minPacket)
+	if err != nil {
+		common2.LogWarnWithFields(logrus.Fields{
+			"faceId": ingress.LogicFaceId,
+		}, "Create GPPkt by MINPacket failed")
+		ingress.GetCounters().IncreaseDrop(lf.CounterPacketTypeGPPkt)
+		return
+	}
+	ingress.GetCounters().IncreaseIn(lf.CounterPacketTypeGPPkt)
+	f.OnIncomingGPPkt(ingress, gPPkt)
+}
+
+//
+// 处理第一个标识为内容兴趣标识的 MINPacket ，将其解析成 Interest 或者 Nack
+//
+// @Description:
+// @receiver f
+// @param ingress
+// @param minPacket
+//
+func (f *Forwarder) onReceiveInterestMINPacket(ingress *lf.LogicFace, minPacket *packet.MINPacket) {
+	interest, err := packet.NewInterestByMINPacket(minPacket)
+	if err != nil {
+		common2.LogWarnWithFields(logrus.Fields{
+			"faceId": ingress.LogicFaceId,
+		}, "Create Interest by MINPacket failed")
+		ingress.GetCounters().IncreaseDrop(lf.CounterPacketTypeInterest)
+		return
+	}
+	if interest.NackHeader.IsInitial() {
+		nack := packet.NewNackByInterest(interest)
+		// Nack
+		ingress.GetCounters().IncreaseIn(lf.CounterPacketTypeNack)
+		f.OnIncomingNack(ingress, nack)
+	} else {
+		// Interest
+		ingress.GetCounters().IncreaseIn(lf.CounterPacketTypeInterest)
+		f.OnIncomingInterest(ingress, interest)
+	}
+}
+
+//
+// 处理第一个标识为内容数据标识的 MINPacket ，将其解析成 Data
+//
+// @Description:
+// @receiver f
+// @param ingress
+// @param minPacket
+//
+func (f *Forwarder) onReceiveDataMINPacket(ingress *lf.LogicFace, minPacket *packet.MINPacket) {
+	data, err := packet.NewDataByMINPacket(minPacket)
+	if err != nil {
+		common2.LogWarnWithFields(logrus.Fields{
+			"faceId": ingress.LogicFaceId,
+		}, "Create data by MINPacket failed")
+		ingress.GetCounters().IncreaseDrop(lf.CounterPacketTypeData)
+		return
+	}
+	ingress.GetCounters().IncreaseIn(lf.CounterPacketTypeData)
+	f.OnIncomingData(ingress, data)
+}
+
 // OnIncomingInterest 处理一个兴趣包到来 （ Incoming Interest Pipeline）
 //
 // @Description:
 // 1. 首先给 Interest 的 TTL 减一，然后检查 TTL 的值是：
 //	- TTL < 0 则认为该兴趣包是一个回环的 Interest ，直接将其传递给 Interest loop 管道进一步处理；
 //	- TTL >= 0 则执行下一步。
-//	问题：下面第3步通过PIT条目进行回环的 Interest 检测，那为什么这边还需要一个 TTL 的机制来检测回环呢？
+//
+// 2. 查询 Dead Nonce List ，如果其中存在相同的 (标识, Nonce) 对，则认为该兴趣包是一个回环的 Interest ，直接将其传递给 Interest loop 管道
+//    进一步处理；否则执行下一步。
+//	问题：下面第4步通过PIT条目进行回环的 Interest 检测，那为什么这边还需要 Dead Nonce List 呢？
 //	 - 因为通过PIT条目对比 Nonce 的方式来检测循环存在一个问题，当一个兴趣包被转发出去，假设其对应的PIT条目的过期时间为 $x$ 秒，那如果这个兴趣
 //	   包在经过 $y$ 秒之后回环到当前路由器（$y > x$），则此时该兴趣包对应的PIT条目已经被移除，路由器无法通过PIT聚合的方式来检测回环的兴趣包；
-//   - 所以，为了解决上述问题，我们通过类比IP中简单的设置 TTL 的方式来检测上面描述的特殊情况下，不能检测回环 Interest 的问题。
-//   - NDN通过引入 Dead Nonce List 来进行检测，我们觉得会引入复杂性，且是概率性可靠的，直接用 TTL 的方式可以实现简单，且减少查询
-//     Dead Nonce List 的开销。
+//   - TTL 只能保证回环的兴趣包最终会被丢弃，在此之前它仍然可能被转发很多次；
+//   - 所以在 PIT 条目被移除时，将其 out-record 中的 Nonce 记录到 Dead Nonce List 中，在一段时间之内依然可以检测到回环的兴趣包，
+//     记录的存活时间会根据观察到的回环情况自适应调整。
 //
-// 2. 根据传入的 Interest 创建一个PIT条目（如果存在同名的PIT条目，则直接使用，不存在则创建）。
+// 3. 根据传入的 Interest 创建一个PIT条目（如果存在同名的PIT条目，则直接使用，不存在则创建）。
 //
-// 3. 然后查询PIT条目中是否有和传入的 Interest 的 Nonce 相同，并且是从不同的 LogicFace 收到的入记录（ in-records ），如果找到匹配的入记
+// 4. 然后查询PIT条目中是否有和传入的 Interest 的 Nonce 相同，并且是从不同的 LogicFace 收到的入记录（ in-records ），如果找到匹配的入记
 //    录，则认为传入的 Interest 是回环的循环 Interest ，直接将其传递给 Interest loop 管道进一步处理；否则执行下一步。
 //  - 如果从同一个逻辑接口收到同名且 Nonce 相同的 Interest，则可能是同一个消费者发送的 Interest，该 Interst 被判定为合法的重传包；
 //  - 如果从不同的逻辑接口收到同名且 Nonce 相同的 Interest，则可能是循环的 Interest 或者是同一个 Interest 沿着多个不同的路径到达，此时，将
 //    传入的 Interest 判定为循环的 Interest ，触发 Interest loop 管道。
 //
-// 4. 然后通过查询 PIT 条目中的记录，判断当前 Interst 是否是未决的 （ pending ），如果传入的 Interest 对应的PIT条目包含其它记录，则认为
+// 5. 然后通过查询 PIT 条目中的记录，判断当前 Interst 是否是未决的 （ pending ），如果传入的 Interest 对应的PIT条目包含其它记录，则认为
 //    该 Interest 是未决的。
 //
-// 5. 如果 Interest 是未决的，则直接传递给 ContentStore miss 管道处理；如果 Interest 不是未决的，则查询CS，如果存在缓存，则传递给
+// 6. 如果 Interest 是未决的，则直接传递给 ContentStore miss 管道处理；如果 Interest 不是未决的，则查询CS，如果存在缓存，则传递给
 //    ContentStore hit 管道进行进一步处理，否则传递给 Content miss 管道进行进一步的处理。
 // @param ingress	入口Face
 // @param interest	收到的内容兴趣包
@@ -233,6 +534,7 @@ func (f *Forwarder) OnIncomingInterest(ingress *lf.LogicFace, interest *packet.I
 		"faceId":   ingress.LogicFaceId,
 		"interest": interest.ToUri(),
 	}, "Incoming Interest")
+	atomic.AddUint64(&f.counters.NInInterests, 1)
 
 	// 调用插件锚点
 	if f.pluginManager.OnIncomingInterest(ingress, interest) != 0 {
@@ -246,6 +548,13 @@ func (f *Forwarder) OnIncomingInterest(ingress *lf.LogicFace, interest *packet.I
 	}
 	interest.TTL.Minus()
 
+	// Detect duplicate Nonce with Dead Nonce List
+	// PIT 条目被移除之后回环回来的兴趣包，需要通过 Dead Nonce List 检测
+	if f.deadNonceList(interest.GetName()).Has(interest.GetName(), &interest.Nonce) {
+		f.OnInterestLoop(ingress, interest)
+		return
+	}
+
 	// PIT insert
 	// 此时如果PIT条目已存在，则返回之前创建的PIT条目；
 	// 如果PIT条目不存在，会创建一个空条目（注意，此时只是创建PIT条目，并没有插入in-record）
@@ -292,12 +601,16 @@ func (f *Forwarder) OnInterestLoop(ingress *lf.LogicFace, interest *packet.Inter
 		"faceId":   ingress.LogicFaceId,
 		"interest": interest.ToUri(),
 	}, "Detect Interest loop")
+	atomic.AddUint64(&f.counters.NInterestLoops, 1)
 
 	// 调用插件锚点
 	if f.pluginManager.OnInterestLoop(ingress, interest) != 0 {
 		return
 	}
 
+	// 回环的兴趣包不会被转发，计入丢包
+	ingress.GetCounters().IncreaseDrop(lf.CounterPacketTypeInterest)
+
 	// 创建一个原因为 duplicate 的Nack
 	nack := packet.Nack{
 		Interest: interest,
@@ -326,6 +639,7 @@ func (f *Forwarder) OnContentStoreMiss(ingress *lf.LogicFace, pitEntry *table.PI
 		"faceId":   ingress.LogicFaceId,
 		"interest": interest.ToUri(),
 	}, "ContentStore miss")
+	atomic.AddUint64(&f.counters.NCsMisses, 1)
 
 	// 调用插件锚点
 	if f.pluginManager.OnContentStoreMiss(ingress, pitEntry, interest) != 0 {
@@ -380,6 +694,7 @@ func (f *Forwarder) OnContentStoreHit(ingress *lf.LogicFace, pitEntry *table.PIT
 		"faceId":   ingress.LogicFaceId,
 		"interest": interest.ToUri(),
 	}, "ContentStore hit")
+	atomic.AddUint64(&f.counters.NCsHits, 1)
 
 	// 调用插件锚点
 	if f.pluginManager.OnContentStoreHit(ingress, pitEntry, interest, data) != 0 {
@@ -414,6 +729,7 @@ func (f *Forwarder) OnOutgoingInterest(egress *lf.LogicFace, pitEntry *table.PIT
 		"faceId":   egress.LogicFaceId,
 		"interest": interest.ToUri(),
 	}, "Outgoing interest")
+	atomic.AddUint64(&f.counters.NOutInterests, 1)
 
 	// 调用插件锚点
 	if f.pluginManager.OnOutgoingInterest(egress, pitEntry, interest) != 0 {
@@ -421,8 +737,15 @@ func (f *Forwarder) OnOutgoingInterest(egress *lf.LogicFace, pitEntry *table.PIT
 	}
 
 	// 插入 out-record
+	now := common.GetCurrentTime()
 	outRecord := pitEntry.InsertOrUpdateOutRecord(egress, interest)
-	outRecord.ExpireTime = common.GetCurrentTime() + interest.InterestLifeTime.GetInterestLifeTime()
+	outRecord.SendTime = now
+	outRecord.ExpireTime = now + interest.InterestLifeTime.GetInterestLifeTime()
+
+	// 开启了中间路由器签名时，在发出之前为包的拷贝追加本路由器的签名，共享的包不会被修改
+	if f.routerSignature != nil {
+		interest = f.routerSignature.AppendToInterest(interest)
+	}
 
 	// 转发兴趣包
 	egress.SendInterest(interest)
@@ -448,6 +771,22 @@ func (f *Forwarder) OnInterestFinalize(pitEntry *table.PITEntry) {
 		return
 	}
 
+	// 如果 PIT 条目没有被满足，则在移除之前通知对应的策略，让策略有机会记录上游的超时信息
+	if pitEntry.IsSatisfied() {
+		atomic.AddUint64(&f.counters.NSatisfiedInterests, 1)
+	} else {
+		atomic.AddUint64(&f.counters.NUnsatisfiedInterests, 1)
+		if ste := f.StrategyTable.FindEffectiveStrategyEntry(pitEntry.GetIdentifier()); ste != nil {
+			ste.GetStrategy().BeforeExpirePendingInterest(pitEntry)
+		}
+	}
+
+	// 将 out-record 中的 Nonce 记录到 Dead Nonce List 中，PIT 条目被移除之后依然可以检测回环的兴趣包
+	deadNonceList := f.deadNonceList(pitEntry.GetIdentifier())
+	for _, outRecord := range pitEntry.GetOutRecords() {
+		deadNonceList.Add(pitEntry.GetIdentifier(), &outRecord.LastNonce)
+	}
+
 	// 将对应的PIT条目从PIT表中移除
 	if err := f.PIT.EraseByPITEntry(pitEntry); err != nil {
 		// 删除 PIT 条目失败，在这边输出提示信息
@@ -479,6 +818,7 @@ func (f *Forwarder) OnIncomingData(ingress *lf.LogicFace, data *packet.Data) {
 		"faceId": ingress.LogicFaceId,
 		"data":   data.ToUri(),
 	}, "Incoming data")
+	atomic.AddUint64(&f.counters.NInData, 1)
 
 	// 调用插件锚点
 	if f.pluginManager.OnIncomingData(ingress, data) != 0 {
@@ -489,6 +829,7 @@ func (f *Forwarder) OnIncomingData(ingress *lf.LogicFace, data *packet.Data) {
 	if data.TTL.Ttl() == 0 {
 		//f.OnInterestLoop(ingress, interest)
 		common2.LogDebug(fmt.Sprintf("%s TTL = 0 DROP", data.GetName().ToUri()))
+		ingress.GetCounters().IncreaseDrop(lf.CounterPacketTypeData)
 		return
 	}
 	data.TTL.Minus()
@@ -501,22 +842,27 @@ func (f *Forwarder) OnIncomingData(ingress *lf.LogicFace, data *packet.Data) {
 		return
 	}
 
+	// 标记 PITEntry 为 satisfied，需要在触发 PITEntry 的清除流程之前设置，避免被当成超时未满足的条目处理
+	pitEntry.SetSatisfied(true)
+
 	// 收到数据包之后，将对应的PIT条目的超时时间设置为当前时间，以触发 PITEntry 的清除流程
 	f.SetExpiryTime(pitEntry, 0)
 
 	// 判断是否需要缓存
 	if !data.NoCache.GetNoCache() {
 		// 找到对应的 PIT 条目
-		// 插入到CS缓存当中
-		f.ICS.Insert(data)
+		// 插入到CS缓存当中，可能被 CS 的准入策略拒绝
+		if _, err := f.ICS.Insert(data); err != nil {
+			common2.LogDebugWithFields(logrus.Fields{
+				"data": data.ToUri(),
+			}, "Data not cached: ", err)
+		}
 	}
 
 	// 调用对应策略的 StrategyBase::afterReceiveData 回调
 	if ste := f.StrategyTable.FindEffectiveStrategyEntry(data.GetName()); ste != nil {
 		// 调用策略
 		ste.GetStrategy().AfterReceiveData(ingress, data, pitEntry)
-		// 标记 PITEntry 为 satisfied
-		pitEntry.SetSatisfied(true)
 		// 清除对应的出记录
 		if err := pitEntry.DeleteOutRecord(ingress); err != nil {
 			// 删除出记录失败，这边输出错误
@@ -538,7 +884,8 @@ func (f *Forwarder) OnIncomingData(ingress *lf.LogicFace, data *packet.Data) {
 //  在 Incoming data 管道处理过程中发现 data 是未经请求的时后会触发 data unsolicited 管道处理逻辑，它的处理过程如下：
 //   1. 根据当前配置的针对未经请求的 data 的处理策略，决定是删除 data 还是将其添加到 ContentStore 。默认情况下，MIR配置了 drop-all 策略，
 //      该策略会丢弃所有未经请求的 data ，因为它们会对转发器造成安全风险。
-//   2. 在某些特殊应用场景下，如果希望MIR将未经请求的 data 存储到 ContentStore，可以在配置文件中修改对应的策略。
+//   2. 在某些特殊应用场景下，如果希望MIR将未经请求的 data 存储到 ContentStore，可以在配置文件中修改对应的策略；
+//   3. 在此之前触发 data 所在命名空间的策略的 AfterReceiveUnsolicitedData 触发器，策略可以据此更新自己的测量信息。
 // @param ingress
 // @param data
 //
@@ -547,14 +894,27 @@ func (f *Forwarder) OnDataUnsolicited(ingress *lf.LogicFace, data *packet.Data)
 		"faceId": ingress.LogicFaceId,
 		"data":   data.ToUri(),
 	}, "data unsolicited")
+	atomic.AddUint64(&f.counters.NUnsolicitedData, 1)
 
 	// 调用插件锚点
 	if f.pluginManager.OnDataUnsolicited(ingress, data) != 0 {
 		return
 	}
+
+	// 通知 data 所在命名空间的策略，策略只能据此更新自己的状态，不能转发 data
+	if ste := f.StrategyTable.FindEffectiveStrategyEntry(data.GetName()); ste != nil {
+		ste.GetStrategy().AfterReceiveUnsolicitedData(ingress, data)
+	}
+
+	// 未经请求的 data 不会被转发，计入丢包
+	ingress.GetCounters().IncreaseDrop(lf.CounterPacketTypeData)
 	// 读取配置文件，判断是否缓存未经请求的 data
 	if f.config.TableConfig.CacheUnsolicitedData {
-		f.ICS.Insert(data)
+		if _, err := f.ICS.Insert(data); err != nil {
+			common2.LogDebugWithFields(logrus.Fields{
+				"data": data.ToUri(),
+			}, "Unsolicited data not cached: ", err)
+		}
 	}
 }
 
@@ -572,12 +932,18 @@ func (f *Forwarder) OnOutgoingData(egress *lf.LogicFace, data *packet.Data) {
 		"faceId": egress.LogicFaceId,
 		"data":   data.ToUri(),
 	}, "Outgoing data")
+	atomic.AddUint64(&f.counters.NOutData, 1)
 
 	// 调用插件锚点
 	if f.pluginManager.OnOutgoingData(egress, data) != 0 {
 		return
 	}
 
+	// 开启了中间路由器签名时，在发出之前为包的拷贝追加本路由器的签名，共享的包不会被修改
+	if f.routerSignature != nil {
+		data = f.routerSignature.AppendToData(data)
+	}
+
 	egress.SendData(data)
 }
 
@@ -599,6 +965,7 @@ func (f *Forwarder) OnIncomingNack(ingress *lf.LogicFace, nack *packet.Nack) {
 		"interest": nack.Interest.ToUri(),
 		"reason":   nack.GetNackReason(),
 	}, "Incoming Nack")
+	atomic.AddUint64(&f.counters.NInNacks, 1)
 
 	// 调用插件锚点
 	if f.pluginManager.OnIncomingNack(ingress, nack) != 0 {
@@ -613,6 +980,7 @@ func (f *Forwarder) OnIncomingNack(ingress *lf.LogicFace, nack *packet.Nack) {
 			"nack":   nack.Interest.ToUri(),
 			"reason": nack.GetNackReason(),
 		}, "Have not found match PITEntry for nack")
+		ingress.GetCounters().IncreaseDrop(lf.CounterPacketTypeNack)
 		return
 	}
 
@@ -623,6 +991,7 @@ func (f *Forwarder) OnIncomingNack(ingress *lf.LogicFace, nack *packet.Nack) {
 			"nack":   nack.Interest.ToUri(),
 			"reason": nack.GetNackReason(),
 		}, "Have not found match out-record for nack")
+		ingress.GetCounters().IncreaseDrop(lf.CounterPacketTypeNack)
 		return
 	}
 
@@ -633,15 +1002,18 @@ func (f *Forwarder) OnIncomingNack(ingress *lf.LogicFace, nack *packet.Nack) {
 			"nack":   nack.Interest.ToUri(),
 			"reason": nack.GetNackReason(),
 		}, "Founded matched out-record, but Nonce is diff")
+		ingress.GetCounters().IncreaseDrop(lf.CounterPacketTypeNack)
 		return
 	}
 	outRecord.NackHeader = &nack.Interest.NackHeader
 
-	// 如果所有 out-record 都超时或者被 Nack，则触发 PIT 条目过期
+	// 如果所有 out-record 都超时或者被 Nack，则触发 PIT 条目过期（只要还有一个 out-record 未超时且未被 Nack ，就继续等待）
 	finished := true
+	now := common.GetCurrentTime()
 	for _, or := range pitEntry.GetOutRecords() {
-		if or.ExpireTime > common.GetCurrentTime() && or.NackHeader != nil {
+		if or.ExpireTime > now && or.NackHeader == nil {
 			finished = false
+			break
 		}
 	}
 	if finished {
@@ -676,6 +1048,7 @@ func (f *Forwarder) OnOutgoingNack(egress *lf.LogicFace, pitEntry *table.PITEntr
 		"pitEntry": pitEntry.GetIdentifier().ToUri(),
 		"reason":   header.GetNackReason(),
 	}, "Outgoing Nack")
+	atomic.AddUint64(&f.counters.NOutNacks, 1)
 
 	// 调用插件锚点
 	if f.pluginManager.OnOutgoingNack(egress, pitEntry, header) != 0 {
@@ -705,7 +1078,10 @@ func (f *Forwarder) OnOutgoingNack(egress *lf.LogicFace, pitEntry *table.PITEntr
 //     - TTL >= 0 则执行下一步。
 //   > 因为 GPPkt 是一种推式语义的网络包，不能向 Interest 那样通过 PIT 聚合来检测回环，所以这边和 IP 一样使用 TTL 来避免网络包无限回环。
 //
-//  2. 接着调用对应策略的 StrategyBase::afterReceiveGPPkt 回调，在其中触发 Outgoing GPPkt 管道。
+//  2. 然后查询 GPPkt 去重缓存，如果最近已经收到过相同的 GPPkt（源标识、目的标识和负载都相同），则认为是通过多路径或者多播到达的重复包，
+//     直接丢弃并增加丢包计数；否则将其记录到去重缓存中，执行下一步。
+//
+//  3. 接着调用对应策略的 StrategyBase::afterReceiveGPPkt 回调，在其中触发 Outgoing GPPkt 管道。
 // @param ingress
 // @param gPPkt
 //
@@ -714,6 +1090,7 @@ func (f *Forwarder) OnIncomingGPPkt(ingress *lf.LogicFace, gPPkt *packet.GPPkt)
 		"faceId": ingress.LogicFaceId,
 		"gPPkt":  gPPkt.ToUri(),
 	}, "Incoming GPPkt")
+	atomic.AddUint64(&f.counters.NInGPPkts, 1)
 
 	// 调用插件锚点
 	if f.pluginManager.OnIncomingGPPkt(ingress, gPPkt) != 0 {
@@ -726,10 +1103,21 @@ func (f *Forwarder) OnIncomingGPPkt(ingress *lf.LogicFace, gPPkt *packet.GPPkt)
 			"faceId": ingress.LogicFaceId,
 			"gPPkt":  gPPkt.ToUri(),
 		}, "GPPkt TTL < 0")
+		ingress.GetCounters().IncreaseDrop(lf.CounterPacketTypeGPPkt)
 		return
 	}
 	gPPkt.TTL.Minus()
 
+	// 丢弃最近已经收到过的重复包
+	if dedupCache := f.gPPktDedupCache(gPPkt.DstIdentifier()); dedupCache != nil && dedupCache.IsDuplicate(gPPkt) {
+		common2.LogDebugWithFields(logrus.Fields{
+			"faceId": ingress.LogicFaceId,
+			"gPPkt":  gPPkt.ToUri(),
+		}, "Duplicate GPPkt")
+		ingress.GetCounters().IncreaseDrop(lf.CounterPacketTypeGPPkt)
+		return
+	}
+
 	// 调用 StrategyBase::afterReceiveGPPkt
 	if ste := f.StrategyTable.FindEffectiveStrategyEntry(gPPkt.DstIdentifier()); ste != nil {
 		ste.GetStrategy().AfterReceiveGPPkt(ingress, gPPkt)
@@ -754,15 +1142,38 @@ func (f *Forwarder) OnOutgoingGPPkt(egress *lf.LogicFace, gPPkt *packet.GPPkt) {
 		"faceId": egress.LogicFaceId,
 		"gPPkt":  gPPkt.ToUri(),
 	}, "Outgoing GPPkt")
+	atomic.AddUint64(&f.counters.NOutGPPkts, 1)
 
 	// 调用插件锚点
 	if f.pluginManager.OnOutgoingGPPkt(egress, gPPkt) != 0 {
 		return
 	}
 
+	// 开启了中间路由器签名时，在发出之前为包的拷贝追加本路由器的签名，共享的包不会被修改
+	if f.routerSignature != nil {
+		gPPkt = f.routerSignature.AppendToGPPkt(gPPkt)
+	}
+
 	egress.SendGPPkt(gPPkt)
 }
 
+// GetDuplicateGPPktDropCount
+// 获取因为重复被丢弃的 GPPkt 的个数
+//
+// @Description:
+// @receiver f
+// @return uint64
+//
+func (f *Forwarder) GetDuplicateGPPktDropCount() uint64 {
+	count := uint64(0)
+	for _, shard := range f.shards {
+		if shard.gPPktDedup != nil {
+			count += shard.gPPktDedup.GetDropCount()
+		}
+	}
+	return count
+}
+
 // SetExpiryTime
 // 设置 PIT 条目的超时时间，并在超时时触发 OnInterestFinalize 管道
 //
@@ -775,18 +1186,38 @@ func (f *Forwarder) SetExpiryTime(pitEntry *table.PITEntry, duration int64) {
 	// TODO: 这边要check一下，是不是调用 SetExpiryTime 的时候之前的定时任务还没有触发，如果已经触发过了，是不是会有问题
 
 	key := pitEntry.Identifier.ToUri()
+	// PIT 条目的超时事件由其所在分片的堆定时器处理
+	heapTimer := f.shardTimer(pitEntry.Identifier)
 
 	// 首先取消之前的定时任务
-	f.heapTimer.CancelEvent(key)
+	heapTimer.CancelEvent(key)
 
-	// 接着设置新的定时任务
-	f.heapTimer.AddTimeoutEvent(duration, key, func() {
+	// 接着设置新的定时任务，duration 为 0 的事件会在当前网络包处理完之后，由分片的处理循环立即处理
+	heapTimer.AddTimeoutEvent(duration, key, func() {
 		f.OnInterestFinalize(pitEntry)
 	})
+}
 
-	if duration == 0 {
-		f.heapTimer.DealEvent()
-	}
+// GetCounters
+// 获取转发器全局统计信息的一个拷贝
+//
+// @Description:
+// @receiver f
+// @return ForwarderCounters
+//
+func (f *Forwarder) GetCounters() ForwarderCounters {
+	return f.counters.Snapshot()
+}
+
+// GetStartTime
+// 获取转发器的启动时间，单位 ms
+//
+// @Description:
+// @receiver f
+// @return uint64
+//
+func (f *Forwarder) GetStartTime() uint64 {
+	return f.startTime
 }
 
 func (f *Forwarder) GetFIB() *table.FIB {
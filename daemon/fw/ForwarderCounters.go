@@ -0,0 +1,78 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 10:30 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import "sync/atomic"
+
+// ForwarderCounters
+// 转发器全局的统计信息
+//
+// @Description:
+//  1. 转发器的多个分片协程会并发更新这些计数，所以所有计数都通过原子操作更新，读取时应该使用 Snapshot 获取一个一致的拷贝；
+//  2. In/Out 计数在对应的转发管道入口处统计，被插件拦截的包同样会被统计；
+//  3. NSatisfiedInterests 和 NUnsatisfiedInterests 在 Interest finalize 管道中根据 PIT 条目是否被满足统计；
+//  4. NShardQueueDrops 在分发协程中统计，分片处理不过来时网络包会被直接丢弃，而不是阻塞分发协程。
+//
+type ForwarderCounters struct {
+	NInInterests          uint64 // 收到的兴趣包的个数
+	NOutInterests         uint64 // 发出的兴趣包的个数
+	NInData               uint64 // 收到的数据包的个数
+	NOutData              uint64 // 发出的数据包的个数
+	NInNacks              uint64 // 收到的 Nack 的个数
+	NOutNacks             uint64 // 发出的 Nack 的个数
+	NInGPPkts             uint64 // 收到的普通推式包的个数
+	NOutGPPkts            uint64 // 发出的普通推式包的个数
+	NCsHits               uint64 // 命中缓存的兴趣包的个数
+	NCsMisses             uint64 // 未命中缓存的兴趣包的个数
+	NUnsolicitedData      uint64 // 收到的未经请求的数据包的个数
+	NInterestLoops        uint64 // 检测到的回环兴趣包的个数
+	NSatisfiedInterests   uint64 // 被满足的 PIT 条目的个数
+	NUnsatisfiedInterests uint64 // 超时或者被 Nack 而未被满足的 PIT 条目的个数
+	NShardQueueDrops      uint64 // 因为分片包队列已满而被丢弃的网络包的个数
+}
+
+// Snapshot
+// 获取当前所有计数的一个拷贝
+//
+// @Description:
+// @receiver c
+// @return ForwarderCounters
+//
+func (c *ForwarderCounters) Snapshot() ForwarderCounters {
+	return ForwarderCounters{
+		NInInterests:          atomic.LoadUint64(&c.NInInterests),
+		NOutInterests:         atomic.LoadUint64(&c.NOutInterests),
+		NInData:               atomic.LoadUint64(&c.NInData),
+		NOutData:              atomic.LoadUint64(&c.NOutData),
+		NInNacks:              atomic.LoadUint64(&c.NInNacks),
+		NOutNacks:             atomic.LoadUint64(&c.NOutNacks),
+		NInGPPkts:             atomic.LoadUint64(&c.NInGPPkts),
+		NOutGPPkts:            atomic.LoadUint64(&c.NOutGPPkts),
+		NCsHits:               atomic.LoadUint64(&c.NCsHits),
+		NCsMisses:             atomic.LoadUint64(&c.NCsMisses),
+		NUnsolicitedData:      atomic.LoadUint64(&c.NUnsolicitedData),
+		NInterestLoops:        atomic.LoadUint64(&c.NInterestLoops),
+		NSatisfiedInterests:   atomic.LoadUint64(&c.NSatisfiedInterests),
+		NUnsatisfiedInterests: atomic.LoadUint64(&c.NUnsatisfiedInterests),
+		NShardQueueDrops:      atomic.LoadUint64(&c.NShardQueueDrops),
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 11:00 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"github.com/sirupsen/logrus"
+	common2 "minlib/common"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/table"
+)
+
+// SubscribeLogicFaceEvents
+// 订阅 LogicFace 的生命周期事件
+//
+// @Description:
+//  1. LogicFace 被创建时，触发所有策略的 OnFaceUp ；
+//  2. LogicFace 被关闭时，在每个分片协程中清理 PIT 中指向该 LogicFace 的 in-record 和 out-record ，并触发所有策略的 OnFaceDown 。
+// @receiver f
+// @param eventBus
+//
+func (f *Forwarder) SubscribeLogicFaceEvents(eventBus *lf.LogicFaceEventBus) {
+	eventBus.Subscribe(f.onLogicFaceEvent)
+}
+
+//
+// 处理 LogicFace 的生命周期事件
+//
+// @Description:
+//  本函数在发布事件的协程中执行，不能直接访问分片独占的 PIT ，需要将清理任务投递到各个分片协程中
+// @receiver f
+// @param event
+//
+func (f *Forwarder) onLogicFaceEvent(event *lf.LogicFaceEvent) {
+	face := event.LogicFace
+	switch event.Type {
+	case lf.LogicFaceEventCreated:
+		for _, ste := range f.StrategyTable.GetAllEntry() {
+			ste.GetStrategy().OnFaceUp(face)
+		}
+	case lf.LogicFaceEventDestroyed:
+		common2.LogDebugWithFields(logrus.Fields{
+			"faceId": face.LogicFaceId,
+		}, "LogicFace destroyed, clean up PIT")
+		for _, shard := range f.shards {
+			shard := shard
+			f.runOnShard(shard, func() {
+				f.cleanupOnFaceDown(shard, face)
+			})
+		}
+	}
+}
+
+//
+// 将一个任务投递到分片协程中执行
+//
+// @Description:
+//  转发器正在停止或者已经崩溃时直接丢弃任务，此时剩余的 PIT 条目由 Forwarder.Stop 统一处理
+// @receiver f
+// @param shard
+// @param task
+//
+func (f *Forwarder) runOnShard(shard *forwarderShard, task func()) {
+	select {
+	case shard.taskChan <- task:
+	case <-f.stopChan:
+	}
+}
+
+//
+// LogicFace 被关闭之后，清理某个分片 PIT 中和该 LogicFace 相关的记录
+//
+// @Description:
+//  1. 删除指向 face 的 in-record ，如果 PIT 条目已经没有任何 in-record ，说明没有下游在等待，将其设置为立即过期；
+//  2. 删除指向 face 的 out-record ，并将其中的 Nonce 记录到 Dead Nonce List 中，避免之后回环回来的兴趣包被当成新的兴趣包；
+//  3. 对于删除了 out-record 并且仍然有下游在等待的 PIT 条目，按照其所属的策略分组，通过 OnFaceDown 交给策略处理；
+//  4. 所有策略都会被触发一次 OnFaceDown ，即使没有受影响的 PIT 条目，策略也可以借此清理自己维护的和 face 相关的状态。
+//  本函数只能在 shard 的协程中调用。
+// @receiver f
+// @param shard
+// @param face
+//
+func (f *Forwarder) cleanupOnFaceDown(shard *forwarderShard, face *lf.LogicFace) {
+	affectedEntries := make(map[table.IStrategy][]*table.PITEntry)
+	for _, pitEntry := range shard.pit.GetAllEntry() {
+		if pitEntry.IsDeleted() {
+			continue
+		}
+		_, inErr := pitEntry.GetInRecord(face)
+		outRecord, outErr := pitEntry.GetOutRecord(face)
+		if inErr != nil && outErr != nil {
+			continue
+		}
+		if inErr == nil {
+			_ = pitEntry.DeleteInRecord(face)
+		}
+		if outErr == nil {
+			shard.dnl.Add(pitEntry.GetIdentifier(), &outRecord.LastNonce)
+			_ = pitEntry.DeleteOutRecord(face)
+		}
+
+		if !pitEntry.HasInRecords() {
+			f.SetExpiryTime(pitEntry, 0)
+			continue
+		}
+		if outErr == nil {
+			if ste := f.StrategyTable.FindEffectiveStrategyEntry(pitEntry.GetIdentifier()); ste != nil {
+				strategy := ste.GetStrategy()
+				affectedEntries[strategy] = append(affectedEntries[strategy], pitEntry)
+			}
+		}
+	}
+
+	for _, ste := range f.StrategyTable.GetAllEntry() {
+		strategy := ste.GetStrategy()
+		strategy.OnFaceDown(face, affectedEntries[strategy])
+	}
+}
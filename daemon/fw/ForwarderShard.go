@@ -0,0 +1,273 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 5:30 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"hash/fnv"
+	"minlib/component"
+	"minlib/packet"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/table"
+	"mir-go/daemon/utils"
+	"time"
+)
+
+const shardTaskQueueSize = 16 // 每个分片任务队列的大小
+
+// forwarderShard
+// 转发分片
+//
+// @Description:
+//  1. 转发平面按照网络包第一个标识的哈希值被划分成多个分片，每个分片由一个独立的协程处理；
+//  2. 每个分片独占自己的一部分 PIT 表、Dead Nonce List 、GPPkt 去重缓存以及处理 PIT 超时事件的堆定时器。PIT 表和堆定时器只会被
+//     分片自己的协程访问，没有加锁；Dead Nonce List 和 GPPkt 去重缓存内部仍然各自带有一把锁，但是只有分片自己的协程会获取，不会
+//     产生竞争；
+//  3. 同一个名字的 Interest、Data 和 Nack 总是被分发到同一个分片，所以 PIT 的聚合、匹配和超时处理都可以在分片内部完成；
+//  4. FIB、CS 以及策略选择表依旧是所有分片共享的；
+//  5. 其它协程需要访问分片独占的状态时（例如 LogicFace 被关闭时清理 PIT），需要通过 taskChan 将任务投递到分片协程中执行。
+//
+type forwarderShard struct {
+	index      int                         // 分片编号
+	pit        table.PIT                   // 分片独占的 PIT 表
+	dnl        *table.DeadNonceList        // 分片独占的 Dead Nonce List
+	gPPktDedup *table.GPPktDedupCache      // 分片独占的 GPPkt 去重缓存，为 nil 时不进行去重
+	heapTimer  *utils.DeadlineTimer        // 分片独占的堆定时器，用来处理PIT的超时事件
+	packetChan chan *lf.IncomingPacketData // 分片的包队列，满了之后新到的网络包会被丢弃
+	taskChan   chan func()                 // 分片的任务队列，其中的任务在分片协程中执行
+}
+
+// newForwarderShard
+// 新建一个转发分片
+//
+// @Description:
+// @param index
+// @param queueSize		分片包队列的大小
+// @param dedupSize		GPPkt 去重缓存的大小，小于等于0时不进行去重
+// @param dedupLifetime	GPPkt 去重缓存中记录的存活时间，单位 ms
+// @return *forwarderShard
+//
+func newForwarderShard(index int, queueSize int, dedupSize int, dedupLifetime uint64) *forwarderShard {
+	shard := &forwarderShard{
+		index:      index,
+		dnl:        table.CreateDeadNonceList(),
+		heapTimer:  utils.NewDeadlineTimer(),
+		packetChan: make(chan *lf.IncomingPacketData, queueSize),
+		taskChan:   make(chan func(), shardTaskQueueSize),
+	}
+	shard.pit.Init()
+	if dedupSize > 0 {
+		shard.gPPktDedup = table.CreateGPPktDedupCache(dedupSize, dedupLifetime)
+	}
+	return shard
+}
+
+//
+// 分片的处理循环
+//
+// @Description:
+//  在一个 select 中同时等待分片的包队列、任务队列以及一个定时器，定时器总是被设置为堆定时器中最早的到期时间：
+//   1. 收到网络包时交给转发管道处理；
+//   2. 收到任务时直接执行；
+//   3. 每次被唤醒之后都处理所有已经到期的超时事件，包括转发管道中通过 SetExpiryTime 设置为立即到期的事件。
+//  没有网络包和超时事件时，协程会一直阻塞在 select 上，不会空转。分片的包队列被关闭并且其中的网络包都处理完之后，协程退出。
+// @receiver s
+// @param f
+// @param onPanic		分片协程 panic 时的回调
+//
+func (s *forwarderShard) run(f *Forwarder, onPanic func(err interface{})) {
+	utils.ProtectRun(func() {
+		timer := time.NewTimer(0)
+		if !timer.Stop() {
+			<-timer.C
+		}
+		// timerArmed 表示 timer 是否正在计时
+		timerArmed := false
+		for true {
+			// 将定时器设置为最早的到期时间
+			var timerChan <-chan time.Time
+			if deadline, ok := s.heapTimer.NextDeadline(); ok {
+				if timerArmed && !timer.Stop() {
+					<-timer.C
+				}
+				timer.Reset(time.Until(deadline))
+				timerArmed = true
+				timerChan = timer.C
+			}
+
+			select {
+			case ipd, ok := <-s.packetChan:
+				if !ok {
+					// 转发器正在停止，剩余的 PIT 条目由 Forwarder.Stop 统一处理
+					timer.Stop()
+					return
+				}
+				f.OnReceiveMINPacket(ipd)
+			case task := <-s.taskChan:
+				task()
+			case <-timerChan:
+				timerArmed = false
+			}
+
+			s.heapTimer.DealEvent()
+		}
+	}, onPanic)
+}
+
+//
+// 计算一个标识所属的分片编号
+//
+// @Description:
+// @param uri			标识的 URI
+// @param shardNum		分片数
+// @return int
+//
+func shardIndex(uri string, shardNum int) int {
+	if shardNum <= 1 {
+		return 0
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(uri))
+	return int(hasher.Sum32() % uint32(shardNum))
+}
+
+//
+// 获取某个标识所在分片的 Dead Nonce List
+//
+// @Description:
+// @receiver f
+// @param identifier
+// @return *table.DeadNonceList
+//
+func (f *Forwarder) deadNonceList(identifier *component.Identifier) *table.DeadNonceList {
+	return f.shards[shardIndex(identifier.ToUri(), len(f.shards))].dnl
+}
+
+//
+// 获取某个标识所在分片的堆定时器
+//
+// @Description:
+//  堆定时器没有加锁，只能在该分片的协程中访问
+// @receiver f
+// @param identifier
+// @return *utils.DeadlineTimer
+//
+func (f *Forwarder) shardTimer(identifier *component.Identifier) *utils.DeadlineTimer {
+	return f.shards[shardIndex(identifier.ToUri(), len(f.shards))].heapTimer
+}
+
+//
+// 获取某个标识所在分片的 GPPkt 去重缓存
+//
+// @Description:
+// @receiver f
+// @param identifier
+// @return *table.GPPktDedupCache		没有开启去重时返回 nil
+//
+func (f *Forwarder) gPPktDedupCache(identifier *component.Identifier) *table.GPPktDedupCache {
+	return f.shards[shardIndex(identifier.ToUri(), len(f.shards))].gPPktDedup
+}
+
+// ShardedPIT
+// 将对 PIT 的访问路由到标识所在的分片
+//
+// @Description:
+//  对外提供和 table.PIT 一致的接口，转发管道可以像使用单个 PIT 一样使用它
+//
+type ShardedPIT struct {
+	shards []*forwarderShard
+}
+
+//
+// 获取某个标识所在分片的 PIT
+//
+// @Description:
+// @receiver sp
+// @param uri
+// @return *table.PIT
+//
+func (sp *ShardedPIT) shardPIT(uri string) *table.PIT {
+	return &sp.shards[shardIndex(uri, len(sp.shards))].pit
+}
+
+// Size
+// 返回所有分片中 PIT 表项数之和
+//
+// @Description:
+// @receiver sp
+// @return uint64
+//
+func (sp *ShardedPIT) Size() uint64 {
+	size := uint64(0)
+	for _, shard := range sp.shards {
+		size += shard.pit.Size()
+	}
+	return size
+}
+
+// Find
+// 在兴趣包所在分片中精准匹配查找对应的PITEntry
+//
+// @Description:
+// @receiver sp
+// @param interest
+// @return *table.PITEntry
+// @return error
+//
+func (sp *ShardedPIT) Find(interest *packet.Interest) (*table.PITEntry, error) {
+	return sp.shardPIT(interest.GetName().ToUri()).Find(interest)
+}
+
+// Insert
+// 在兴趣包所在分片中插入PITEntry
+//
+// @Description:
+// @receiver sp
+// @param interest
+// @return *table.PITEntry
+//
+func (sp *ShardedPIT) Insert(interest *packet.Interest) *table.PITEntry {
+	return sp.shardPIT(interest.GetName().ToUri()).Insert(interest)
+}
+
+// FindDataMatches
+// 在数据包所在分片中获取匹配的PITEntry
+//
+// @Description:
+// @receiver sp
+// @param data
+// @return *table.PITEntry
+//
+func (sp *ShardedPIT) FindDataMatches(data *packet.Data) *table.PITEntry {
+	return sp.shardPIT(data.GetName().ToUri()).FindDataMatches(data)
+}
+
+// EraseByPITEntry
+// 从PITEntry所在分片中删除PITEntry
+//
+// @Description:
+// @receiver sp
+// @param pitEntry
+// @return error
+//
+func (sp *ShardedPIT) EraseByPITEntry(pitEntry *table.PITEntry) error {
+	return sp.shardPIT(pitEntry.GetIdentifier().ToUri()).EraseByPITEntry(pitEntry)
+}
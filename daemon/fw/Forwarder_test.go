@@ -26,7 +26,6 @@ import (
 	"fmt"
 	"minlib/component"
 	"minlib/packet"
-	"minlib/utils"
 	"mir-go/daemon/lf"
 	"mir-go/daemon/plugin"
 	"testing"
@@ -35,7 +34,7 @@ import (
 func TestForwarder_Init(t *testing.T) {
 	forwarder := new(Forwarder)
 	newPlugin := new(plugin.GlobalPluginManager)
-	queue := utils.NewBlockQueue(20)
+	queue := make(chan *lf.IncomingPacketData, 20)
 	forwarder.Init(nil, newPlugin, queue)
 	fmt.Println("forwarder", forwarder.FIB.GetDepth(), forwarder.PIT.Size())
 	face := new(lf.LogicFace)
@@ -70,7 +69,7 @@ func TestForwarder_OnOutgoingInterest(t *testing.T) {
 	newName1, _ := component.CreateIdentifierByString("/min")
 	forwarder := new(Forwarder)
 	newPlugin := new(plugin.GlobalPluginManager)
-	queue := utils.NewBlockQueue(20)
+	queue := make(chan *lf.IncomingPacketData, 20)
 	forwarder.Init(nil, newPlugin, queue)
 	forwarder.SetDefaultStrategy("/")
 	forwarder.StrategyTable.Init()
@@ -105,7 +104,7 @@ func TestForwarder_OnInterestLoop(t *testing.T) {
 	newName1, _ := component.CreateIdentifierByString("/min")
 	forwarder := new(Forwarder)
 	newPlugin := new(plugin.GlobalPluginManager)
-	queue := utils.NewBlockQueue(20)
+	queue := make(chan *lf.IncomingPacketData, 20)
 	forwarder.Init(nil, newPlugin, queue)
 	forwarder.SetDefaultStrategy("/")
 	forwarder.StrategyTable.Init()
@@ -141,7 +140,7 @@ func TestForwarder_OnContentStoreHit(t *testing.T) {
 
 	forwarder := new(Forwarder)
 	newPlugin := new(plugin.GlobalPluginManager)
-	queue := utils.NewBlockQueue(20)
+	queue := make(chan *lf.IncomingPacketData, 20)
 	forwarder.Init(nil, newPlugin, queue)
 	fmt.Println("forwarder", forwarder.FIB.GetDepth(), forwarder.PIT.Size())
 	brs := BestRouteStrategy{StrategyBase{forwarder: forwarder}}
@@ -0,0 +1,256 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 1:10 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"fmt"
+	"minlib/packet"
+	"mir-go/daemon/lf"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// IdentifierTypeHandler
+// 处理第一个标识为某种类型的 MINPacket
+//
+// @Description:
+//  处理函数负责将 MINPacket 解析成具体的网络包，并传递给对应的转发管道，解析失败时处理函数自己负责统计和记录日志
+// @param ingress		MINPacket 到来的入口 LogicFace
+// @param minPacket		收到的 MINPacket
+//
+type IdentifierTypeHandler func(ingress *lf.LogicFace, minPacket *packet.MINPacket)
+
+// IdentifierTypeRegistry
+// 标识类型 => 网络包处理函数的注册表
+//
+// @Description:
+//  1. 转发器根据 MINPacket 第一个标识的类型，将其分发给对应的处理函数，新增一种标识类型时只需要注册一个新的处理函数；
+//  2. 白名单为配置文件中 GeneralConfig.IdentifierType 列出的标识类型，不在白名单中或者没有注册处理函数的标识类型的包都会被丢弃，
+//     注册了处理函数的标识类型按照类型分别统计丢包数，其余的标识类型都来自网络，取值不受控制，统一记在一个计数器中，
+//     以免丢包计数表被任意的标识类型撑大；没有设置白名单时，所有注册了处理函数的标识类型都被允许；
+//  3. 处理函数和白名单应该在转发器启动之前设置好，之后只有丢包计数会被并发修改，丢包计数使用原子操作，分发时只需要持有读锁。
+//
+type IdentifierTypeRegistry struct {
+	unregisteredDrops uint64                           // 没有注册处理函数的标识类型的丢包数，放在第一个字段以保证原子操作时 64 位对齐
+	handlers          map[uint64]IdentifierTypeHandler // 标识类型 => 处理函数
+	allowlist         map[uint64]bool                  // 允许的标识类型，为 nil 时允许所有注册了处理函数的标识类型
+	drops             map[uint64]*uint64               // 注册了处理函数的标识类型 => 丢包数，在注册时创建
+	lock              sync.RWMutex
+}
+
+// CreateIdentifierTypeRegistry
+// 创建一个标识类型注册表
+//
+// @Description:
+// @return *IdentifierTypeRegistry
+//
+func CreateIdentifierTypeRegistry() *IdentifierTypeRegistry {
+	return &IdentifierTypeRegistry{
+		handlers: make(map[uint64]IdentifierTypeHandler),
+		drops:    make(map[uint64]*uint64),
+	}
+}
+
+// Register
+// 为某种标识类型注册处理函数
+//
+// @Description:
+// @receiver r
+// @param identifierType
+// @param handler
+// @return error		处理函数为 nil 或者该标识类型已经注册过时返回错误
+//
+func (r *IdentifierTypeRegistry) Register(identifierType uint64, handler IdentifierTypeHandler) error {
+	if handler == nil {
+		return createIdentifierTypeRegistryErrorByType(InvalidIdentifierTypeHandlerError, identifierType)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.handlers[identifierType]; ok {
+		return createIdentifierTypeRegistryErrorByType(IdentifierTypeAlreadyRegisteredError, identifierType)
+	}
+	r.handlers[identifierType] = handler
+	r.drops[identifierType] = new(uint64)
+	return nil
+}
+
+// SetAllowlist
+// 设置允许的标识类型
+//
+// @Description:
+// @receiver r
+// @param identifierTypes		为 nil 时允许所有注册了处理函数的标识类型
+//
+func (r *IdentifierTypeRegistry) SetAllowlist(identifierTypes []uint64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if identifierTypes == nil {
+		r.allowlist = nil
+		return
+	}
+	r.allowlist = make(map[uint64]bool)
+	for _, identifierType := range identifierTypes {
+		r.allowlist[identifierType] = true
+	}
+}
+
+// IsAllowed
+// 判断某种标识类型的包是否可以被处理，即在白名单中并且注册了处理函数
+//
+// @Description:
+// @receiver r
+// @param identifierType
+// @return bool
+//
+func (r *IdentifierTypeRegistry) IsAllowed(identifierType uint64) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	_, ok := r.lookup(identifierType)
+	return ok
+}
+
+// Dispatch
+// 将 MINPacket 分发给其标识类型对应的处理函数
+//
+// @Description:
+// @receiver r
+// @param identifierType		MINPacket 第一个标识的类型
+// @param ingress
+// @param minPacket
+// @return bool				标识类型不被允许时返回 false ，此时包被丢弃并增加对应的丢包计数
+//
+func (r *IdentifierTypeRegistry) Dispatch(identifierType uint64, ingress *lf.LogicFace, minPacket *packet.MINPacket) bool {
+	r.lock.RLock()
+	handler, ok := r.lookup(identifierType)
+	if !ok {
+		if counter, registered := r.drops[identifierType]; registered {
+			atomic.AddUint64(counter, 1)
+		} else {
+			atomic.AddUint64(&r.unregisteredDrops, 1)
+		}
+	}
+	r.lock.RUnlock()
+	if !ok {
+		return false
+	}
+	handler(ingress, minPacket)
+	return true
+}
+
+// GetDropCounts
+// 获取每种注册了处理函数的标识类型因为不在白名单中而被丢弃的包数
+//
+// @Description:
+// @receiver r
+// @return map[uint64]uint64		返回的是一份拷贝，只包含丢包数不为 0 的标识类型
+//
+func (r *IdentifierTypeRegistry) GetDropCounts() map[uint64]uint64 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	result := make(map[uint64]uint64)
+	for identifierType, counter := range r.drops {
+		if count := atomic.LoadUint64(counter); count > 0 {
+			result[identifierType] = count
+		}
+	}
+	return result
+}
+
+// GetUnregisteredDropCount
+// 获取因为标识类型没有注册处理函数而被丢弃的包数
+//
+// @Description:
+// @receiver r
+// @return uint64
+//
+func (r *IdentifierTypeRegistry) GetUnregisteredDropCount() uint64 {
+	return atomic.LoadUint64(&r.unregisteredDrops)
+}
+
+// List
+// 按从小到大的顺序列出所有可以被处理的标识类型
+//
+// @Description:
+// @receiver r
+// @return []uint64
+//
+func (r *IdentifierTypeRegistry) List() []uint64 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	result := make([]uint64, 0, len(r.handlers))
+	for identifierType := range r.handlers {
+		if _, ok := r.lookup(identifierType); ok {
+			result = append(result, identifierType)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result
+}
+
+//
+// 查找某种标识类型的处理函数，调用者需要持有读锁
+//
+// @Description:
+// @receiver r
+// @param identifierType
+// @return IdentifierTypeHandler
+// @return bool		不在白名单中或者没有注册处理函数时返回 false
+//
+func (r *IdentifierTypeRegistry) lookup(identifierType uint64) (IdentifierTypeHandler, bool) {
+	if r.allowlist != nil && !r.allowlist[identifierType] {
+		return nil, false
+	}
+	handler, ok := r.handlers[identifierType]
+	return handler, ok
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+///// 错误处理
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	InvalidIdentifierTypeHandlerError = iota
+	IdentifierTypeAlreadyRegisteredError
+)
+
+type IdentifierTypeRegistryError struct {
+	msg string
+}
+
+func (i IdentifierTypeRegistryError) Error() string {
+	return fmt.Sprintf("IdentifierTypeRegistryError: %s", i.msg)
+}
+
+func createIdentifierTypeRegistryErrorByType(errorType int, identifierType uint64) (err IdentifierTypeRegistryError) {
+	switch errorType {
+	case InvalidIdentifierTypeHandlerError:
+		err.msg = fmt.Sprintf("invalid handler for identifier type: %d", identifierType)
+	case IdentifierTypeAlreadyRegisteredError:
+		err.msg = fmt.Sprintf("identifier type already registered: %d", identifierType)
+	default:
+		err.msg = "Unknown error"
+	}
+	return
+}
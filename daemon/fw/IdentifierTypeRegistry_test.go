@@ -0,0 +1,74 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 1:30 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package fw
+
+import (
+	"fmt"
+	"minlib/packet"
+	"mir-go/daemon/lf"
+	"testing"
+)
+
+func TestIdentifierTypeRegistry_Dispatch(t *testing.T) {
+	registry := CreateIdentifierTypeRegistry()
+	handled := make(map[uint64]int)
+	for _, identifierType := range []uint64{102, 103, 104} {
+		identifierType := identifierType
+		if err := registry.Register(identifierType, func(ingress *lf.LogicFace, minPacket *packet.MINPacket) {
+			handled[identifierType]++
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := registry.Register(102, func(ingress *lf.LogicFace, minPacket *packet.MINPacket) {}); err == nil {
+		t.Fatal("should reject duplicate identifier type")
+	} else {
+		fmt.Println(err)
+	}
+
+	// 没有设置白名单时，所有注册了处理函数的标识类型都被允许
+	if !registry.Dispatch(104, nil, nil) || registry.Dispatch(105, nil, nil) {
+		t.Fatal("unexpected dispatch result without allowlist")
+	}
+
+	// 设置白名单之后，不在白名单中的标识类型被丢弃
+	registry.SetAllowlist([]uint64{102, 103, 105})
+	for _, identifierType := range []uint64{102, 103, 104, 104, 105} {
+		registry.Dispatch(identifierType, nil, nil)
+	}
+	// 没有注册处理函数的标识类型都记在同一个计数器中，不会为每种标识类型创建计数
+	registry.Dispatch(200, nil, nil)
+	registry.Dispatch(201, nil, nil)
+	drops := registry.GetDropCounts()
+	unregisteredDrops := registry.GetUnregisteredDropCount()
+	fmt.Println("handled", handled, "drops", drops, "unregistered drops", unregisteredDrops, "allowed", registry.List())
+	if handled[102] != 1 || handled[103] != 1 || handled[104] != 1 {
+		t.Fatal("unexpected handled counts", handled)
+	}
+	if len(drops) != 1 || drops[104] != 2 {
+		t.Fatal("unexpected drop counts", drops)
+	}
+	if unregisteredDrops != 4 {
+		t.Fatal("unexpected unregistered drop count", unregisteredDrops)
+	}
+}
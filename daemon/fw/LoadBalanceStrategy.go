@@ -0,0 +1,235 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 2:05 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"hash/fnv"
+	"math"
+	common2 "minlib/common"
+	"minlib/component"
+	"minlib/packet"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/table"
+	"sync"
+)
+
+// LoadBalanceStrategyName 负载均衡转发策略的名字
+const LoadBalanceStrategyName = "/strategy/load-balance"
+
+// LoadBalanceStrategy
+// 基于一致性哈希的加权负载均衡转发策略
+//
+// @Description:
+//  1. 使用加权的最高随机权重哈希（Weighted Rendezvous Hashing）为每个 Interest（按名字）或 GPPkt 流（按源标识和目的标识）
+//     选择出口，同一个名字总是选中同一个下一跳，不同名字则按权重比例分摊到各个下一跳；
+//  2. 每个下一跳的权重优先使用 SetWeight 显式配置的值，未配置时根据 Cost 计算：weight = 1 / (Cost + 1)；
+//  3. 下一跳加入或者离开 FIBEntry 时，只有原本映射到该下一跳的流量会被重新分配，其它流量的出口保持不变。
+//
+type LoadBalanceStrategy struct {
+	StrategyBase
+	weightLock sync.RWMutex
+	weights    map[uint64]float64 // LogicFaceId => 显式配置的权重
+}
+
+// NewLoadBalanceStrategy
+// 新建一个负载均衡转发策略
+//
+// @Description:
+// @return *LoadBalanceStrategy
+//
+func NewLoadBalanceStrategy() *LoadBalanceStrategy {
+	return &LoadBalanceStrategy{
+		weights: make(map[uint64]float64),
+	}
+}
+
+// SetWeight
+// 为某个 LogicFace 显式配置权重
+//
+// @Description:
+// @receiver lbs
+// @param logicFaceId
+// @param weight		权重，必须大于 0，否则等同于 RemoveWeight
+//
+func (lbs *LoadBalanceStrategy) SetWeight(logicFaceId uint64, weight float64) {
+	lbs.weightLock.Lock()
+	defer lbs.weightLock.Unlock()
+	if weight <= 0 {
+		delete(lbs.weights, logicFaceId)
+		return
+	}
+	lbs.weights[logicFaceId] = weight
+}
+
+// RemoveWeight
+// 移除某个 LogicFace 显式配置的权重，之后该 LogicFace 的权重将根据 Cost 计算
+//
+// @Description:
+// @receiver lbs
+// @param logicFaceId
+//
+func (lbs *LoadBalanceStrategy) RemoveWeight(logicFaceId uint64) {
+	lbs.weightLock.Lock()
+	defer lbs.weightLock.Unlock()
+	delete(lbs.weights, logicFaceId)
+}
+
+//
+// 获取某个下一跳的权重
+//
+// @Description:
+// @receiver lbs
+// @param nextHop
+// @return float64
+//
+func (lbs *LoadBalanceStrategy) getWeight(nextHop *table.NextHop) float64 {
+	lbs.weightLock.RLock()
+	defer lbs.weightLock.RUnlock()
+	if weight, ok := lbs.weights[nextHop.LogicFace.LogicFaceId]; ok {
+		return weight
+	}
+	return 1 / (float64(nextHop.Cost) + 1)
+}
+
+//
+// 使用加权的最高随机权重哈希为 key 选出一个下一跳
+//
+// @Description:
+//  对每个可用下一跳计算 score = -ln(h) / weight，其中 h 为 (key, LogicFaceId) 的哈希值映射到 (0, 1] 区间的结果，
+//  选择 score 最小的下一跳，这样每个下一跳被选中的概率与其权重成正比。
+// @receiver lbs
+// @param ingress
+// @param fibEntry
+// @param key
+// @return *table.NextHop
+//
+func (lbs *LoadBalanceStrategy) selectNextHop(ingress *lf.LogicFace, fibEntry *table.FIBEntry, key []byte) *table.NextHop {
+	if fibEntry == nil {
+		return nil
+	}
+	var selected *table.NextHop
+	bestScore := math.Inf(1)
+	for _, nextHop := range fibEntry.GetNextHops() {
+		if nextHop.LogicFace.LogicFaceId == ingress.LogicFaceId {
+			continue
+		}
+		h := rendezvousHash(key, nextHop.LogicFace.LogicFaceId)
+		score := -math.Log(h) / lbs.getWeight(nextHop)
+		// 分数相同时选择 LogicFaceId 较小的下一跳，保证结果与 FIBEntry 中下一跳的顺序无关
+		if selected == nil || score < bestScore ||
+			(score == bestScore && nextHop.LogicFace.LogicFaceId < selected.LogicFace.LogicFaceId) {
+			selected = nextHop
+			bestScore = score
+		}
+	}
+	return selected
+}
+
+//
+// 计算 (key, logicFaceId) 的哈希值，并映射到 (0, 1] 区间
+//
+// @Description:
+//  FNV 哈希的高位对输入末尾字节的变化不敏感，所以这边使用 splitmix64 的混淆函数将 key 的哈希值和 logicFaceId 充分混合
+// @param key
+// @param logicFaceId
+// @return float64
+//
+func rendezvousHash(key []byte, logicFaceId uint64) float64 {
+	hasher := fnv.New64a()
+	_, _ = hasher.Write(key)
+	v := mix64(hasher.Sum64() ^ mix64(logicFaceId))
+	// 取高 53 位，保证转换成 float64 时没有精度损失
+	return (float64(v>>11) + 1) / float64(uint64(1)<<53)
+}
+
+//
+// splitmix64 的混淆函数
+//
+// @Description:
+// @param x
+// @return uint64
+//
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func (lbs *LoadBalanceStrategy) AfterReceiveInterest(ingress *lf.LogicFace, interest *packet.Interest, pitEntry *table.PITEntry) {
+	// 首先判断是否是仍然在重传抑制窗口内的下游重传，是则不转发被聚合
+	retxResult := lbs.decideRetxSuppression(pitEntry)
+	if retxResult == RetxSuppressionSuppress {
+		return
+	}
+
+	fibEntry := lbs.lookupFibForInterest(interest)
+	nextHop := lbs.selectNextHop(ingress, fibEntry, []byte(interest.GetName().ToUri()))
+	if nextHop == nil {
+		if retxResult != RetxSuppressionNew {
+			// 下游的重传找不到下一跳时，继续等待 pending 的上游
+			return
+		}
+		// 如果没有找到下一跳路由信息，直接返回一个原因为 no-route 的 Nack
+		var nh component.NackHeader
+		nh.SetNackReason(component.NackReasonNoRoute)
+		lbs.sendNack(ingress, &nh, pitEntry)
+
+		// 同时触发 PITEntry 移除
+		lbs.rejectPendingInterest(pitEntry)
+		return
+	}
+	lbs.sendInterest(nextHop.LogicFace, interest, pitEntry)
+}
+
+func (lbs *LoadBalanceStrategy) AfterReceiveNack(ingress *lf.LogicFace, nack *packet.Nack, pitEntry *table.PITEntry) {
+	lbs.processNack(ingress, pitEntry)
+}
+
+func (lbs *LoadBalanceStrategy) AfterReceiveGPPkt(ingress *lf.LogicFace, gPPkt *packet.GPPkt) {
+	fibEntry := lbs.lookupFibForGPPkt(gPPkt)
+	// 同一对（源标识，目的标识）构成一个流，同一个流总是从同一个下一跳转发出去
+	key := []byte(gPPkt.SrcIdentifier().ToUri() + "|" + gPPkt.DstIdentifier().ToUri())
+	nextHop := lbs.selectNextHop(ingress, fibEntry, key)
+	if nextHop == nil {
+		// 没有路由无法转发
+		common2.LogDebug("No Route")
+		return
+	}
+	lbs.sendGPPkt(nextHop.LogicFace, gPPkt)
+}
+
+// OnFaceDown
+// LogicFace 被关闭时，移除其显式配置的权重，并将失去上游的兴趣包重新选择下一跳转发
+//
+// @Description:
+//  LogicFaceId 不会被重复分配，所以被关闭的 LogicFace 的权重不会再被用到
+// @receiver lbs
+// @param face
+// @param pitEntries
+//
+func (lbs *LoadBalanceStrategy) OnFaceDown(face *lf.LogicFace, pitEntries []*table.PITEntry) {
+	lbs.RemoveWeight(face.LogicFaceId)
+	lbs.reforwardPendingInterests(pitEntries, lbs.AfterReceiveInterest)
+}
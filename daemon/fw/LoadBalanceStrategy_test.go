@@ -0,0 +1,71 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 2:40 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package fw
+
+import (
+	"fmt"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/table"
+	"testing"
+)
+
+func TestLoadBalanceStrategy_SelectNextHop(t *testing.T) {
+	lbs := NewLoadBalanceStrategy()
+	ingress := &lf.LogicFace{LogicFaceId: 100}
+	fibEntry := table.CreateFIBEntry()
+	for i := uint64(1); i <= 3; i++ {
+		fibEntry.AddOrUpdateNextHop(&lf.LogicFace{LogicFaceId: i}, 0)
+	}
+	// LogicFace 3 的权重是其它 LogicFace 的两倍
+	lbs.SetWeight(3, 2)
+
+	const keyNum = 10000
+	selected := make(map[int]uint64)
+	counts := make(map[uint64]int)
+	for i := 0; i < keyNum; i++ {
+		nextHop := lbs.selectNextHop(ingress, fibEntry, []byte(fmt.Sprintf("/min/pkusz/%d", i)))
+		selected[i] = nextHop.LogicFace.LogicFaceId
+		counts[nextHop.LogicFace.LogicFaceId]++
+	}
+	fmt.Println("distribution", counts)
+	if counts[3] < counts[1] || counts[3] < counts[2] {
+		t.Fatal("LogicFace with higher weight should receive more traffic")
+	}
+
+	// 同一个名字总是选中同一个下一跳
+	for i := 0; i < 100; i++ {
+		nextHop := lbs.selectNextHop(ingress, fibEntry, []byte(fmt.Sprintf("/min/pkusz/%d", i)))
+		if nextHop.LogicFace.LogicFaceId != selected[i] {
+			t.Fatal("Same name should be mapped to the same next hop")
+		}
+	}
+
+	// 移除一个下一跳之后，原本没有映射到该下一跳的名字不应该改变出口
+	fibEntry.RemoveNextHop(&lf.LogicFace{LogicFaceId: 2})
+	for i := 0; i < keyNum; i++ {
+		nextHop := lbs.selectNextHop(ingress, fibEntry, []byte(fmt.Sprintf("/min/pkusz/%d", i)))
+		if selected[i] != 2 && nextHop.LogicFace.LogicFaceId != selected[i] {
+			t.Fatal("Removing a next hop should not remap other names")
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 10:12 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"github.com/sirupsen/logrus"
+	common2 "minlib/common"
+	"minlib/component"
+	"minlib/packet"
+	"mir-go/daemon/common"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/table"
+)
+
+// MulticastStrategyName 多播转发策略的名字
+const MulticastStrategyName = "/strategy/multicast"
+
+// MulticastStrategy
+// 多播转发策略实现
+//
+// @Description:
+//  将 Interest 和 GPPkt 转发到 FIB 条目中除入口 LogicFace 之外的所有可用下一跳，适用于一个前缀由多个副本提供服务，或者用于
+//  服务发现的场景。
+//
+type MulticastStrategy struct {
+	StrategyBase
+}
+
+//
+// 找到所有可用于转发的下一跳（排除网络包到来的 LogicFace）
+//
+// @Description:
+// @receiver ms
+// @param ingress
+// @param fibEntry
+// @return []*table.NextHop
+//
+func (ms *MulticastStrategy) findEligibleNextHops(ingress *lf.LogicFace, fibEntry *table.FIBEntry) []*table.NextHop {
+	nextHops := make([]*table.NextHop, 0)
+	if fibEntry == nil {
+		return nextHops
+	}
+	for _, nextHop := range fibEntry.GetNextHops() {
+		if nextHop.LogicFace.LogicFaceId != ingress.LogicFaceId {
+			nextHops = append(nextHops, nextHop)
+		}
+	}
+	return nextHops
+}
+
+func (ms *MulticastStrategy) AfterReceiveInterest(ingress *lf.LogicFace, interest *packet.Interest, pitEntry *table.PITEntry) {
+	fibEntry := ms.lookupFibForInterest(interest)
+	now := common.GetCurrentTime()
+
+	sentNum := 0
+	for _, nextHop := range ms.findEligibleNextHops(ingress, fibEntry) {
+		// 如果往这个下一跳转发的 Interest 还在 pending，则不重复转发
+		if outRecord, err := pitEntry.GetOutRecord(nextHop.LogicFace); err == nil &&
+			outRecord.ExpireTime > now && outRecord.NackHeader == nil {
+			continue
+		}
+		ms.sendInterest(nextHop.LogicFace, interest, pitEntry)
+		sentNum++
+	}
+
+	if sentNum > 0 {
+		return
+	}
+
+	if HasPendingOutRecords(pitEntry) {
+		// 所有的下一跳都在 pending，Interest 被聚合
+		common2.LogDebugWithFields(logrus.Fields{
+			"ingress":  ingress.LogicFaceId,
+			"interest": interest.ToUri(),
+			"pitEntry": pitEntry.Identifier.ToUri(),
+		}, "PITEntry already has pending interest on every next hop, drop")
+		return
+	}
+
+	// 没有任何可用的下一跳，返回一个原因为 no-route 的 Nack，并触发 PITEntry 移除
+	var nh component.NackHeader
+	nh.SetNackReason(component.NackReasonNoRoute)
+	ms.sendNack(ingress, &nh, pitEntry)
+	ms.rejectPendingInterest(pitEntry)
+}
+
+func (ms *MulticastStrategy) AfterReceiveNack(ingress *lf.LogicFace, nack *packet.Nack, pitEntry *table.PITEntry) {
+	ms.processNack(ingress, pitEntry)
+}
+
+func (ms *MulticastStrategy) AfterReceiveGPPkt(ingress *lf.LogicFace, gPPkt *packet.GPPkt) {
+	fibEntry := ms.lookupFibForGPPkt(gPPkt)
+	nextHops := ms.findEligibleNextHops(ingress, fibEntry)
+	if len(nextHops) == 0 {
+		// 没有路由无法转发
+		common2.LogDebug("No Route")
+		return
+	}
+	for _, nextHop := range nextHops {
+		ms.sendGPPkt(nextHop.LogicFace, gPPkt)
+	}
+}
+
+// OnFaceDown
+// LogicFace 被关闭时，将已经没有任何 pending 上游的兴趣包重新转发给其它所有可用的下一跳
+//
+// @Description:
+// @receiver ms
+// @param face
+// @param pitEntries
+//
+func (ms *MulticastStrategy) OnFaceDown(face *lf.LogicFace, pitEntries []*table.PITEntry) {
+	ms.reforwardPendingInterests(pitEntries, ms.AfterReceiveInterest)
+}
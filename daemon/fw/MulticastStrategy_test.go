@@ -0,0 +1,182 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 9:20 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package fw
+
+import (
+	"fmt"
+	"minlib/component"
+	"minlib/packet"
+	"mir-go/daemon/common"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/plugin"
+	"mir-go/daemon/table"
+	"sort"
+	"testing"
+)
+
+// 记录策略发出的兴趣包、 data 和 Nack ，并拦截后续的管道处理，避免往没有底层传输的 LogicFace 发包
+type recordingPlugin struct {
+	plugin.BasePlugin
+	interestFaces []uint64
+	dataFaces     []uint64
+	nackFaces     []uint64
+	nackReasons   []uint64
+}
+
+func (r *recordingPlugin) OnOutgoingInterest(egress *lf.LogicFace, pitEntry *table.PITEntry, interest *packet.Interest) int {
+	r.interestFaces = append(r.interestFaces, egress.LogicFaceId)
+	return 1
+}
+
+func (r *recordingPlugin) OnOutgoingData(egress *lf.LogicFace, data *packet.Data) int {
+	r.dataFaces = append(r.dataFaces, egress.LogicFaceId)
+	return 1
+}
+
+func (r *recordingPlugin) OnOutgoingNack(egress *lf.LogicFace, pitEntry *table.PITEntry, header *component.NackHeader) int {
+	r.nackFaces = append(r.nackFaces, egress.LogicFaceId)
+	r.nackReasons = append(r.nackReasons, header.GetNackReason())
+	return 1
+}
+
+func newRecordingTestForwarder(t *testing.T) (*Forwarder, *recordingPlugin) {
+	recorder := new(recordingPlugin)
+	pluginManager := new(plugin.GlobalPluginManager)
+	pluginManager.RegisterPlugin(recorder)
+	config := new(common.MIRConfig)
+	config.Init()
+	forwarder := new(Forwarder)
+	if err := forwarder.Init(config, pluginManager, make(chan *lf.IncomingPacketData, 20)); err != nil {
+		t.Fatal(err)
+	}
+	return forwarder, recorder
+}
+
+func newMulticastTestForwarder(t *testing.T) (*Forwarder, *recordingPlugin, *MulticastStrategy) {
+	forwarder, recorder := newRecordingTestForwarder(t)
+	ms := new(MulticastStrategy)
+	ms.SetForwarder(forwarder)
+	return forwarder, recorder, ms
+}
+
+func TestMulticastStrategy_AfterReceiveInterest(t *testing.T) {
+	forwarder, recorder, ms := newMulticastTestForwarder(t)
+	name, _ := component.CreateIdentifierByString("/min/multicast")
+	downstream := &lf.LogicFace{LogicFaceId: 1}
+	for _, face := range []*lf.LogicFace{downstream, {LogicFaceId: 2}, {LogicFaceId: 3}, {LogicFaceId: 4}} {
+		forwarder.FIB.AddOrUpdate(name, face, 0)
+	}
+	interest := new(packet.Interest)
+	interest.SetName(name)
+	interest.InterestLifeTime.SetInterestLifeTime(4000)
+	pitEntry := table.CreatePITEntry()
+	pitEntry.Identifier = name
+	pitEntry.InsertOrUpdateInRecord(downstream, interest)
+
+	// 转发到除下游之外的所有下一跳
+	ms.AfterReceiveInterest(downstream, interest, pitEntry)
+	sort.Slice(recorder.interestFaces, func(i, j int) bool { return recorder.interestFaces[i] < recorder.interestFaces[j] })
+	fmt.Println("fan-out =>", recorder.interestFaces)
+	if fmt.Sprint(recorder.interestFaces) != "[2 3 4]" {
+		t.Fatal("expect interest sent to faces 2, 3, 4, got", recorder.interestFaces)
+	}
+
+	// 所有下一跳都在 pending 时兴趣包被聚合，不再转发
+	recorder.interestFaces = nil
+	for _, id := range []uint64{2, 3, 4} {
+		outRecord := pitEntry.InsertOrUpdateOutRecord(&lf.LogicFace{LogicFaceId: id}, interest)
+		outRecord.ExpireTime = ^uint64(0)
+	}
+	ms.AfterReceiveInterest(downstream, interest, pitEntry)
+	if len(recorder.interestFaces) != 0 || len(recorder.nackFaces) != 0 {
+		t.Fatal("expect interest aggregated, got", recorder.interestFaces, recorder.nackFaces)
+	}
+
+	// 被 Nack 的上游不再算作 pending，会重新转发
+	nackedRecord, _ := pitEntry.GetOutRecord(&lf.LogicFace{LogicFaceId: 3})
+	nackedRecord.NackHeader = new(component.NackHeader)
+	ms.AfterReceiveInterest(downstream, interest, pitEntry)
+	if fmt.Sprint(recorder.interestFaces) != "[3]" {
+		t.Fatal("expect interest re-sent to face 3, got", recorder.interestFaces)
+	}
+}
+
+func TestMulticastStrategy_NoRoute(t *testing.T) {
+	forwarder, recorder, ms := newMulticastTestForwarder(t)
+	name, _ := component.CreateIdentifierByString("/min/multicast")
+	downstream := &lf.LogicFace{LogicFaceId: 1}
+	// 唯一的下一跳就是下游本身
+	forwarder.FIB.AddOrUpdate(name, downstream, 0)
+	interest := new(packet.Interest)
+	interest.SetName(name)
+	pitEntry := table.CreatePITEntry()
+	pitEntry.Identifier = name
+	pitEntry.InsertOrUpdateInRecord(downstream, interest)
+
+	ms.AfterReceiveInterest(downstream, interest, pitEntry)
+	if len(recorder.interestFaces) != 0 {
+		t.Fatal("expect no interest sent, got", recorder.interestFaces)
+	}
+	if fmt.Sprint(recorder.nackFaces) != "[1]" || recorder.nackReasons[0] != component.NackReasonNoRoute {
+		t.Fatal("expect no-route nack to downstream, got", recorder.nackFaces, recorder.nackReasons)
+	}
+}
+
+func TestMulticastStrategy_AfterReceiveNack(t *testing.T) {
+	_, recorder, ms := newMulticastTestForwarder(t)
+	name, _ := component.CreateIdentifierByString("/min/multicast")
+	downstream := &lf.LogicFace{LogicFaceId: 1}
+	upstream2, upstream3 := &lf.LogicFace{LogicFaceId: 2}, &lf.LogicFace{LogicFaceId: 3}
+	interest := new(packet.Interest)
+	interest.SetName(name)
+	pitEntry := table.CreatePITEntry()
+	pitEntry.Identifier = name
+	pitEntry.InsertOrUpdateInRecord(downstream, interest)
+	pitEntry.InsertOrUpdateOutRecord(upstream2, interest).ExpireTime = ^uint64(0)
+	pitEntry.InsertOrUpdateOutRecord(upstream3, interest).ExpireTime = ^uint64(0)
+
+	// 只有一个上游返回 Nack => 继续等待另一个上游
+	nack := new(packet.Nack)
+	nack.Interest = interest
+	nack.SetNackReason(component.NackReasonNoRoute)
+	outRecord2, _ := pitEntry.GetOutRecord(upstream2)
+	outRecord2.NackHeader = &nack.Interest.NackHeader
+	ms.AfterReceiveNack(upstream2, nack, pitEntry)
+	if len(recorder.nackFaces) != 0 {
+		t.Fatal("expect nack not forwarded before all upstreams nacked, got", recorder.nackFaces)
+	}
+
+	// 所有上游都返回 Nack => 将最不严重的原因返回给下游
+	var nh component.NackHeader
+	nh.SetNackReason(component.NackReasonDuplicate)
+	outRecord3, _ := pitEntry.GetOutRecord(upstream3)
+	outRecord3.NackHeader = &nh
+	ms.AfterReceiveNack(upstream3, nack, pitEntry)
+	expectedReason := uint64(component.NackReasonNoRoute)
+	if uint64(component.NackReasonDuplicate) > expectedReason {
+		expectedReason = uint64(component.NackReasonDuplicate)
+	}
+	fmt.Println("aggregated nack =>", recorder.nackFaces, recorder.nackReasons)
+	if fmt.Sprint(recorder.nackFaces) != "[1]" || recorder.nackReasons[0] != expectedReason {
+		t.Fatal("expect aggregated nack to downstream, got", recorder.nackFaces, recorder.nackReasons)
+	}
+}
@@ -25,21 +25,34 @@ import (
 	"github.com/panjf2000/ants"
 	common2 "minlib/common"
 	"minlib/security"
-	"minlib/utils"
+	"mir-go/daemon/audit"
 	"mir-go/daemon/lf"
+	"strconv"
+	"sync"
+	"time"
 )
 
+// verifyFailureAuditInterval 验证失败事件写入审计日志的聚合周期
+const verifyFailureAuditInterval = time.Second
+
 // PacketValidator
 // 表示一个包验证器，本验证器会并发的对收到的网络包进行签名验证，并且在
 //
 // @Description:
 //
 type PacketValidator struct {
-	_pool        *ants.Pool         // 协程池，用于并发验签
-	packetQueue  *utils.BlockQueue  // 一个阻塞队列，用于和 Forwarder 进行通信
-	keyChain     *security.KeyChain // 一个KeyChain，用于包签名验证
-	cap          int                // 协程池容量
-	needValidate bool               // 是否需要进行验证（如果不开启签名验证，则直接传递给缓存队列即可，无需开启线程池）
+	_pool                 *ants.Pool                    // 协程池，用于并发验签
+	packetQueue           chan<- *lf.IncomingPacketData // 包队列，用于和 Forwarder 进行通信，满了之后写入会阻塞，直到包验证器被停止
+	stopChan              chan struct{}                 // 停止信号，关闭之后不再往包队列写入，阻塞的写入也会返回
+	stopOnce              sync.Once                     // 保证停止信号只会被关闭一次
+	keyChain              *security.KeyChain            // 一个KeyChain，用于包签名验证
+	cap                   int                           // 协程池容量
+	needValidate          bool                          // 是否需要进行验证（如果不开启签名验证，则直接传递给缓存队列即可，无需开启线程池）
+	maxRouterSignatureNum int                           // 允许的最多的中间路由器签名个数，小于 0 表示不限制
+	verifyFailures        *audit.AuditAggregator        // 验证失败事件的聚合器，为 nil 时不记录审计日志
+	trustSchema           *TrustSchema                  // 信任模式，为 nil 时只验证签名是否有效
+	certificateFetcher    *CertificateFetcher           // 证书获取器，为 nil 时签名者的证书不在本地的包直接验证失败
+	certificateFetchFace  *lf.LogicFace                 // 证书获取器发送兴趣包使用的内部 LogicFace ，从它收到的包不需要验证
 }
 
 // Init
@@ -49,12 +62,14 @@ type PacketValidator struct {
 // @receiver p
 // @param cap					协程池的大小
 // @param needValidate			是否需要开启签名验证
-// @param packetQueue			与 Forwarder 共同持有的一个阻塞队列
+// @param packetQueue			与 Forwarder 共同持有的包队列
 //
-func (p *PacketValidator) Init(cap int, needValidate bool, packetQueue *utils.BlockQueue) {
+func (p *PacketValidator) Init(cap int, needValidate bool, packetQueue chan<- *lf.IncomingPacketData) {
 	p.cap = cap
 	p.packetQueue = packetQueue
+	p.stopChan = make(chan struct{})
 	p.needValidate = needValidate
+	p.maxRouterSignatureNum = -1
 	// 当且仅当需要进行签名验证时，才开启协程池
 	if needValidate {
 		if keyChain, err := security.CreateKeyChain(); err != nil {
@@ -70,33 +85,114 @@ func (p *PacketValidator) Init(cap int, needValidate bool, packetQueue *utils.Bl
 	}
 }
 
+// SetMaxRouterSignatureNum
+// 设置允许的最多的中间路由器签名个数
+//
+// @Description:
+//  开启中间路由器签名时调用，签名区中除了生产者的签名之外，签名个数超过 maxRouterSignatureNum 的包会被丢弃
+// @receiver p
+// @param maxRouterSignatureNum
+//
+func (p *PacketValidator) SetMaxRouterSignatureNum(maxRouterSignatureNum int) {
+	p.maxRouterSignatureNum = maxRouterSignatureNum
+}
+
+// SetAuditLog
+// 设置审计日志，签名验证失败的包会被记录到审计日志中
+//
+// @Description:
+//  验证失败可以由网络上的任意一方触发，所以按照 (入口 LogicFace, 失败原因) 聚合，每个 verifyFailureAuditInterval 最多写一条记录，
+//  并且由后台协程异步写入，不会阻塞验签协程
+// @receiver p
+// @param auditLog
+//
+func (p *PacketValidator) SetAuditLog(auditLog *audit.AuditLog) {
+	p.verifyFailures = audit.CreateAuditAggregator(auditLog, audit.AuditEventVerifyFailed, verifyFailureAuditInterval)
+}
+
+// SetTrustSchema
+// 设置信任模式，签名验证通过之后还需要检查生产者是否有权为网络包的标识签名
+//
+// @Description:
+// @receiver p
+// @param trustSchema
+//
+func (p *PacketValidator) SetTrustSchema(trustSchema *TrustSchema) {
+	p.trustSchema = trustSchema
+}
+
+// EnableCertificateFetcher
+// 开启未知签名者的证书获取
+//
+// @Description:
+//  签名者的证书不在本地的包会被暂存，等证书获取器通过转发器获取到签名者的证书之后再重新验证，获取失败时丢弃。
+//  需要调用返回的证书获取器的 Start 方法，传入 faceServer 对应的另一端，让其能够通过转发器发送兴趣包
+// @receiver p
+// @param faceServer			证书获取器使用的内部 LogicFace 中转发器使用的一端
+// @param trustAnchors		信任锚的网络身份，获取到的证书必须可以追溯到其中之一
+// @param cacheLifetime		获取到的证书的缓存时间，单位 ms
+// @param fetchTimeout		获取证书的超时时间，单位 ms
+// @return *CertificateFetcher
+//
+func (p *PacketValidator) EnableCertificateFetcher(faceServer *lf.LogicFace, trustAnchors []string,
+	cacheLifetime uint64, fetchTimeout uint64) *CertificateFetcher {
+	p.certificateFetchFace = faceServer
+	p.certificateFetcher = CreateCertificateFetcher(CreateKeyChainCertificateStore(p.keyChain), cacheLifetime, fetchTimeout)
+	p.certificateFetcher.SetTrustAnchors(trustAnchors)
+	p.certificateFetcher.resume = p.ReceiveMINPacket
+	p.certificateFetcher.drop = p.onVerifyFailed
+	return p.certificateFetcher
+}
+
 // ReceiveMINPacket
 // 收到一个MINPacket
 //
 // @Description:
 //	1. 如果开启了签名验证，则将收到的网络包交给协程池进行并发的验证，验证通过则放入 p.packetQueue
-//	2. 如果没有开启签名验证，则直接将收到的网络包放入 p.packetQueue
+//	   - 签名区中的所有签名，包括之前每一跳追加的中间路由器签名，都需要验证通过；
+//	   - 设置了最多的中间路由器签名个数时，中间路由器签名个数超过上限的包直接丢弃；
+//	   - 设置了信任模式时，生产者不被信任模式允许为该标识签名的包也会被丢弃；
+//	   - 开启了证书获取时，生产者的证书不在本地的包会被暂存，等获取到证书之后再验证。
+//	2. 如果没有开启签名验证，或者包来自证书获取器自己使用的内部 LogicFace （证书获取器发出的兴趣包没有签名），
+//	   则直接将收到的网络包放入 p.packetQueue ；其它内部 LogicFace （例如管理模块）收到的包仍然需要验证
 // @receiver p
 // @param data
 //
 func (p *PacketValidator) ReceiveMINPacket(data *lf.IncomingPacketData) {
-	if !p.needValidate {
+	if !p.needValidate || (p.certificateFetchFace != nil && data.LogicFace == p.certificateFetchFace) {
 		// 如果不需要进行包验证，则直接放到队列中
-		p.packetQueue.Write(data)
+		p.enqueue(data)
 		return
 	}
 
 	// 如果开启了包验证，则放到协程池里进行并发验证
 	if err := p._pool.Submit(func() {
+		if p.maxRouterSignatureNum >= 0 && GetMINPacketSignatureNum(data.MinPacket) > p.maxRouterSignatureNum+1 {
+			// 中间路由器签名超过上限，计入入口逻辑接口的丢包
+			common2.LogDebugWithFields(data.ToFields(), "Too many router signatures")
+			p.onVerifyFailed(data, "too many router signatures")
+			return
+		}
+		if p.parkIfSignerUnknown(data) {
+			// 生产者的证书不在本地，等待证书获取器获取证书
+			return
+		}
 		// TODO: 这边需要检查一下 KeyChain 的签名验证方法是不是多线程安全的
 		if err := p.keyChain.Verify(data.MinPacket); err == nil {
+			if err := p.checkTrustSchema(data); err != nil {
+				// 签名有效，但是签名者不被信任模式允许
+				common2.LogDebugWithFields(data.ToFields(), "Trust Schema Check Failed")
+				p.onVerifyFailed(data, err.Error())
+				return
+			}
 			// 验证成功
 			common2.LogDebugWithFields(data.ToFields(), "Verify Packet Success")
 			// 验证成功之后将包放入队列中
-			p.packetQueue.Write(data)
+			p.enqueue(data)
 		} else {
-			// 验证失败
+			// 验证失败，计入入口逻辑接口的丢包
 			common2.LogDebugWithFields(data.ToFields(), "Verify Packet Failed")
+			p.onVerifyFailed(data, err.Error())
 		}
 	}); err != nil {
 		// 任务提交失败，输出错误
@@ -104,6 +200,113 @@ func (p *PacketValidator) ReceiveMINPacket(data *lf.IncomingPacketData) {
 	}
 }
 
+//
+// 将网络包放入包队列
+//
+// @Description:
+//  包队列满了时阻塞等待转发器读取，包验证器被停止之后直接丢弃，避免转发器退出之后收包协程和验签协程被永久阻塞
+// @receiver p
+// @param data
+//
+func (p *PacketValidator) enqueue(data *lf.IncomingPacketData) {
+	select {
+	case p.packetQueue <- data:
+	case <-p.stopChan:
+		common2.LogDebugWithFields(data.ToFields(), "Packet validator stopped, drop packet")
+	}
+}
+
+//
+// 如果开启了证书获取并且包的生产者的证书不在本地，则将包暂存到证书获取器中
+//
+// @Description:
+// @receiver p
+// @param data
+// @return bool		是否被暂存
+//
+func (p *PacketValidator) parkIfSignerUnknown(data *lf.IncomingPacketData) bool {
+	if p.certificateFetcher == nil {
+		return false
+	}
+	signer, err := GetMINPacketSignerIdentifier(data.MinPacket)
+	if err != nil || p.certificateFetcher.IsTrusted(signer.ToUri()) {
+		return false
+	}
+	identifier, err := data.MinPacket.GetIdentifier(0)
+	if err != nil {
+		return false
+	}
+	p.certificateFetcher.Park(data, identifier.ToUri(), signer.ToUri())
+	return true
+}
+
+//
+// 使用信任模式检查包的生产者是否有权为包的标识签名
+//
+// @Description:
+//  没有设置信任模式时总是通过
+// @receiver p
+// @param data
+// @return error		不通过的原因
+//
+func (p *PacketValidator) checkTrustSchema(data *lf.IncomingPacketData) error {
+	if p.trustSchema == nil {
+		return nil
+	}
+	identifier, err := data.MinPacket.GetIdentifier(0)
+	if err != nil {
+		return err
+	}
+	signer, err := GetMINPacketSignerIdentifier(data.MinPacket)
+	if err != nil {
+		return err
+	}
+	return p.trustSchema.Check(identifier.ToUri(), signer.ToUri())
+}
+
+//
+// 处理一个验证失败的包
+//
+// @Description:
+//  计入入口逻辑接口的丢包，并交给聚合器记录到审计日志中
+// @receiver p
+// @param data
+// @param reason		验证失败的原因
+//
+func (p *PacketValidator) onVerifyFailed(data *lf.IncomingPacketData, reason string) {
+	if packetType, ok := lf.GetMINPacketCounterType(data.MinPacket); ok {
+		data.LogicFace.GetCounters().IncreaseDrop(packetType)
+	}
+	if p.verifyFailures == nil {
+		return
+	}
+	faceId := strconv.FormatUint(data.LogicFace.LogicFaceId, 10)
+	fields := map[string]string{
+		"faceId":    faceId,
+		"remoteUri": data.LogicFace.GetRemoteUri(),
+		"reason":    reason,
+	}
+	if identifier, err := data.MinPacket.GetIdentifier(0); err == nil {
+		fields["identifier"] = identifier.ToUri()
+	}
+	p.verifyFailures.Add(faceId+"|"+reason, fields)
+}
+
+// Stop
+// 停止往包队列写入网络包
+//
+// @Description:
+//  转发器停止读取包队列之后调用，之后验证通过的包直接丢弃，已经阻塞在包队列上的写入也会返回
+// @receiver p
+//
+func (p *PacketValidator) Stop() {
+	p.stopOnce.Do(func() {
+		if p.stopChan != nil {
+			close(p.stopChan)
+		}
+	})
+}
+
 // Close
 // 关闭包验证器
 //
@@ -111,8 +314,16 @@ func (p *PacketValidator) ReceiveMINPacket(data *lf.IncomingPacketData) {
 // @receiver p
 //
 func (p *PacketValidator) Close() {
+	if p.certificateFetcher != nil {
+		// 丢弃所有等待证书的包
+		p.certificateFetcher.Close()
+	}
 	if p._pool != nil {
 		// 关闭协程池
 		p._pool.Release()
 	}
+	if p.verifyFailures != nil {
+		// 写入最后一个聚合周期内的验证失败事件
+		p.verifyFailures.Close()
+	}
 }
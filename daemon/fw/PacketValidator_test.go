@@ -0,0 +1,66 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 3:10 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package fw
+
+import (
+	"minlib/packet"
+	"mir-go/daemon/lf"
+	"testing"
+	"time"
+)
+
+func TestPacketValidator_Stop(t *testing.T) {
+	// 包队列已经满了，并且转发器已经不再读取包队列
+	packetQueue := make(chan *lf.IncomingPacketData, 1)
+	var packetValidator PacketValidator
+	packetValidator.Init(1, false, packetQueue)
+	newData := func() *lf.IncomingPacketData {
+		return &lf.IncomingPacketData{LogicFace: &lf.LogicFace{LogicFaceId: 1}, MinPacket: new(packet.MINPacket)}
+	}
+	packetValidator.ReceiveMINPacket(newData())
+
+	// 阻塞在包队列上的收包协程在包验证器停止之后返回
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		packetValidator.ReceiveMINPacket(newData())
+	}()
+	select {
+	case <-done:
+		t.Fatal("expect enqueue blocked on a full packet queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+	packetValidator.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expect blocked enqueue released after stop")
+	}
+
+	// 停止之后收到的包直接丢弃，重复停止不会 panic
+	packetValidator.ReceiveMINPacket(newData())
+	packetValidator.Stop()
+	if len(packetQueue) != 1 {
+		t.Fatal("expect packets dropped after stop, got", len(packetQueue))
+	}
+}
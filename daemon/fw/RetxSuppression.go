@@ -0,0 +1,186 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 0:20 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"mir-go/daemon/table"
+)
+
+const (
+	DefaultRetxSuppressionInitialInterval = 10  // 重传抑制窗口的默认初始大小，单位 ms
+	DefaultRetxSuppressionMultiplier      = 2   // 每次转发重传之后重传抑制窗口默认扩大的倍数
+	DefaultRetxSuppressionMaxInterval     = 250 // 重传抑制窗口的默认最大值，单位 ms
+)
+
+// RetxSuppressionResult 重传抑制的判断结果
+type RetxSuppressionResult int
+
+const (
+	RetxSuppressionNew      RetxSuppressionResult = iota // 新的兴趣包，PIT 条目中没有 pending 的 out-record
+	RetxSuppressionForward                               // 下游的重传，并且已经超过了重传抑制窗口，应该转发
+	RetxSuppressionSuppress                              // 下游的重传，但是仍然在重传抑制窗口内，应该被抑制
+)
+
+// RetxSuppressionInfo
+// 重传抑制保存在 PIT 条目上的状态
+//
+// @Description:
+//  策略如果需要在 PIT 条目上保存自己的状态，应该在自己的状态结构体中内嵌本结构体，这样重传抑制和策略就可以共用 PIT 条目上的同一份状态
+//
+type RetxSuppressionInfo struct {
+	interval uint64 // 当前的重传抑制窗口，单位 ms
+}
+
+//
+// 获取 PIT 条目上保存的重传抑制状态，内嵌了 RetxSuppressionInfo 的结构体会自动实现本方法
+//
+// @Description:
+// @receiver info
+// @return *RetxSuppressionInfo
+//
+func (info *RetxSuppressionInfo) getRetxSuppressionInfo() *RetxSuppressionInfo {
+	return info
+}
+
+// retxSuppressionInfoHolder 保存了重传抑制状态的 PIT 条目状态
+type retxSuppressionInfoHolder interface {
+	getRetxSuppressionInfo() *RetxSuppressionInfo
+}
+
+// RetxSuppression
+// 指数退避的兴趣包重传抑制
+//
+// @Description:
+//  1. 下游的兴趣包到来时，如果 PIT 条目中没有 pending 的 out-record ，则是一个新的兴趣包，重传抑制窗口被重置为初始大小；
+//  2. 否则是下游的重传：距离最后一次往上游转发的时间还不到重传抑制窗口时被抑制，超过时应该被转发，同时重传抑制窗口扩大 multiplier
+//     倍，直到 maxInterval ；
+//  3. 通过这种方式，策略既可以尽快从上游的丢包中恢复，又不会因为下游频繁的重传而向上游发送过多的兴趣包。
+//
+type RetxSuppression struct {
+	initialInterval uint64 // 重传抑制窗口的初始大小，单位 ms
+	multiplier      uint64 // 每次转发重传之后重传抑制窗口扩大的倍数
+	maxInterval     uint64 // 重传抑制窗口的最大值，单位 ms
+}
+
+// CreateRetxSuppression
+// 创建一个指数退避的重传抑制
+//
+// @Description:
+// @param initialInterval	重传抑制窗口的初始大小，单位 ms
+// @param multiplier		每次转发重传之后重传抑制窗口扩大的倍数
+// @param maxInterval		重传抑制窗口的最大值，单位 ms
+// @return *RetxSuppression
+//
+func CreateRetxSuppression(initialInterval uint64, multiplier uint64, maxInterval uint64) *RetxSuppression {
+	return &RetxSuppression{
+		initialInterval: initialInterval,
+		multiplier:      multiplier,
+		maxInterval:     maxInterval,
+	}
+}
+
+// CreateDefaultRetxSuppression
+// 使用默认参数创建一个指数退避的重传抑制
+//
+// @Description:
+// @return *RetxSuppression
+//
+func CreateDefaultRetxSuppression() *RetxSuppression {
+	return CreateRetxSuppression(DefaultRetxSuppressionInitialInterval, DefaultRetxSuppressionMultiplier,
+		DefaultRetxSuppressionMaxInterval)
+}
+
+// GetInitialInterval
+// 获取重传抑制窗口的初始大小，单位 ms
+//
+// @Description:
+// @receiver r
+// @return uint64
+//
+func (r *RetxSuppression) GetInitialInterval() uint64 {
+	return r.initialInterval
+}
+
+// GetMultiplier
+// 获取每次转发重传之后重传抑制窗口扩大的倍数
+//
+// @Description:
+// @receiver r
+// @return uint64
+//
+func (r *RetxSuppression) GetMultiplier() uint64 {
+	return r.multiplier
+}
+
+// GetMaxInterval
+// 获取重传抑制窗口的最大值，单位 ms
+//
+// @Description:
+// @receiver r
+// @return uint64
+//
+func (r *RetxSuppression) GetMaxInterval() uint64 {
+	return r.maxInterval
+}
+
+// Decide
+// 在 now 时刻判断 PIT 条目收到的兴趣包是新的兴趣包、应该被转发的重传还是应该被抑制的重传，并更新 info 中的重传抑制窗口
+//
+// @Description:
+//  最后一次往上游转发的时间取 pending 的 out-record 中最晚的 SendTime ，所以策略通过其它途径（例如收到 Nack 之后重试）转发的兴趣包
+//  同样会推迟下一次重传被转发的时间
+// @receiver r
+// @param pitEntry
+// @param info
+// @param now			当前时间，单位 ms
+// @return RetxSuppressionResult
+//
+func (r *RetxSuppression) Decide(pitEntry *table.PITEntry, info *RetxSuppressionInfo, now uint64) RetxSuppressionResult {
+	pending := false
+	lastSendTime := uint64(0)
+	for _, outRecord := range pitEntry.GetOutRecords() {
+		if outRecord.ExpireTime > now && outRecord.NackHeader == nil {
+			pending = true
+			if outRecord.SendTime > lastSendTime {
+				lastSendTime = outRecord.SendTime
+			}
+		}
+	}
+
+	if !pending {
+		info.interval = r.initialInterval
+		return RetxSuppressionNew
+	}
+
+	// 策略切换等情况下 PIT 条目上可能还没有重传抑制状态，此时使用初始窗口
+	if info.interval == 0 {
+		info.interval = r.initialInterval
+	}
+	if now < lastSendTime+info.interval {
+		return RetxSuppressionSuppress
+	}
+	info.interval *= r.multiplier
+	if info.interval > r.maxInterval {
+		info.interval = r.maxInterval
+	}
+	return RetxSuppressionForward
+}
@@ -0,0 +1,76 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 0:40 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package fw
+
+import (
+	"fmt"
+	"minlib/component"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/table"
+	"testing"
+)
+
+func TestRetxSuppression_Decide(t *testing.T) {
+	retx := CreateRetxSuppression(10, 2, 30)
+	pitEntry := table.CreatePITEntry()
+	info := new(RetxSuppressionInfo)
+	now := uint64(1000)
+
+	// 没有 pending 的 out-record => 新的兴趣包
+	if result := retx.Decide(pitEntry, info, now); result != RetxSuppressionNew {
+		t.Fatal("expect new, got", result)
+	}
+	outRecord := &table.OutRecord{LogicFace: &lf.LogicFace{LogicFaceId: 1}, SendTime: now, ExpireTime: now + 4000}
+	pitEntry.OutRecordList[1] = outRecord
+
+	// 窗口内的重传被抑制，超过窗口的重传被转发，并且窗口按指数扩大直到上限
+	expected := []struct {
+		delay  uint64
+		result RetxSuppressionResult
+	}{
+		{5, RetxSuppressionSuppress},
+		{10, RetxSuppressionForward}, // 窗口 => 20
+		{19, RetxSuppressionSuppress},
+		{20, RetxSuppressionForward}, // 窗口 => 30 （上限）
+		{30, RetxSuppressionForward},
+	}
+	for i, e := range expected {
+		result := retx.Decide(pitEntry, info, outRecord.SendTime+e.delay)
+		if result != e.result {
+			t.Fatal("case", i, "expect", e.result, "got", result)
+		}
+		if result == RetxSuppressionForward {
+			outRecord.SendTime += e.delay
+		}
+		fmt.Println("retx interval =>", info.interval)
+	}
+	if info.interval != 30 {
+		t.Fatal("expect retx interval capped at 30, got", info.interval)
+	}
+
+	// 上游 Nack 之后不再 pending => 重新作为新的兴趣包，窗口被重置
+	outRecord.NackHeader = new(component.NackHeader)
+	if result := retx.Decide(pitEntry, info, outRecord.SendTime+1); result != RetxSuppressionNew || info.interval != 10 {
+		t.Fatal("expect new after nack, got", result, info.interval)
+	}
+}
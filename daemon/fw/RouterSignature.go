@@ -0,0 +1,324 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 2:00 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"github.com/sirupsen/logrus"
+	common2 "minlib/common"
+	"minlib/component"
+	"minlib/encoding"
+	"minlib/packet"
+	"minlib/security"
+)
+
+// IRouterSigner
+// 中间路由器签名的底层实现
+//
+// @Description:
+//  1. 签名只覆盖网络包的标识区、只读区和之前的签名，不覆盖 TTL 等逐跳变化的可变区，所以下游路由器可以验证之前每一跳追加的签名；
+//  2. 一个签名实现同一时刻只会被一个协程使用，所以实现不需要自己加锁，RouterSignature 通过签名实现池在多个转发分片之间分配签名实现。
+//
+type IRouterSigner interface {
+	// SignatureNum 获取签名区中已有的签名个数（包括生产者的签名）
+	SignatureNum(signatureField *component.SignatureField) int
+
+	// IsLastSigner 判断签名区中的最后一个签名是否是使用当前路由器的网络身份签的
+	IsLastSigner(signatureField *component.SignatureField) bool
+
+	// SignInterest 使用当前路由器的网络身份，在 Interest 的签名区末尾追加一个签名
+	SignInterest(interest *packet.Interest) error
+
+	// SignData 使用当前路由器的网络身份，在 Data 的签名区末尾追加一个签名
+	SignData(data *packet.Data) error
+
+	// SignGPPkt 使用当前路由器的网络身份，在 GPPkt 的签名区末尾追加一个签名
+	SignGPPkt(gPPkt *packet.GPPkt) error
+}
+
+// GetMINPacketSignatureNum
+// 获取 MINPacket 签名区中已有的签名个数（包括生产者的签名）
+//
+// @Description:
+// @param minPacket
+// @return int
+//
+func GetMINPacketSignatureNum(minPacket *packet.MINPacket) int {
+	return minPacket.SignatureField.SignatureNum()
+}
+
+// GetMINPacketSignerIdentifier
+// 获取 MINPacket 生产者（签名区中的第一个签名）的网络身份
+//
+// @Description:
+//  签名者的网络身份取自签名的 KeyLocator ，信任模式根据它判断生产者是否有权为网络包的标识签名。中间路由器只会往已经带有生产者签名的包
+//  追加签名，所以签名区中的第一个签名总是生产者的签名
+// @param minPacket
+// @return *component.Identifier
+// @return error
+//
+func GetMINPacketSignerIdentifier(minPacket *packet.MINPacket) (*component.Identifier, error) {
+	signature, err := minPacket.SignatureField.GetSignature(0)
+	if err != nil {
+		return nil, err
+	}
+	return signature.SigInfo.KeyLocator.Identifier, nil
+}
+
+// keyChainRouterSigner
+// 使用 KeyChain 当前网络身份签名的中间路由器签名实现
+//
+// @Description:
+//  每个签名实现独占一个 KeyChain ，不和其它签名实现以及管理模块共享，所以不需要加锁
+//
+type keyChainRouterSigner struct {
+	keyChain *security.KeyChain
+}
+
+// CreateKeyChainRouterSigner
+// 创建一个使用 KeyChain 当前网络身份签名的中间路由器签名实现
+//
+// @Description:
+//  KeyChain 的当前网络身份需要已经被解锁，并且传入的 KeyChain 只能被这一个签名实现使用
+// @param keyChain
+// @return IRouterSigner
+//
+func CreateKeyChainRouterSigner(keyChain *security.KeyChain) IRouterSigner {
+	return &keyChainRouterSigner{keyChain: keyChain}
+}
+
+func (k *keyChainRouterSigner) SignatureNum(signatureField *component.SignatureField) int {
+	return signatureField.SignatureNum()
+}
+
+func (k *keyChainRouterSigner) IsLastSigner(signatureField *component.SignatureField) bool {
+	signatureNum := signatureField.SignatureNum()
+	if signatureNum == 0 {
+		return false
+	}
+	signature, err := signatureField.GetSignature(signatureNum - 1)
+	if err != nil || signature.SigInfo.KeyLocator.Identifier == nil {
+		return false
+	}
+	identity := k.keyChain.GetCurrentIdentity()
+	return identity != nil && signature.SigInfo.KeyLocator.Identifier.ToUri() == identity.Name
+}
+
+func (k *keyChainRouterSigner) SignInterest(interest *packet.Interest) error {
+	return k.keyChain.SignInterest(interest)
+}
+
+func (k *keyChainRouterSigner) SignData(data *packet.Data) error {
+	return k.keyChain.SignData(data)
+}
+
+func (k *keyChainRouterSigner) SignGPPkt(gPPkt *packet.GPPkt) error {
+	return k.keyChain.SignGPPkt(gPPkt)
+}
+
+// RouterSignature
+// 中间路由器签名
+//
+// @Description:
+//  1. 开启中间路由器签名之后，路由器在 Outgoing Interest 、 Outgoing Data 和 Outgoing GPPkt 管道中，即转发决策已经做出、
+//     网络包确定要被发出之后，使用自己的网络身份在包的签名区末尾追加一个签名，这样每个包都带有一条可以被审计的、经过认证的转发路径，
+//     被策略丢弃或者被插件拦截的包不会消耗签名的开销；
+//  2. 转发器中的网络包是多个协程共享的（ PIT 、 ContentStore 、策略以及 LogicFace 的发送协程），所以签名不会修改传入的网络包，而是
+//     为每个出口签名一个通过编码再解码得到的拷贝， ContentStore 中缓存的始终是没有本路由器签名的数据包；签名区的最后一个签名已经是
+//     本路由器的签名时不会重复签名；
+//  3. 签名区的第一个签名是生产者的签名，没有生产者签名的包不会被追加签名，以免中间路由器的签名被当成生产者的签名；之后最多追加
+//     maxRouterSignatureNum 个中间路由器签名，达到上限之后的路由器不再追加签名，直接转发；
+//  4. 多个转发分片会并发的签名，签名实现保存在一个池中，每次签名时取出一个独占使用，签名实现的个数和转发分片的个数相同时，
+//     各个分片之间不会互相等待；
+//  5. 下游路由器在 PacketValidator 中验证之前每一跳追加的签名，并丢弃中间路由器签名超过上限的包。
+//
+type RouterSignature struct {
+	signers               chan IRouterSigner // 签名实现池
+	maxRouterSignatureNum int                // 最多追加的中间路由器签名个数
+}
+
+// CreateRouterSignature
+// 创建一个中间路由器签名
+//
+// @Description:
+// @param signers					签名实现，每个签名实现同一时刻只会被一个协程使用
+// @param maxRouterSignatureNum		最多追加的中间路由器签名个数
+// @return *RouterSignature
+//
+func CreateRouterSignature(signers []IRouterSigner, maxRouterSignatureNum int) *RouterSignature {
+	r := &RouterSignature{
+		signers:               make(chan IRouterSigner, len(signers)),
+		maxRouterSignatureNum: maxRouterSignatureNum,
+	}
+	for _, signer := range signers {
+		r.signers <- signer
+	}
+	return r
+}
+
+// AppendToInterest
+// 为 Interest 的一个拷贝在签名区末尾追加本路由器的签名
+//
+// @Description:
+//  传入的 interest 不会被修改
+// @receiver r
+// @param interest
+// @return *packet.Interest		需要发出的 Interest ，追加了签名时为签过名的拷贝，否则为传入的 interest
+//
+func (r *RouterSignature) AppendToInterest(interest *packet.Interest) *packet.Interest {
+	signed := interest
+	r.append(&interest.SignatureField, func(signer IRouterSigner) error {
+		minPacket, err := copyMINPacketByWire(interest)
+		if err != nil {
+			return err
+		}
+		interestCopy, err := packet.NewInterestByMINPacket(minPacket)
+		if err != nil {
+			return err
+		}
+		if err := signer.SignInterest(interestCopy); err != nil {
+			return err
+		}
+		signed = interestCopy
+		return nil
+	})
+	return signed
+}
+
+// AppendToData
+// 为 Data 的一个拷贝在签名区末尾追加本路由器的签名
+//
+// @Description:
+//  传入的 data 不会被修改
+// @receiver r
+// @param data
+// @return *packet.Data		需要发出的 Data ，追加了签名时为签过名的拷贝，否则为传入的 data
+//
+func (r *RouterSignature) AppendToData(data *packet.Data) *packet.Data {
+	signed := data
+	r.append(&data.SignatureField, func(signer IRouterSigner) error {
+		minPacket, err := copyMINPacketByWire(data)
+		if err != nil {
+			return err
+		}
+		dataCopy, err := packet.NewDataByMINPacket(minPacket)
+		if err != nil {
+			return err
+		}
+		if err := signer.SignData(dataCopy); err != nil {
+			return err
+		}
+		signed = dataCopy
+		return nil
+	})
+	return signed
+}
+
+// AppendToGPPkt
+// 为 GPPkt 的一个拷贝在签名区末尾追加本路由器的签名
+//
+// @Description:
+//  传入的 gPPkt 不会被修改
+// @receiver r
+// @param gPPkt
+// @return *packet.GPPkt		需要发出的 GPPkt ，追加了签名时为签过名的拷贝，否则为传入的 gPPkt
+//
+func (r *RouterSignature) AppendToGPPkt(gPPkt *packet.GPPkt) *packet.GPPkt {
+	signed := gPPkt
+	r.append(&gPPkt.SignatureField, func(signer IRouterSigner) error {
+		minPacket, err := copyMINPacketByWire(gPPkt)
+		if err != nil {
+			return err
+		}
+		gPPktCopy, err := packet.NewGPPktByMINPacket(minPacket)
+		if err != nil {
+			return err
+		}
+		if err := signer.SignGPPkt(gPPktCopy); err != nil {
+			return err
+		}
+		signed = gPPktCopy
+		return nil
+	})
+	return signed
+}
+
+//
+// 从签名实现池中取出一个签名实现，在签名区末尾追加本路由器的签名
+//
+// @Description:
+// @receiver r
+// @param signatureField		网络包的签名区
+// @param sign				使用取出的签名实现为网络包的拷贝签名
+// @return bool				追加了签名时返回 true ，没有生产者签名、已经签过名、签名个数已经达到上限或者签名失败时返回 false
+//
+func (r *RouterSignature) append(signatureField *component.SignatureField, sign func(signer IRouterSigner) error) bool {
+	signer := <-r.signers
+	defer func() {
+		r.signers <- signer
+	}()
+
+	signatureNum := signer.SignatureNum(signatureField)
+	if signatureNum == 0 || signatureNum >= r.maxRouterSignatureNum+1 || signer.IsLastSigner(signatureField) {
+		return false
+	}
+	if err := sign(signer); err != nil {
+		// 签名失败不影响转发，只是转发路径上少了一个签名
+		common2.LogWarnWithFields(logrus.Fields{
+			"err": err,
+		}, "Append router signature failed")
+		return false
+	}
+	return true
+}
+
+//
+// 通过编码再解码得到网络包的一个独立的拷贝
+//
+// @Description:
+// @param pkt
+// @return *packet.MINPacket
+// @return error
+//
+func copyMINPacketByWire(pkt encoding.IEncodingAble) (*packet.MINPacket, error) {
+	var encoder encoding.Encoder
+	if err := encoder.EncoderReset(encoding.MaxPacketSize, 0); err != nil {
+		return nil, err
+	}
+	bufLen, err := pkt.WireEncode(&encoder)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := encoder.GetBuffer()
+	if err != nil {
+		return nil, err
+	}
+	value := make([]byte, bufLen)
+	copy(value, buf[:bufLen])
+	block, err := encoding.CreateBlockByBuffer(value, true)
+	if err != nil {
+		return nil, err
+	}
+	var minPacket packet.MINPacket
+	if err := minPacket.WireDecode(block); err != nil {
+		return nil, err
+	}
+	return &minPacket, nil
+}
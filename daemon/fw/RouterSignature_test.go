@@ -0,0 +1,237 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 2:20 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package fw
+
+import (
+	"errors"
+	"fmt"
+	"minlib/component"
+	"minlib/packet"
+	"mir-go/daemon/common"
+	"mir-go/daemon/lf"
+	"mir-go/daemon/plugin"
+	"sync"
+	"testing"
+)
+
+// fakeRouterSigner 只记录每个签名区的签名个数和最后一个签名者的签名实现
+type fakeRouterSigner struct {
+	name          string
+	signatureNums map[*component.SignatureField]int
+	lastSigners   map[*component.SignatureField]string
+	fail          bool
+	lock          sync.Mutex
+}
+
+func newFakeRouterSigner(name string) *fakeRouterSigner {
+	return &fakeRouterSigner{
+		name:          name,
+		signatureNums: make(map[*component.SignatureField]int),
+		lastSigners:   make(map[*component.SignatureField]string),
+	}
+}
+
+func (f *fakeRouterSigner) setLastSigner(signatureField *component.SignatureField, signatureNum int, lastSigner string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.signatureNums[signatureField] = signatureNum
+	f.lastSigners[signatureField] = lastSigner
+}
+
+func (f *fakeRouterSigner) SignatureNum(signatureField *component.SignatureField) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.signatureNums[signatureField]
+}
+
+func (f *fakeRouterSigner) IsLastSigner(signatureField *component.SignatureField) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.lastSigners[signatureField] == f.name
+}
+
+func (f *fakeRouterSigner) SignInterest(interest *packet.Interest) error {
+	return f.sign(&interest.SignatureField)
+}
+
+func (f *fakeRouterSigner) SignData(data *packet.Data) error {
+	return f.sign(&data.SignatureField)
+}
+
+func (f *fakeRouterSigner) SignGPPkt(gPPkt *packet.GPPkt) error {
+	return f.sign(&gPPkt.SignatureField)
+}
+
+func (f *fakeRouterSigner) sign(signatureField *component.SignatureField) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.fail {
+		return errors.New("sign failed")
+	}
+	f.signatureNums[signatureField]++
+	f.lastSigners[signatureField] = f.name
+	return nil
+}
+
+func TestRouterSignature_Append(t *testing.T) {
+	signer := newFakeRouterSigner("/router")
+	routerSignature := CreateRouterSignature([]IRouterSigner{signer}, 2)
+	name, _ := component.CreateIdentifierByString("/min/router-signature")
+	interest := new(packet.Interest)
+	interest.SetName(name)
+	interest.InterestLifeTime.SetInterestLifeTime(4000)
+	// 已经带有生产者的签名
+	signer.setLastSigner(&interest.SignatureField, 1, "/producer")
+
+	// 同一个包被转发到多个下一跳时，每个下一跳各自签名一个拷贝，共享的包不会被修改
+	for i := 0; i < 3; i++ {
+		signed := routerSignature.AppendToInterest(interest)
+		fmt.Println("next hop", i, "signatures =>", signer.SignatureNum(&signed.SignatureField))
+		if signed == interest || signer.SignatureNum(&signed.SignatureField) != 1 {
+			t.Fatal("expect a signed copy at next hop", i)
+		}
+		if signer.SignatureNum(&interest.SignatureField) != 1 || signer.IsLastSigner(&interest.SignatureField) {
+			t.Fatal("expect shared interest not modified at next hop", i)
+		}
+	}
+
+	// 最后一个签名已经是本路由器的签名时不重复签名
+	signer.setLastSigner(&interest.SignatureField, 2, "/router")
+	if routerSignature.AppendToInterest(interest) != interest {
+		t.Fatal("expect no duplicated router signature")
+	}
+
+	// 之后的每一跳各追加一个签名，最多追加 2 个中间路由器签名
+	signer.setLastSigner(&interest.SignatureField, 2, "/previous-router")
+	if routerSignature.AppendToInterest(interest) == interest {
+		t.Fatal("expect the second router signature to be appended")
+	}
+	signer.setLastSigner(&interest.SignatureField, 3, "/previous-router")
+	if routerSignature.AppendToInterest(interest) != interest {
+		t.Fatal("expect no more than 3 signatures")
+	}
+
+	// 没有生产者签名的包不追加签名，以免中间路由器的签名被当成生产者的签名
+	data := new(packet.Data)
+	data.SetName(name)
+	if routerSignature.AppendToData(data) != data || signer.SignatureNum(&data.SignatureField) != 0 {
+		t.Fatal("expect unsigned data not to be signed")
+	}
+
+	// 签名失败时不追加
+	signer.fail = true
+	signer.setLastSigner(&data.SignatureField, 1, "/producer")
+	if routerSignature.AppendToData(data) != data || signer.SignatureNum(&data.SignatureField) != 1 {
+		t.Fatal("expect append to fail")
+	}
+}
+
+func TestRouterSignature_ConcurrentAppend(t *testing.T) {
+	// 多个分片并发签名时，每个签名实现同一时刻只会被一个协程使用
+	signers := make([]IRouterSigner, 0, 4)
+	for i := 0; i < 4; i++ {
+		signers = append(signers, newFakeRouterSigner("/router"))
+	}
+	routerSignature := CreateRouterSignature(signers, 4)
+	name, _ := component.CreateIdentifierByString("/min/router-signature")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data := new(packet.Data)
+			data.SetName(name)
+			for _, signer := range signers {
+				signer.(*fakeRouterSigner).setLastSigner(&data.SignatureField, 1, "/producer")
+			}
+			routerSignature.AppendToData(data)
+		}()
+	}
+	wg.Wait()
+	if len(routerSignature.signers) != len(signers) {
+		t.Fatal("expect all signers to be returned to the pool")
+	}
+}
+
+func TestRouterSignature_ConcurrentEgress(t *testing.T) {
+	config := new(common.MIRConfig)
+	config.Init()
+	forwarder := new(Forwarder)
+	if err := forwarder.Init(config, new(plugin.GlobalPluginManager), make(chan *lf.IncomingPacketData, 20)); err != nil {
+		t.Fatal(err)
+	}
+	signers := []*fakeRouterSigner{newFakeRouterSigner("/router"), newFakeRouterSigner("/router")}
+	forwarder.EnableRouterSignature([]IRouterSigner{signers[0], signers[1]}, 2)
+
+	name, _ := component.CreateIdentifierByString("/min/router-signature")
+	data := new(packet.Data)
+	data.SetName(name)
+	data.FreshnessPeriod.SetFreshnessPeriod(10000)
+	forwarder.ICS.Insert(data)
+	interest := new(packet.Interest)
+	interest.SetName(name)
+	csEntry, _ := forwarder.ICS.Find(interest)
+	if csEntry == nil {
+		t.Fatal("expect data cached")
+	}
+	cached := csEntry.GetData()
+	for _, signer := range signers {
+		signer.setLastSigner(&cached.SignatureField, 1, "/producer")
+	}
+
+	// 同一个缓存的数据包一边作为 ContentStore 命中从其它分片发出，一边被转发出去，同时 LogicFace 的发送协程在编码它
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(2)
+		go func(logicFaceId uint64) {
+			defer wg.Done()
+			forwarder.OnOutgoingData(&lf.LogicFace{LogicFaceId: logicFaceId}, cached)
+		}(uint64(i))
+		go func() {
+			defer wg.Done()
+			if _, err := copyMINPacketByWire(cached); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 每个出口各自签名一个拷贝，缓存中的数据包依然只有生产者的签名
+	signedCopies := 0
+	for _, signer := range signers {
+		if signer.SignatureNum(&cached.SignatureField) != 1 || signer.IsLastSigner(&cached.SignatureField) {
+			t.Fatal("expect cached data not signed by router")
+		}
+		signer.lock.Lock()
+		for signatureField, signatureNum := range signer.signatureNums {
+			if signatureField != &cached.SignatureField && signatureNum == 1 {
+				signedCopies++
+			}
+		}
+		signer.lock.Unlock()
+	}
+	fmt.Println("signed copies =>", signedCopies)
+	if signedCopies != 32 {
+		t.Fatal("expect 32 signed copies, got", signedCopies)
+	}
+}
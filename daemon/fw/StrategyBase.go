@@ -37,18 +37,49 @@ import (
 // @Description:
 //
 type StrategyBase struct {
-	forwarder *Forwarder
+	forwarder       *Forwarder
+	retxSuppression *RetxSuppression // 兴趣包重传抑制，没有设置时在 SetForwarder 中创建一个默认参数的实例
 }
 
 // SetForwarder
 // 保存Forwarder指针
 //
 // @Description:
+//  如果还没有设置兴趣包重传抑制，则在这边创建一个默认参数的重传抑制，之后所有的兴趣包共用这一个实例
 // @receiver s
 // @param forwarder
 //
 func (s *StrategyBase) SetForwarder(forwarder *Forwarder) {
 	s.forwarder = forwarder
+	if s.retxSuppression == nil {
+		s.retxSuppression = CreateDefaultRetxSuppression()
+	}
+}
+
+// SetRetxSuppression
+// 设置策略使用的兴趣包重传抑制
+//
+// @Description:
+// @receiver s
+// @param retxSuppression		为 nil 时恢复使用默认参数的重传抑制
+//
+func (s *StrategyBase) SetRetxSuppression(retxSuppression *RetxSuppression) {
+	if retxSuppression == nil {
+		retxSuppression = CreateDefaultRetxSuppression()
+	}
+	s.retxSuppression = retxSuppression
+}
+
+// GetRetxSuppression
+// 获取策略使用的兴趣包重传抑制
+//
+// @Description:
+//  返回的是策略保存的实例，没有通过 SetRetxSuppression 设置过时为 SetForwarder 中创建的默认参数的重传抑制
+// @receiver s
+// @return *RetxSuppression
+//
+func (s *StrategyBase) GetRetxSuppression() *RetxSuppression {
+	return s.retxSuppression
 }
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -127,6 +158,18 @@ func (s *StrategyBase) AfterReceiveData(ingress *lf.LogicFace, data *packet.Data
 	s.sendDataToAll(ingress, data, pitEntry)
 }
 
+// AfterReceiveUnsolicitedData
+// 当收到一个没有匹配到 PIT 条目的 data 时，会触发本触发器（默认不做任何处理）
+//
+// @Description:
+//  策略不能转发未经请求的 data ，只能据此更新自己维护的状态，例如 AsfStrategy 用来统计晚到的探测 data 的 RTT
+// @param ingress		data 到来的入口 LogicFace
+// @param data			收到的 data
+//
+func (s *StrategyBase) AfterReceiveUnsolicitedData(ingress *lf.LogicFace, data *packet.Data) {
+	// 收到一个未经请求的 data
+}
+
 // AfterReceiveNack
 // 当收到一个 Nack 时，会触发本触发器（默认不做任何处理）
 //
@@ -162,6 +205,39 @@ func (s *StrategyBase) AfterReceiveGPPkt(ingress *lf.LogicFace, gPPkt *packet.GP
 	panic("implement me")
 }
 
+// BeforeExpirePendingInterest
+// 当一个没有被满足的 PIT 条目即将被移除时，会触发本触发器（默认不做任何处理）
+//
+// @Description:
+//  PIT 条目因为超时或者被 Nack 而被移除时会触发本触发器，需要统计上游超时信息的策略（例如：自适应转发策略）可以覆盖本触发器。
+// @param pitEntry		即将被移除的PIT条目
+//
+func (s *StrategyBase) BeforeExpirePendingInterest(pitEntry *table.PITEntry) {
+}
+
+// OnFaceUp
+// 当一个新的 LogicFace 被创建时，会触发本触发器（默认不做任何处理）
+//
+// @Description:
+//  本触发器不在转发分片的协程中调用，策略不能在其中访问 PIT。
+// @param face			新创建的 LogicFace
+//
+func (s *StrategyBase) OnFaceUp(face *lf.LogicFace) {
+}
+
+// OnFaceDown
+// 当一个 LogicFace 被关闭时，会触发本触发器（默认不做任何处理）
+//
+// @Description:
+//  转发器会在每个转发分片的协程中分别触发一次本触发器，pitEntries 是该分片中失去了上游、仍然有下游在等待的 PIT 条目。默认不做任何
+//  处理，这些 PIT 条目会在超时之后被移除；需要尽快恢复转发的策略可以覆盖本触发器，例如调用 StrategyBase.reforwardPendingInterests
+//  将其重新转发到其它的上游。
+// @param face			被关闭的 LogicFace
+// @param pitEntries	失去了上游的 PIT 条目
+//
+func (s *StrategyBase) OnFaceDown(face *lf.LogicFace, pitEntries []*table.PITEntry) {
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////
 //// Actions
 //////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -279,10 +355,136 @@ func (s *StrategyBase) rejectPendingInterest(pitEntry *table.PITEntry) {
 	s.forwarder.SetExpiryTime(pitEntry, 0)
 }
 
+//
+// 设置 PIT 条目的超时时间
+//
+// @Description:
+//  策略在重新转发 Interest 之后，可以调用本方法延长 PIT 条目的存活期，避免之前设置的立即过期事件移除 PIT 条目
+// @receiver s
+// @param pitEntry
+// @param duration		单位 ms
+//
+func (s *StrategyBase) setExpiryTimer(pitEntry *table.PITEntry, duration int64) {
+	s.forwarder.SetExpiryTime(pitEntry, duration)
+}
+
+//
+// 在 PIT 条目所在分片的堆定时器中添加一个策略自己的定时事件
+//
+// @Description:
+//  只能在处理该 PIT 条目的触发器中调用，回调在同一个分片的协程中执行。 key 不能和 PIT 条目的标识冲突，已经存在相同 key 的事件时替换之
+// @receiver s
+// @param pitEntry
+// @param duration		单位 ms
+// @param key
+// @param callback
+//
+func (s *StrategyBase) addTimeoutEvent(pitEntry *table.PITEntry, duration int64, key string, callback func()) {
+	s.forwarder.shardTimer(pitEntry.GetIdentifier()).AddTimeoutEvent(duration, key, callback)
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////
 //// 其它辅助函数
 //////////////////////////////////////////////////////////////////////////////////////////////////////
 
+//
+// 对 PIT 条目中所有 out-record 的 Nack 进行聚合
+//
+// @Description:
+//  如果 PIT 条目中所有的 out-record 都已经被 Nack，则将其中最不严重的 Nack 原因返回给所有的下游；否则不做处理，继续等待其它上游
+//  返回 data 或者 Nack。
+// @param ingress		收到 Nack 的入口 LogicFace
+// @param pitEntry		Nack 对应匹配的 PIT 条目
+//
+func (s *StrategyBase) processNack(ingress *lf.LogicFace, pitEntry *table.PITEntry) {
+	// 保存最不严重的 Nack 原因
+	leastSevereReason := component.NackReasonUnknown
+	// 保存没有被 Nack 的出记录的数量
+	notNackedOutRecordNums := 0
+
+	// 在 Strategy.AfterReceiveNack 之前，OnIncomingNack 管道已经将 Nack 头部信息保存到了对应的 out-record 里面
+	// 所以下面的步骤肯定至少能从一个 out-record 中得到 NackHeader
+	for _, outRecord := range pitEntry.GetOutRecords() {
+		if outRecord.NackHeader != nil {
+			if int(outRecord.NackHeader.GetNackReason()) > leastSevereReason {
+				leastSevereReason = int(outRecord.NackHeader.GetNackReason())
+			}
+		} else {
+			notNackedOutRecordNums++
+		}
+	}
+
+	// 如果还有 out-record 没有被 Nack，则不转发nack，等待其它上游返回的nack
+	if notNackedOutRecordNums > 0 {
+		return
+	}
+
+	var nh component.NackHeader
+	nh.SetNackReason(uint64(leastSevereReason))
+
+	s.sendNackToAll(ingress, &nh, pitEntry)
+}
+
+//
+// 将失去了上游的 PIT 条目重新交给策略的 AfterReceiveInterest 转发
+//
+// @Description:
+//  对于每个 PIT 条目，如果仍然存在 pending 的 out-record 则继续等待；否则选择最晚过期的 in-record ，以其中保存的 Interest 和
+//  LogicFace 作为输入重新调用 afterReceiveInterest 。没有未过期 in-record 的 PIT 条目会在超时之后被移除，这边不做处理。
+// @param pitEntries				失去了上游的 PIT 条目
+// @param afterReceiveInterest		策略自己的 AfterReceiveInterest
+//
+func (s *StrategyBase) reforwardPendingInterests(pitEntries []*table.PITEntry,
+	afterReceiveInterest func(ingress *lf.LogicFace, interest *packet.Interest, pitEntry *table.PITEntry)) {
+	now := common.GetCurrentTime()
+	for _, pitEntry := range pitEntries {
+		if pitEntry.IsDeleted() || HasPendingOutRecords(pitEntry) {
+			continue
+		}
+		latest := FindLatestInRecord(pitEntry, now)
+		if latest == nil {
+			continue
+		}
+		common2.LogDebugWithFields(logrus.Fields{
+			"ingress":  latest.LogicFace.LogicFaceId,
+			"pitEntry": pitEntry.GetIdentifier().ToUri(),
+		}, "Reforward pending interest")
+		afterReceiveInterest(latest.LogicFace, latest.Interest, pitEntry)
+	}
+}
+
+//
+// 判断 PIT 条目收到的兴趣包是新的兴趣包、应该被转发的重传还是应该被抑制的重传
+//
+// @Description:
+//  重传抑制的状态保存在 PIT 条目的策略状态上：
+//   - PIT 条目上还没有策略状态时，新建一个 RetxSuppressionInfo 保存到 PIT 条目上；
+//   - 策略自己的状态内嵌了 RetxSuppressionInfo 时，直接使用其中的重传抑制状态，所以这类策略应该在调用本函数之前先在 PIT 条目上保存自己的状态；
+//   - 其它情况下每次都使用一个新的状态，即重传抑制窗口始终为初始大小。
+// @param pitEntry
+// @return RetxSuppressionResult
+//
+func (s *StrategyBase) decideRetxSuppression(pitEntry *table.PITEntry) RetxSuppressionResult {
+	var info *RetxSuppressionInfo
+	switch strategyInfo := pitEntry.GetStrategyInfo().(type) {
+	case retxSuppressionInfoHolder:
+		info = strategyInfo.getRetxSuppressionInfo()
+	case nil:
+		info = new(RetxSuppressionInfo)
+		pitEntry.SetStrategyInfo(info)
+	default:
+		info = new(RetxSuppressionInfo)
+	}
+
+	result := s.GetRetxSuppression().Decide(pitEntry, info, common.GetCurrentTime())
+	if result == RetxSuppressionSuppress {
+		common2.LogDebugWithFields(logrus.Fields{
+			"pitEntry": pitEntry.GetIdentifier().ToUri(),
+		}, "Retransmission suppressed")
+	}
+	return result
+}
+
 //
 // 在 FIB 表中查询可用于转发 Interest 的 FIB 条目
 //
@@ -0,0 +1,517 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 3:10 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"fmt"
+	"mir-go/daemon/table"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// strategyVersionPrefix 策略名字中表示版本号的组件的前缀，例如：/strategy/best-route/v=1
+const strategyVersionPrefix = "v="
+
+// StrategyFactory
+// 策略工厂函数，根据策略名字中携带的参数新建一个策略实例
+//
+// @Description:
+// @param forwarder		策略实例所属的 Forwarder
+// @param params			策略名字中版本号之后的参数组件，例如 /strategy/asf/v=1/probing-interval=30000 的参数为 ["probing-interval=30000"]
+// @return table.IStrategy
+// @return error
+//
+type StrategyFactory func(forwarder *Forwarder, params []string) (table.IStrategy, error)
+
+// StrategyRegistry
+// 策略注册表，以（策略名，版本号）为键保存策略工厂函数
+//
+// @Description:
+//
+type StrategyRegistry struct {
+	lock      sync.RWMutex
+	factories map[string]map[uint64]StrategyFactory // 策略名 => 版本号 => 工厂函数
+	defaults  map[string]uint64                     // 策略名 => 不指定版本号时使用的版本号，没有设置时使用已注册的最新版本
+}
+
+// 全局的策略注册表
+var gStrategyRegistry = &StrategyRegistry{
+	factories: make(map[string]map[uint64]StrategyFactory),
+	defaults:  make(map[string]uint64),
+}
+
+// GetStrategyRegistry
+// 获取全局的策略注册表
+//
+// @Description:
+// @return *StrategyRegistry
+//
+func GetStrategyRegistry() *StrategyRegistry {
+	return gStrategyRegistry
+}
+
+// RegisterStrategy
+// 往全局的策略注册表中注册一个策略
+//
+// @Description:
+// @param name			不带版本号的策略名，例如：/strategy/best-route
+// @param version		版本号
+// @param factory		工厂函数
+// @return error
+//
+func RegisterStrategy(name string, version uint64, factory StrategyFactory) error {
+	return gStrategyRegistry.Register(name, version, factory)
+}
+
+// SetDefaultStrategyVersion
+// 设置全局的策略注册表中某个策略不指定版本号时使用的版本
+//
+// @Description:
+// @param name			不带版本号的策略名，例如：/strategy/best-route
+// @param version		已经注册过的版本号
+// @return error
+//
+func SetDefaultStrategyVersion(name string, version uint64) error {
+	return gStrategyRegistry.SetDefaultVersion(name, version)
+}
+
+// CreateStrategy
+// 根据策略名从全局的策略注册表中新建一个策略实例
+//
+// @Description:
+// @param forwarder
+// @param strategyName
+// @return table.IStrategy
+// @return string			规范化之后的策略名（总是带有版本号）
+// @return error
+//
+func CreateStrategy(forwarder *Forwarder, strategyName string) (table.IStrategy, string, error) {
+	return gStrategyRegistry.Create(forwarder, strategyName)
+}
+
+// Register
+// 注册一个策略
+//
+// @Description:
+// @receiver sr
+// @param name			不带版本号的策略名，例如：/strategy/best-route
+// @param version		版本号
+// @param factory		工厂函数
+// @return error
+//
+func (sr *StrategyRegistry) Register(name string, version uint64, factory StrategyFactory) error {
+	baseName, _, hasVersion, params, err := ParseStrategyName(name)
+	if err != nil {
+		return err
+	}
+	if hasVersion || len(params) > 0 || factory == nil {
+		return createStrategyRegistryErrorByType(InvalidStrategyNameError, name)
+	}
+
+	sr.lock.Lock()
+	defer sr.lock.Unlock()
+	versions, ok := sr.factories[baseName]
+	if !ok {
+		versions = make(map[uint64]StrategyFactory)
+		sr.factories[baseName] = versions
+	}
+	if _, ok := versions[version]; ok {
+		return createStrategyRegistryErrorByType(StrategyAlreadyRegisteredError,
+			makeStrategyName(baseName, version, nil))
+	}
+	versions[version] = factory
+	return nil
+}
+
+// SetDefaultVersion
+// 设置某个策略不指定版本号时使用的版本
+//
+// @Description:
+//  新版本改变了策略的行为时，可以将默认版本固定为旧版本，避免只使用策略名配置的前缀在升级之后悄悄换成新的行为
+// @receiver sr
+// @param name			不带版本号的策略名，例如：/strategy/best-route
+// @param version		已经注册过的版本号
+// @return error
+//
+func (sr *StrategyRegistry) SetDefaultVersion(name string, version uint64) error {
+	sr.lock.Lock()
+	defer sr.lock.Unlock()
+	versions, ok := sr.factories[name]
+	if !ok {
+		return createStrategyRegistryErrorByType(UnknownStrategyError, name)
+	}
+	if _, ok := versions[version]; !ok {
+		return createStrategyRegistryErrorByType(UnknownStrategyVersionError, makeStrategyName(name, version, nil))
+	}
+	sr.defaults[name] = version
+	return nil
+}
+
+// Create
+// 根据策略名新建一个策略实例
+//
+// @Description:
+//  1. 策略名中没有指定版本号时，使用通过 SetDefaultVersion 设置的默认版本，没有设置时使用已注册的最新版本；
+//  2. 策略名或者版本号没有注册时返回错误。
+// @receiver sr
+// @param forwarder
+// @param strategyName		例如：/strategy/best-route、/strategy/best-route/v=1、/strategy/asf/v=1/probing-interval=30000
+// @return table.IStrategy
+// @return string			规范化之后的策略名（总是带有版本号）
+// @return error
+//
+func (sr *StrategyRegistry) Create(forwarder *Forwarder, strategyName string) (table.IStrategy, string, error) {
+	baseName, version, hasVersion, params, err := ParseStrategyName(strategyName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sr.lock.RLock()
+	versions, ok := sr.factories[baseName]
+	if !ok {
+		sr.lock.RUnlock()
+		return nil, "", createStrategyRegistryErrorByType(UnknownStrategyError, strategyName)
+	}
+	if defaultVersion, ok := sr.defaults[baseName]; !hasVersion && ok {
+		version = defaultVersion
+	} else if !hasVersion {
+		for v := range versions {
+			if v >= version {
+				version = v
+			}
+		}
+	}
+	factory, ok := versions[version]
+	sr.lock.RUnlock()
+	if !ok {
+		return nil, "", createStrategyRegistryErrorByType(UnknownStrategyVersionError, strategyName)
+	}
+
+	strategy, err := factory(forwarder, params)
+	if err != nil {
+		return nil, "", err
+	}
+	return strategy, makeStrategyName(baseName, version, params), nil
+}
+
+// IsRegistered
+// 判断某个策略名（及其指定的版本）是否已经注册
+//
+// @Description:
+// @receiver sr
+// @param strategyName
+// @return bool
+//
+func (sr *StrategyRegistry) IsRegistered(strategyName string) bool {
+	baseName, version, hasVersion, _, err := ParseStrategyName(strategyName)
+	if err != nil {
+		return false
+	}
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+	versions, ok := sr.factories[baseName]
+	if !ok {
+		return false
+	}
+	if !hasVersion {
+		return true
+	}
+	_, ok = versions[version]
+	return ok
+}
+
+// List
+// 获取所有已注册的策略名（带版本号），按字典序排序
+//
+// @Description:
+// @receiver sr
+// @return []string
+//
+func (sr *StrategyRegistry) List() []string {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+	names := make([]string, 0)
+	for baseName, versions := range sr.factories {
+		for version := range versions {
+			names = append(names, makeStrategyName(baseName, version, nil))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseStrategyName
+// 解析策略名
+//
+// @Description:
+//  策略名的格式为 /<name>[/v=<version>[/<param>...]]，只有指定了版本号之后，才可以在后面携带参数组件。
+// @param strategyName
+// @return baseName		不带版本号和参数的策略名
+// @return version		版本号
+// @return hasVersion		策略名中是否指定了版本号
+// @return params			版本号之后的参数组件
+// @return err
+//
+func ParseStrategyName(strategyName string) (baseName string, version uint64, hasVersion bool, params []string, err error) {
+	if !strings.HasPrefix(strategyName, "/") {
+		err = createStrategyRegistryErrorByType(InvalidStrategyNameError, strategyName)
+		return
+	}
+	components := strings.Split(strings.TrimSuffix(strategyName[1:], "/"), "/")
+	for _, c := range components {
+		if c == "" {
+			err = createStrategyRegistryErrorByType(InvalidStrategyNameError, strategyName)
+			return
+		}
+	}
+	nameComponents := make([]string, 0, len(components))
+	for i, c := range components {
+		if strings.HasPrefix(c, strategyVersionPrefix) {
+			version, err = strconv.ParseUint(c[len(strategyVersionPrefix):], 10, 64)
+			if err != nil {
+				err = createStrategyRegistryErrorByType(InvalidStrategyNameError, strategyName)
+				return
+			}
+			hasVersion = true
+			params = components[i+1:]
+			break
+		}
+		nameComponents = append(nameComponents, c)
+	}
+	if len(nameComponents) == 0 {
+		err = createStrategyRegistryErrorByType(InvalidStrategyNameError, strategyName)
+		return
+	}
+	baseName = "/" + strings.Join(nameComponents, "/")
+	return
+}
+
+// ParseStrategyParams
+// 将 key=value 形式的参数组件解析成 map
+//
+// @Description:
+// @param params
+// @return map[string]string
+// @return error
+//
+func ParseStrategyParams(params []string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, param := range params {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, createStrategyRegistryErrorByType(InvalidStrategyParameterError, param)
+		}
+		result[kv[0]] = kv[1]
+	}
+	return result, nil
+}
+
+//
+// 根据策略名、版本号和参数组件拼接出规范化的策略名
+//
+// @Description:
+// @param baseName
+// @param version
+// @param params
+// @return string
+//
+func makeStrategyName(baseName string, version uint64, params []string) string {
+	name := fmt.Sprintf("%s/%s%d", baseName, strategyVersionPrefix, version)
+	if len(params) > 0 {
+		name += "/" + strings.Join(params, "/")
+	}
+	return name
+}
+
+//
+// 不接受任何参数的策略使用本函数检查参数
+//
+// @Description:
+// @param params
+// @return error
+//
+func rejectStrategyParams(params []string) error {
+	if len(params) > 0 {
+		return createStrategyRegistryErrorByType(InvalidStrategyParameterError, strings.Join(params, "/"))
+	}
+	return nil
+}
+
+//
+// 根据 retx-initial=<ms>、retx-multiplier=<n>、retx-max=<ms> 形式的参数创建重传抑制，没有指定的参数使用默认值
+//
+// @Description:
+//  要求 retx-initial 大于 0 ， retx-multiplier 不小于 1 ， retx-max 不小于 retx-initial ，出现其它参数时返回错误
+// @param params
+// @return *RetxSuppression
+// @return error
+//
+func createRetxSuppressionByParams(params []string) (*RetxSuppression, error) {
+	kvs, err := ParseStrategyParams(params)
+	if err != nil {
+		return nil, err
+	}
+	initialInterval := uint64(DefaultRetxSuppressionInitialInterval)
+	multiplier := uint64(DefaultRetxSuppressionMultiplier)
+	maxInterval := uint64(DefaultRetxSuppressionMaxInterval)
+	for k, v := range kvs {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, createStrategyRegistryErrorByType(InvalidStrategyParameterError, k+"="+v)
+		}
+		switch k {
+		case "retx-initial":
+			initialInterval = n
+		case "retx-multiplier":
+			multiplier = n
+		case "retx-max":
+			maxInterval = n
+		default:
+			return nil, createStrategyRegistryErrorByType(InvalidStrategyParameterError, k+"="+v)
+		}
+	}
+	if initialInterval == 0 || multiplier == 0 || maxInterval < initialInterval {
+		return nil, createStrategyRegistryErrorByType(InvalidStrategyParameterError, strings.Join(params, "/"))
+	}
+	return CreateRetxSuppression(initialInterval, multiplier, maxInterval), nil
+}
+
+func init() {
+	// 支持的参数：retx-initial=<ms>、retx-multiplier=<n>、retx-max=<ms>
+	_ = RegisterStrategy(BestRouteStrategyName, 1, func(forwarder *Forwarder, params []string) (table.IStrategy, error) {
+		retxSuppression, err := createRetxSuppressionByParams(params)
+		if err != nil {
+			return nil, err
+		}
+		strategy := new(BestRouteStrategy)
+		strategy.SetForwarder(forwarder)
+		strategy.SetRetxSuppression(retxSuppression)
+		return strategy, nil
+	})
+
+	// v=2 在收到 Nack 或者下游重传时会重试其它的下一跳，支持的参数与 v=1 相同
+	_ = RegisterStrategy(BestRouteStrategyName, 2, func(forwarder *Forwarder, params []string) (table.IStrategy, error) {
+		retxSuppression, err := createRetxSuppressionByParams(params)
+		if err != nil {
+			return nil, err
+		}
+		strategy := NewBestRouteStrategyV2()
+		strategy.SetForwarder(forwarder)
+		strategy.SetRetxSuppression(retxSuppression)
+		return strategy, nil
+	})
+	// v=2 改变了收到 Nack 之后的行为，需要显式指定版本号才会使用，/strategy/best-route 依然是 v=1
+	_ = SetDefaultStrategyVersion(BestRouteStrategyName, 1)
+
+	_ = RegisterStrategy(MulticastStrategyName, 1, func(forwarder *Forwarder, params []string) (table.IStrategy, error) {
+		if err := rejectStrategyParams(params); err != nil {
+			return nil, err
+		}
+		strategy := new(MulticastStrategy)
+		strategy.SetForwarder(forwarder)
+		return strategy, nil
+	})
+
+	// 支持的参数：probing-interval=<ms>、max-timeouts=<n>
+	_ = RegisterStrategy(AsfStrategyName, 1, func(forwarder *Forwarder, params []string) (table.IStrategy, error) {
+		kvs, err := ParseStrategyParams(params)
+		if err != nil {
+			return nil, err
+		}
+		strategy := NewAsfStrategy()
+		strategy.SetForwarder(forwarder)
+		for k, v := range kvs {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return nil, createStrategyRegistryErrorByType(InvalidStrategyParameterError, k+"="+v)
+			}
+			switch k {
+			case "probing-interval":
+				strategy.SetProbingInterval(n)
+			case "max-timeouts":
+				strategy.SetMaxTimeouts(n)
+			default:
+				return nil, createStrategyRegistryErrorByType(InvalidStrategyParameterError, k+"="+v)
+			}
+		}
+		return strategy, nil
+	})
+
+	// 支持的参数：<LogicFaceId>=<weight>，例如 /strategy/load-balance/v=1/3=2/4=1
+	_ = RegisterStrategy(LoadBalanceStrategyName, 1, func(forwarder *Forwarder, params []string) (table.IStrategy, error) {
+		kvs, err := ParseStrategyParams(params)
+		if err != nil {
+			return nil, err
+		}
+		strategy := NewLoadBalanceStrategy()
+		strategy.SetForwarder(forwarder)
+		for k, v := range kvs {
+			logicFaceId, err1 := strconv.ParseUint(k, 10, 64)
+			weight, err2 := strconv.ParseFloat(v, 64)
+			if err1 != nil || err2 != nil || weight <= 0 {
+				return nil, createStrategyRegistryErrorByType(InvalidStrategyParameterError, k+"="+v)
+			}
+			strategy.SetWeight(logicFaceId, weight)
+		}
+		return strategy, nil
+	})
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+///// 错误处理
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	InvalidStrategyNameError = iota
+	InvalidStrategyParameterError
+	UnknownStrategyError
+	UnknownStrategyVersionError
+	StrategyAlreadyRegisteredError
+)
+
+type StrategyRegistryError struct {
+	msg string
+}
+
+func (s StrategyRegistryError) Error() string {
+	return fmt.Sprintf("StrategyRegistryError: %s", s.msg)
+}
+
+func createStrategyRegistryErrorByType(errorType int, detail string) (err StrategyRegistryError) {
+	switch errorType {
+	case InvalidStrategyNameError:
+		err.msg = "invalid strategy name: " + detail
+	case InvalidStrategyParameterError:
+		err.msg = "invalid strategy parameter: " + detail
+	case UnknownStrategyError:
+		err.msg = "unknown strategy: " + detail
+	case UnknownStrategyVersionError:
+		err.msg = "unknown strategy version: " + detail
+	case StrategyAlreadyRegisteredError:
+		err.msg = "strategy already registered: " + detail
+	default:
+		err.msg = "Unknown error"
+	}
+	return
+}
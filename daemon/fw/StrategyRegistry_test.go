@@ -0,0 +1,89 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 3:45 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package fw
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStrategyRegistry_Create(t *testing.T) {
+	fmt.Println("registered", GetStrategyRegistry().List())
+
+	// 不指定版本号时使用默认版本
+	if _, name, err := CreateStrategy(nil, BestRouteStrategyName); err != nil || name != BestRouteStrategyName+"/v=1" {
+		t.Fatal("create best-route failed", name, err)
+	}
+	// 指定版本号时使用指定的版本
+	if strategy, name, err := CreateStrategy(nil, BestRouteStrategyName+"/v=2"); err != nil || name != BestRouteStrategyName+"/v=2" {
+		t.Fatal("create best-route v=2 failed", name, err)
+	} else if _, ok := strategy.(*BestRouteStrategyV2); !ok {
+		t.Fatal("best-route v=2 should be BestRouteStrategyV2")
+	}
+	// 默认版本必须是已经注册过的版本
+	if err := SetDefaultStrategyVersion(BestRouteStrategyName, 100); err == nil {
+		t.Fatal("should reject unregistered default version")
+	}
+
+	// 带参数
+	strategy, name, err := CreateStrategy(nil, AsfStrategyName+"/v=1/probing-interval=30000")
+	if err != nil || name != AsfStrategyName+"/v=1/probing-interval=30000" {
+		t.Fatal("create asf failed", name, err)
+	}
+	if asf, ok := strategy.(*AsfStrategy); !ok || asf.probingInterval != 30000 {
+		t.Fatal("asf parameter not applied")
+	}
+	strategy, _, err = CreateStrategy(nil, BestRouteStrategyName+"/v=2/retx-initial=20/retx-max=500")
+	if err != nil {
+		t.Fatal("create best-route v=2 with retx parameters failed", err)
+	}
+	if retx := strategy.(*BestRouteStrategyV2).GetRetxSuppression(); retx.GetInitialInterval() != 20 ||
+		retx.GetMultiplier() != DefaultRetxSuppressionMultiplier || retx.GetMaxInterval() != 500 {
+		t.Fatal("best-route retx parameters not applied")
+	}
+	// 没有重传抑制参数时使用同一个默认参数的实例
+	strategy, _, _ = CreateStrategy(nil, BestRouteStrategyName+"/v=2")
+	if retx := strategy.(*BestRouteStrategyV2).GetRetxSuppression(); retx == nil ||
+		retx != strategy.(*BestRouteStrategyV2).GetRetxSuppression() ||
+		retx.GetInitialInterval() != DefaultRetxSuppressionInitialInterval {
+		t.Fatal("expect default retx suppression created once")
+	}
+
+	// 未知的策略名、版本号以及参数
+	for _, name := range []string{
+		"/strategy/unknown",
+		BestRouteStrategyName + "/v=100",
+		BestRouteStrategyName + "/v=1/x=1",
+		BestRouteStrategyName + "/v=2/retx-initial=0",
+		BestRouteStrategyName + "/v=2/retx-initial=300/retx-max=200",
+		AsfStrategyName + "/v=1/unknown=1",
+		"strategy/best-route",
+		"/strategy//best-route",
+	} {
+		if _, _, err := CreateStrategy(nil, name); err == nil {
+			t.Fatal("should reject", name)
+		} else {
+			fmt.Println(err)
+		}
+	}
+}
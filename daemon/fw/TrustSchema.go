@@ -0,0 +1,327 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 2:50 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package fw
+
+import (
+	"fmt"
+	"mir-go/daemon/common"
+	"strings"
+)
+
+const (
+	TrustSchemaActionAccept = "accept" // 没有规则匹配时接受，也是 DefaultAction 为空时的处理方式
+	TrustSchemaActionReject = "reject" // 没有规则匹配时丢弃
+
+	trustPatternAnyComponent  = "*"  // 匹配任意一个组件
+	trustPatternAnyComponents = "**" // 匹配剩余的任意多个组件（包括0个），只能作为最后一个组件
+)
+
+// trustPattern
+// 标识或者网络身份的模式，按组件匹配
+//
+// @Description:
+//  每个组件可以是：
+//   1. 普通字符串：只匹配相同的组件；
+//   2. * ：匹配任意一个组件；
+//   3. {name} ：匹配任意一个组件，并将其捕获为 name ，同一条规则中再次出现的 {name} 必须匹配相同的组件；
+//      不使用 <name> 是因为尖括号在 XML 配置文件中需要转义，直接写会导致配置文件解析失败；
+//   4. ** ：只能作为最后一个组件，匹配剩余的任意多个组件（包括0个）。
+//
+type trustPattern []string
+
+// trustRule
+// 解析之后的信任模式规则
+//
+// @Description:
+//
+type trustRule struct {
+	id         string          // 规则的名字
+	identifier trustPattern    // 网络包标识的模式
+	signers    []trustPattern  // 允许的签名者网络身份的模式
+	anchors    map[string]bool // 信任锚的 URI ，签名者的网络身份必须与之完全相同
+}
+
+// TrustSchema
+// 信任模式，规定哪些网络身份可以为哪些标识的网络包签名
+//
+// @Description:
+//  1. 签名验证只能证明签名是有效的，信任模式进一步检查生产者（签名区中的第一个签名）是否有权为该标识签名；
+//  2. 规则按照配置文件中的顺序匹配，网络包的标识匹配的第一条规则决定其签名者是否被允许：签名者的网络身份匹配任意一个
+//     Signer 模式（Signer 模式中可以引用 Identifier 模式捕获的组件），或者等于任意一个信任锚；
+//  3. 信任锚只和签名者的网络身份做完全相同的比较，不会沿着证书链向上查找，所以由信任锚签发了证书的其它网络身份并不会因此被允许，
+//     需要通过 Signer 模式显式的允许；
+//  4. 信任模式对所有类型的网络包（包括 Interest 以及证书数据包）都生效，没有规则匹配的网络包根据 DefaultAction 处理，
+//     DefaultAction 为空时接受，以免只为部分标识配置了规则时，其它 Interest 和证书数据包都被丢弃；
+//  5. 信任模式在转发器启动之前加载，之后只读，可以被多个验证协程并发使用。
+//
+type TrustSchema struct {
+	rules         []*trustRule // 规则列表
+	defaultAccept bool         // 没有规则匹配时是否接受
+}
+
+// CreateTrustSchema
+// 根据信任模式配置创建一个信任模式
+//
+// @Description:
+// @param config
+// @return *TrustSchema
+// @return error
+//
+func CreateTrustSchema(config *common.TrustSchemaConfig) (*TrustSchema, error) {
+	trustSchema := &TrustSchema{rules: make([]*trustRule, 0, len(config.Rules))}
+	switch strings.ToLower(strings.TrimSpace(config.DefaultAction)) {
+	case "", TrustSchemaActionAccept:
+		trustSchema.defaultAccept = true
+	case TrustSchemaActionReject:
+		trustSchema.defaultAccept = false
+	default:
+		return nil, createTrustSchemaErrorByType(InvalidTrustSchemaDefaultActionError, config.DefaultAction)
+	}
+
+	for i, ruleConfig := range config.Rules {
+		rule := &trustRule{id: strings.TrimSpace(ruleConfig.Id), anchors: make(map[string]bool)}
+		if rule.id == "" {
+			rule.id = fmt.Sprintf("rule-%d", i+1)
+		}
+		identifier, err := parseTrustPattern(ruleConfig.Identifier)
+		if err != nil {
+			return nil, err
+		}
+		rule.identifier = identifier
+		for _, signerConfig := range ruleConfig.Signers {
+			signer, err := parseTrustPattern(signerConfig)
+			if err != nil {
+				return nil, err
+			}
+			rule.signers = append(rule.signers, signer)
+		}
+		for _, anchor := range ruleConfig.Anchors {
+			rule.anchors[normalizeTrustUri(anchor)] = true
+		}
+		if len(rule.signers) == 0 && len(rule.anchors) == 0 {
+			return nil, createTrustSchemaErrorByType(EmptyTrustSchemaRuleError, rule.id)
+		}
+		trustSchema.rules = append(trustSchema.rules, rule)
+	}
+	return trustSchema, nil
+}
+
+// LoadTrustSchema
+// 从配置文件中加载信任模式
+//
+// @Description:
+// @param configPath	信任模式配置文件的路径
+// @return *TrustSchema
+// @return error
+//
+func LoadTrustSchema(configPath string) (*TrustSchema, error) {
+	config, err := common.ParseTrustSchemaConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return CreateTrustSchema(config)
+}
+
+// Size
+// 获取规则的条数
+//
+// @Description:
+// @receiver t
+// @return int
+//
+func (t *TrustSchema) Size() int {
+	return len(t.rules)
+}
+
+// Check
+// 检查 signer 是否有权为标识为 identifier 的网络包签名
+//
+// @Description:
+//  不被允许时返回的错误中包含丢包的原因
+// @receiver t
+// @param identifier		网络包标识的 URI
+// @param signer			签名者网络身份的 URI
+// @return error
+//
+func (t *TrustSchema) Check(identifier string, signer string) error {
+	identifierComponents := splitTrustUri(identifier)
+	signerComponents := splitTrustUri(signer)
+	for _, rule := range t.rules {
+		captures := make(map[string]string)
+		if !rule.identifier.match(identifierComponents, captures) {
+			continue
+		}
+		if rule.anchors[normalizeTrustUri(signer)] {
+			return nil
+		}
+		for _, signerPattern := range rule.signers {
+			// 每个 Signer 模式使用 Identifier 模式捕获结果的拷贝，避免不匹配的 Signer 模式污染捕获结果
+			signerCaptures := make(map[string]string, len(captures))
+			for name, value := range captures {
+				signerCaptures[name] = value
+			}
+			if signerPattern.match(signerComponents, signerCaptures) {
+				return nil
+			}
+		}
+		return createTrustSchemaErrorByType(TrustSchemaSignerNotAllowedError,
+			fmt.Sprintf("signer %s is not allowed to sign %s by rule %s", signer, identifier, rule.id))
+	}
+	if t.defaultAccept {
+		return nil
+	}
+	return createTrustSchemaErrorByType(TrustSchemaNoMatchedRuleError, identifier)
+}
+
+//
+// 判断 components 是否匹配本模式
+//
+// @Description:
+// @receiver p
+// @param components
+// @param captures		捕获的组件，会被修改
+// @return bool
+//
+func (p trustPattern) match(components []string, captures map[string]string) bool {
+	for i, patternComponent := range p {
+		if patternComponent == trustPatternAnyComponents {
+			return true
+		}
+		if i >= len(components) {
+			return false
+		}
+		switch {
+		case patternComponent == trustPatternAnyComponent:
+		case isTrustPatternCapture(patternComponent):
+			name := patternComponent[1 : len(patternComponent)-1]
+			if value, ok := captures[name]; ok {
+				if value != components[i] {
+					return false
+				}
+			} else {
+				captures[name] = components[i]
+			}
+		default:
+			if patternComponent != components[i] {
+				return false
+			}
+		}
+	}
+	return len(p) == len(components)
+}
+
+//
+// 解析一个模式
+//
+// @Description:
+// @param pattern
+// @return trustPattern
+// @return error
+//
+func parseTrustPattern(pattern string) (trustPattern, error) {
+	components := splitTrustUri(pattern)
+	for i, component := range components {
+		if component == trustPatternAnyComponents && i != len(components)-1 {
+			return nil, createTrustSchemaErrorByType(InvalidTrustPatternError, pattern)
+		}
+		if strings.ContainsAny(component, "{}") && !isTrustPatternCapture(component) {
+			return nil, createTrustSchemaErrorByType(InvalidTrustPatternError, pattern)
+		}
+	}
+	return components, nil
+}
+
+//
+// 判断模式中的一个组件是否为捕获组件 {name}
+//
+// @Description:
+// @param component
+// @return bool
+//
+func isTrustPatternCapture(component string) bool {
+	return len(component) > 2 && strings.HasPrefix(component, "{") && strings.HasSuffix(component, "}") &&
+		!strings.ContainsAny(component[1:len(component)-1], "{}")
+}
+
+//
+// 将 URI 拆分成组件
+//
+// @Description:
+// @param uri
+// @return []string
+//
+func splitTrustUri(uri string) []string {
+	uri = strings.Trim(strings.TrimSpace(uri), "/")
+	if uri == "" {
+		return []string{}
+	}
+	return strings.Split(uri, "/")
+}
+
+//
+// 将 URI 规范化，用于比较信任锚
+//
+// @Description:
+// @param uri
+// @return string
+//
+func normalizeTrustUri(uri string) string {
+	return "/" + strings.Join(splitTrustUri(uri), "/")
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+///// 错误处理
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	InvalidTrustSchemaDefaultActionError = iota
+	InvalidTrustPatternError
+	EmptyTrustSchemaRuleError
+	TrustSchemaSignerNotAllowedError
+	TrustSchemaNoMatchedRuleError
+)
+
+type TrustSchemaError struct {
+	msg string
+}
+
+func (t TrustSchemaError) Error() string {
+	return fmt.Sprintf("TrustSchemaError: %s", t.msg)
+}
+
+func createTrustSchemaErrorByType(errorType int, detail string) (err TrustSchemaError) {
+	switch errorType {
+	case InvalidTrustSchemaDefaultActionError:
+		err.msg = fmt.Sprintf("invalid default action: %s", detail)
+	case InvalidTrustPatternError:
+		err.msg = fmt.Sprintf("invalid pattern: %s", detail)
+	case EmptyTrustSchemaRuleError:
+		err.msg = fmt.Sprintf("rule %s has neither signer nor anchor", detail)
+	case TrustSchemaSignerNotAllowedError:
+		err.msg = detail
+	case TrustSchemaNoMatchedRuleError:
+		err.msg = fmt.Sprintf("no rule matches %s", detail)
+	default:
+		err.msg = "Unknown error"
+	}
+	return
+}
@@ -0,0 +1,114 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package fw
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 3:20 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package fw
+
+import (
+	"fmt"
+	"mir-go/daemon/common"
+	"testing"
+)
+
+func TestTrustSchema_Check(t *testing.T) {
+	trustSchema, err := CreateTrustSchema(&common.TrustSchemaConfig{
+		DefaultAction: TrustSchemaActionReject,
+		Rules: []common.TrustSchemaRule{
+			{
+				Id:         "pku-user",
+				Identifier: "/min/pku/{user}/**",
+				Signers:    []string{"/min/pku/{user}"},
+				Anchors:    []string{"/min/pku"},
+			},
+			{
+				Id:         "any-video",
+				Identifier: "/min/*/video",
+				Signers:    []string{"/min/video-server/**"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		identifier string
+		signer     string
+		allowed    bool
+	}{
+		{"/min/pku/alice/file/1", "/min/pku/alice", true},
+		{"/min/pku/alice", "/min/pku/alice", true},
+		{"/min/pku/alice/file/1", "/min/pku/bob", false},
+		{"/min/pku/alice/file/1", "/min/pku", true},
+		// 信任锚只做完全相同的比较，不会沿着证书链查找
+		{"/min/pku/alice/file/1", "/min/pku/admin", false},
+		{"/min/tsinghua/video", "/min/video-server/node1", true},
+		{"/min/tsinghua/video/1", "/min/video-server/node1", false},
+		{"/min/tsinghua/video", "/min/pku/alice", false},
+	}
+	for _, c := range cases {
+		err := trustSchema.Check(c.identifier, c.signer)
+		if (err == nil) != c.allowed {
+			t.Fatal("check", c.identifier, "signed by", c.signer, "expect allowed =", c.allowed, "got", err)
+		}
+		fmt.Println(c.identifier, c.signer, err)
+	}
+
+	// 没有规则匹配时根据 DefaultAction 处理，为空时接受
+	trustSchema, _ = CreateTrustSchema(&common.TrustSchemaConfig{})
+	if err := trustSchema.Check("/min/other", "/min/other"); err != nil {
+		t.Fatal("expect accepted by default action, got", err)
+	}
+}
+
+func TestTrustSchema_Invalid(t *testing.T) {
+	invalidConfigs := []*common.TrustSchemaConfig{
+		{DefaultAction: "drop"},
+		{Rules: []common.TrustSchemaRule{{Identifier: "/min/**/a", Signers: []string{"/min"}}}},
+		{Rules: []common.TrustSchemaRule{{Identifier: "/min/{user", Signers: []string{"/min"}}}},
+		{Rules: []common.TrustSchemaRule{{Identifier: "/min/**"}}},
+	}
+	for _, config := range invalidConfigs {
+		if _, err := CreateTrustSchema(config); err == nil {
+			t.Fatal("expect error for", config)
+		} else {
+			fmt.Println(err)
+		}
+	}
+}
+
+func TestLoadTrustSchema(t *testing.T) {
+	trustSchema, err := LoadTrustSchema("../../trustSchema.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trustSchema.Check("/min-mir/mgmt/localhost/fib/add", "/localhost/mir"); err != nil {
+		t.Fatal(err)
+	}
+	// 示例配置中使用捕获组件的规则
+	if err := trustSchema.Check("/min/pku/alice/file/1", "/min/pku/alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := trustSchema.Check("/min/pku/alice/file/1", "/min/pku/bob"); err == nil {
+		t.Fatal("expect /min/pku/bob not to be allowed to sign /min/pku/alice/file/1")
+	}
+	fmt.Println(trustSchema.Size())
+}
@@ -26,6 +26,7 @@ import (
 	"minlib/utils"
 	utils2 "mir-go/daemon/utils"
 	"net"
+	"sync/atomic"
 	"time"
 )
 
@@ -57,6 +58,7 @@ type EthernetListener struct {
 	mInterfaceListeners InterfaceListenerMap   // 用于保存，已经打开了的网卡的信息，以及相应的logicFace号
 	badDev              utils.ThreadFreeIntMap // 用于保存无法启动的网卡名
 	receiveRoutineNum   int
+	closed              uint32 // 监听器是否已经被关闭，0 表示没有关闭，需要使用 atomic 读写
 }
 
 // Init
@@ -137,7 +139,7 @@ func (e *EthernetListener) CreateInterfaceListener(ifName string, macAddr net.Ha
 // @receiver e
 //
 func (e *EthernetListener) monitorDev() {
-	for true {
+	for atomic.LoadUint32(&e.closed) == 0 {
 		interfaces, err := net.Interfaces()
 		if err != nil {
 			common2.LogFatal(err)
@@ -149,6 +151,20 @@ func (e *EthernetListener) monitorDev() {
 	}
 }
 
+// Close
+// @Description: 	关闭监听器，停止扫描网卡状态，并关闭所有的网卡监听器以及与之相关的 logicFace
+// @receiver e
+//
+func (e *EthernetListener) Close() {
+	if !atomic.CompareAndSwapUint32(&e.closed, 0, 1) {
+		return
+	}
+	e.mInterfaceListeners.Range(func(key, value interface{}) bool {
+		e.closeInterfaceListener(value.(*InterfaceListener))
+		return true
+	})
+}
+
 // DeleteLogicFace
 // @Description: 	删除一个logicFace
 // @receiver e
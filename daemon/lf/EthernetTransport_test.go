@@ -52,7 +52,7 @@ var macRoutineNum = flag.Int("routineNum", 1, "number of routine to send UDP int
 func TestEthernetTransport_Send(t *testing.T) {
 	var faceSystem lf.LogicFaceSystem
 	var packetValidator fw.PacketValidator
-	blockQueue := utils.NewBlockQueue(10)
+	blockQueue := make(chan *lf.IncomingPacketData, 10)
 	packetValidator.Init(100, false, blockQueue)
 	var mir common.MIRConfig
 	mir.Init()
@@ -153,8 +153,8 @@ func EtherTransportSend(faceSystem *lf.LogicFaceSystem, payloadSize int, volume
 func TestEtherTransport_Speed(t *testing.T) {
 	var faceSystem lf.LogicFaceSystem
 	var packetValidator fw.PacketValidator
-	blockQueue := utils.BlockQueue{}
-	packetValidator.Init(100, true, &blockQueue)
+	blockQueue := make(chan *lf.IncomingPacketData, 10)
+	packetValidator.Init(100, true, blockQueue)
 	var mir common.MIRConfig
 	mir.Init()
 	faceSystem.Init(&packetValidator, &mir)
@@ -200,8 +200,8 @@ func EtherTransportSendAndSign(faceSystem *lf.LogicFaceSystem, payloadSize int,
 func TestEtherTransport_SpeedAndSign(t *testing.T) {
 	var faceSystem lf.LogicFaceSystem
 	var packetValidator fw.PacketValidator
-	blockQueue := utils.BlockQueue{}
-	packetValidator.Init(100, true, &blockQueue)
+	blockQueue := make(chan *lf.IncomingPacketData, 10)
+	packetValidator.Init(100, true, blockQueue)
 	var mir common.MIRConfig
 	mir.Init()
 	faceSystem.Init(&packetValidator, &mir)
@@ -129,6 +129,7 @@ func (l *LinkService) ReceivePacket(lpPacket *packet.LpPacket) {
 			l.logicFace.refreshExpireTime()
 			return
 		}
+		l.logicFace.logicFaceCounters.AddInBytes(uint64(len(lpPacket.GetValue())))
 		minPacket, err := getMINPacketFromLpPacket(lpPacket)
 		if err != nil {
 			common2.LogWarn(err)
@@ -137,6 +138,7 @@ func (l *LinkService) ReceivePacket(lpPacket *packet.LpPacket) {
 		l.logicFace.ReceivePacket(minPacket)
 		return
 	}
+	l.logicFace.logicFaceCounters.AddInBytes(uint64(len(lpPacket.GetValue())))
 	reassembleLpPacket := l.lpReassemble.ReceiveFragment(l.transport.GetRemoteUri(), lpPacket)
 	if reassembleLpPacket == nil {
 		return
@@ -175,6 +177,7 @@ func (l *LinkService) sendFragment(buf []byte, bufLen int, fragmentId, fragmentN
 //
 func (l *LinkService) sendByteBuffer(buf []byte, bufLen int) {
 	common2.LogDebug("send to face : ", l.logicFace.LogicFaceId, " ", l.logicFace.GetRemoteUri())
+	l.logicFace.logicFaceCounters.AddOutBytes(uint64(bufLen))
 	fragmentLen := l.mtu - l.lpPacketHeadSize - 10
 	startIdx := 0
 	fragmentSeq := 0
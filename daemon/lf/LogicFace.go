@@ -27,14 +27,12 @@ import (
 	"minlib/packet"
 	"minlib/utils"
 	utils2 "mir-go/daemon/utils"
-	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type LogicFaceType uint32
 
-var lock sync.Mutex
-
 //
 // @Description:  LogicFace的类型
 //
@@ -51,6 +49,9 @@ const (
 //
 var logicFaceMaxIdolTimeMs int64 = 600000
 
+// flushCheckInterval Flush 检查发送队列是否已经发送完成的间隔
+const flushCheckInterval = 10 * time.Millisecond
+
 // LogicFaceMap 一个线程安全的，用于存储 LogicFace 的 map 实现
 //
 // @Description:
@@ -105,8 +106,10 @@ type LogicFace struct {
 	//	非 0 时表示有持久性，就算一直没有收发数据，也不会被清理
 	onShutdownCallback func(logicFaceId uint64) // 传输logic face 关闭时的回调
 
-	sendQue chan encoding.IEncodingAble
-	recvQue chan *packet.MINPacket
+	sendQue        chan encoding.IEncodingAble
+	recvQue        chan *packet.MINPacket
+	unsentNum      int64 // 已经放入发送队列但是还没有发送完成的包的个数，需要使用 atomic 读写
+	receiveStopped int32 // 非 0 表示已经停止接收，之后收到的包直接丢弃，需要使用 atomic 读写
 }
 
 // GetState 获取接口状态
@@ -168,18 +171,22 @@ var send2ChanException = func() {
 //
 func (lf *LogicFace) ReceivePacket(minPacket *packet.MINPacket) {
 	defer send2ChanException()
-	if !lf.state {
+	if !lf.state || atomic.LoadInt32(&lf.receiveStopped) != 0 {
 		return
 	}
 	if len(lf.recvQue) < cap(lf.recvQue) {
 		lf.recvQue <- minPacket
 	} else {
 		common2.LogError("receive que full, ", lf.GetLocalUri(), lf.GetRemoteUri())
+		if packetType, ok := GetMINPacketCounterType(minPacket); ok {
+			lf.logicFaceCounters.IncreaseDrop(packetType)
+		}
 	}
 }
 
 //
 // @Description:	由接收协程调用，把接收队列中的包往forwarder的缓冲区中送
+//		流入计数由转发器在识别出网络包的具体类型之后统计（在不解码的情况下无法区分兴趣包和 Nack）
 // @receiver lf
 // @param minPacket
 //
@@ -190,21 +197,6 @@ func (lf *LogicFace) onReceivePacket(minPacket *packet.MINPacket) {
 		LogicFace: lf,
 		MinPacket: minPacket,
 	})
-	identifier, err := minPacket.GetIdentifier(0)
-	if err != nil {
-		common2.LogWarn(err, "face ", lf.LogicFaceId, " receive packet has no identifier")
-		return
-	}
-	if identifier.GetIdentifierType() == encoding.TlvIdentifierCommon {
-		lf.logicFaceCounters.InGPPktN++
-	} else if identifier.GetIdentifierType() == encoding.TlvIdentifierContentInterest {
-		lock.Lock()
-		lf.logicFaceCounters.InInterestN++
-		lock.Unlock()
-		//common2.LogInfo(lf.logicFaceCounters.InInterestN)
-	} else if identifier.GetIdentifierType() == encoding.TlvIdentifierContentData {
-		lf.logicFaceCounters.InDataN++
-	}
 
 	// 更新过期时间
 	lf.refreshExpireTime()
@@ -241,6 +233,7 @@ func (lf *LogicFace) Start() {
 				break
 			}
 			lf.linkService.SendEncodingAble(minPacket)
+			atomic.AddInt64(&lf.unsentNum, -1)
 		}
 	})
 
@@ -264,6 +257,7 @@ func (lf *LogicFace) Start() {
 					heatBeatPkt.SetHeartBeat(true)
 					common2.LogDebug("Send heart Beat")
 					// 将心跳包加到发送队列当中
+					atomic.AddInt64(&lf.unsentNum, 1)
 					lf.sendQue <- heatBeatPkt
 				}
 			}
@@ -279,13 +273,60 @@ func (lf *LogicFace) refreshExpireTime() {
 	lf.expireTime = getTimestampMS() + logicFaceMaxIdolTimeMs
 }
 
-func (lf *LogicFace) addPkt2SendQue(pkt encoding.IEncodingAble) {
+//
+// @Description: 将一个包放入发送队列
+// @receiver lf
+// @param pkt
+// @return sent		接口已关闭或者发送队列已满时返回 false
+//
+func (lf *LogicFace) addPkt2SendQue(pkt encoding.IEncodingAble) (sent bool) {
 	defer send2ChanException()
 	if !lf.state {
-		return
+		return false
 	}
 	if len(lf.sendQue) < cap(lf.sendQue) {
+		atomic.AddInt64(&lf.unsentNum, 1)
 		lf.sendQue <- pkt
+		return true
+	}
+	return false
+}
+
+// StopReceive
+// @Description: 停止接收，之后 transport 收到的包直接丢弃，接收队列中已有的包依然会交给转发器处理；发送不受影响
+// @receiver lf
+//
+func (lf *LogicFace) StopReceive() {
+	atomic.StoreInt32(&lf.receiveStopped, 1)
+}
+
+// Flush
+// @Description: 等待发送队列中的包都发送完成，最多等待到 deadline
+// @receiver lf
+// @param deadline
+// @return bool		在 deadline 之前发送完成，或者接口已经关闭时返回 true
+//
+func (lf *LogicFace) Flush(deadline time.Time) bool {
+	for lf.state && atomic.LoadInt64(&lf.unsentNum) > 0 {
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		time.Sleep(flushCheckInterval)
+	}
+	return true
+}
+
+//
+// @Description: 将一个包放入发送队列，并更新流出或者丢包计数
+// @receiver lf
+// @param pkt
+// @param packetType	网络包类型
+//
+func (lf *LogicFace) sendAndCount(pkt encoding.IEncodingAble, packetType CounterPacketType) {
+	if lf.addPkt2SendQue(pkt) {
+		lf.logicFaceCounters.IncreaseOut(packetType)
+	} else {
+		lf.logicFaceCounters.IncreaseDrop(packetType)
 	}
 }
 
@@ -295,7 +336,12 @@ func (lf *LogicFace) addPkt2SendQue(pkt encoding.IEncodingAble) {
 // @param packet
 //
 func (lf *LogicFace) SendMINPacket(packet *packet.MINPacket) {
-	lf.addPkt2SendQue(packet)
+	packetType, ok := GetMINPacketCounterType(packet)
+	if !ok {
+		lf.addPkt2SendQue(packet)
+		return
+	}
+	lf.sendAndCount(packet, packetType)
 }
 
 // SendInterest
@@ -304,7 +350,7 @@ func (lf *LogicFace) SendMINPacket(packet *packet.MINPacket) {
 // @param interest
 //
 func (lf *LogicFace) SendInterest(interest *packet.Interest) {
-	lf.addPkt2SendQue(interest)
+	lf.sendAndCount(interest, CounterPacketTypeInterest)
 }
 
 // SendData
@@ -313,7 +359,7 @@ func (lf *LogicFace) SendInterest(interest *packet.Interest) {
 // @param data
 //
 func (lf *LogicFace) SendData(data *packet.Data) {
-	lf.addPkt2SendQue(data)
+	lf.sendAndCount(data, CounterPacketTypeData)
 }
 
 // SendNack
@@ -322,7 +368,7 @@ func (lf *LogicFace) SendData(data *packet.Data) {
 // @param nack
 //
 func (lf *LogicFace) SendNack(nack *packet.Nack) {
-	lf.addPkt2SendQue(nack)
+	lf.sendAndCount(nack, CounterPacketTypeNack)
 }
 
 // SendGPPkt
@@ -331,7 +377,7 @@ func (lf *LogicFace) SendNack(nack *packet.Nack) {
 // @param gPPkt
 //
 func (lf *LogicFace) SendGPPkt(gPPkt *packet.GPPkt) {
-	lf.addPkt2SendQue(gPPkt)
+	lf.sendAndCount(gPPkt, CounterPacketTypeGPPkt)
 }
 
 // GetLocalUri
@@ -352,6 +398,15 @@ func (lf *LogicFace) GetRemoteUri() string {
 	return lf.transport.GetRemoteUri()
 }
 
+// GetLogicFaceType
+// @Description: 获得 logicFace 的类型
+// @receiver lf
+// @return LogicFaceType
+//
+func (lf *LogicFace) GetLogicFaceType() LogicFaceType {
+	return lf.logicFaceType
+}
+
 // Shutdown
 // @Description: 关闭face
 // @receiver lf
@@ -371,7 +426,16 @@ func (lf *LogicFace) Shutdown() {
 }
 
 func (lf *LogicFace) GetCounter() uint64 {
-	return lf.logicFaceCounters.InInterestN
+	return atomic.LoadUint64(&lf.logicFaceCounters.InInterestN)
+}
+
+// GetCounters
+// @Description: 获取logicFace的流量统计对象，所有计数都通过原子操作更新，读取时请使用 Snapshot
+// @receiver lf
+// @return *LogicFaceCounters
+//
+func (lf *LogicFace) GetCounters() *LogicFaceCounters {
+	return &lf.logicFaceCounters
 }
 
 // SetPersistence
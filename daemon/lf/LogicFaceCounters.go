@@ -21,22 +21,161 @@
 //
 package lf
 
+import (
+	"minlib/encoding"
+	"minlib/packet"
+	"sync/atomic"
+)
+
+// CounterPacketType 流量统计中区分的网络包类型
+type CounterPacketType int
+
+const (
+	CounterPacketTypeInterest CounterPacketType = iota // 兴趣包
+	CounterPacketTypeData                              // 数据包
+	CounterPacketTypeNack                              // Nack
+	CounterPacketTypeGPPkt                             // 普通推式包
+)
+
 // LogicFaceCounters
 // @Description: 统计信息对象，其关键成员有以下几个,用于统计一个logicFace的流量信息
+//		1. 所有计数都通过原子操作更新，读取时应该使用 Snapshot 获取一个一致的拷贝；
+//		2. In 计数在网络包进入转发管道、被识别出具体类型时统计；Out 计数在网络包被放入发送队列时统计；
+//		3. Drop 计数包括流入后被丢弃（接收队列已满、签名验证失败、转发管道丢弃）以及因为发送队列已满或者接口已关闭而无法发出的包；
+//		4. 字节数统计的是网络层的字节数，不包括链路层的 LpPacket 头部和心跳包。
 //
 type LogicFaceCounters struct {
 	InGPPktN      uint64 // 从本接口流入的普通推式包的个数
 	OutGPPktN     uint64 // 从本接口流出的普通推式包的个数
-	DropGPPktN    uint64 // 从本接口流入后被丢弃的普通推式包的个数
+	DropGPPktN    uint64 // 在本接口上被丢弃的普通推式包的个数
 	InInterestN   uint64 // 从本接口流入的兴趣包的个数
 	OutInterestN  uint64 // 从本接口流出的兴趣包的个数
-	DropInterestN uint64 // 从本接口流入后被丢弃的兴趣包的个数
+	DropInterestN uint64 // 在本接口上被丢弃的兴趣包的个数
 	InDataN       uint64 // 从本接口流入的数据包的个数
 	OutDataN      uint64 // 从本接口流出的数据包的个数
-	DropDataN     uint64 // 从本接口流入后被丢弃的数据包的个数
+	DropDataN     uint64 // 在本接口上被丢弃的数据包的个数
 	InNackN       uint64 // 从本接口流入的Nack包的个数
 	OutNackN      uint64 // 从本接口流出的Nack包的个数
-	DropNackN     uint64 // 从本接口流入后被丢弃的Nack包的个数
+	DropNackN     uint64 // 在本接口上被丢弃的Nack包的个数
 	InBytesN      uint64 // 从本接口流入的数据字节数
 	OutBytesN     uint64 // 从本接口流出的数据字节数
 }
+
+// IncreaseIn
+// @Description: 	流入计数加一
+// @receiver c
+// @param packetType	网络包类型
+//
+func (c *LogicFaceCounters) IncreaseIn(packetType CounterPacketType) {
+	switch packetType {
+	case CounterPacketTypeInterest:
+		atomic.AddUint64(&c.InInterestN, 1)
+	case CounterPacketTypeData:
+		atomic.AddUint64(&c.InDataN, 1)
+	case CounterPacketTypeNack:
+		atomic.AddUint64(&c.InNackN, 1)
+	case CounterPacketTypeGPPkt:
+		atomic.AddUint64(&c.InGPPktN, 1)
+	}
+}
+
+// IncreaseOut
+// @Description: 	流出计数加一
+// @receiver c
+// @param packetType	网络包类型
+//
+func (c *LogicFaceCounters) IncreaseOut(packetType CounterPacketType) {
+	switch packetType {
+	case CounterPacketTypeInterest:
+		atomic.AddUint64(&c.OutInterestN, 1)
+	case CounterPacketTypeData:
+		atomic.AddUint64(&c.OutDataN, 1)
+	case CounterPacketTypeNack:
+		atomic.AddUint64(&c.OutNackN, 1)
+	case CounterPacketTypeGPPkt:
+		atomic.AddUint64(&c.OutGPPktN, 1)
+	}
+}
+
+// IncreaseDrop
+// @Description: 	丢包计数加一
+// @receiver c
+// @param packetType	网络包类型
+//
+func (c *LogicFaceCounters) IncreaseDrop(packetType CounterPacketType) {
+	switch packetType {
+	case CounterPacketTypeInterest:
+		atomic.AddUint64(&c.DropInterestN, 1)
+	case CounterPacketTypeData:
+		atomic.AddUint64(&c.DropDataN, 1)
+	case CounterPacketTypeNack:
+		atomic.AddUint64(&c.DropNackN, 1)
+	case CounterPacketTypeGPPkt:
+		atomic.AddUint64(&c.DropGPPktN, 1)
+	}
+}
+
+// AddInBytes
+// @Description: 	增加流入的字节数
+// @receiver c
+// @param n
+//
+func (c *LogicFaceCounters) AddInBytes(n uint64) {
+	atomic.AddUint64(&c.InBytesN, n)
+}
+
+// AddOutBytes
+// @Description: 	增加流出的字节数
+// @receiver c
+// @param n
+//
+func (c *LogicFaceCounters) AddOutBytes(n uint64) {
+	atomic.AddUint64(&c.OutBytesN, n)
+}
+
+// Snapshot
+// @Description: 	获取当前所有计数的一个拷贝
+// @receiver c
+// @return LogicFaceCounters
+//
+func (c *LogicFaceCounters) Snapshot() LogicFaceCounters {
+	return LogicFaceCounters{
+		InGPPktN:      atomic.LoadUint64(&c.InGPPktN),
+		OutGPPktN:     atomic.LoadUint64(&c.OutGPPktN),
+		DropGPPktN:    atomic.LoadUint64(&c.DropGPPktN),
+		InInterestN:   atomic.LoadUint64(&c.InInterestN),
+		OutInterestN:  atomic.LoadUint64(&c.OutInterestN),
+		DropInterestN: atomic.LoadUint64(&c.DropInterestN),
+		InDataN:       atomic.LoadUint64(&c.InDataN),
+		OutDataN:      atomic.LoadUint64(&c.OutDataN),
+		DropDataN:     atomic.LoadUint64(&c.DropDataN),
+		InNackN:       atomic.LoadUint64(&c.InNackN),
+		OutNackN:      atomic.LoadUint64(&c.OutNackN),
+		DropNackN:     atomic.LoadUint64(&c.DropNackN),
+		InBytesN:      atomic.LoadUint64(&c.InBytesN),
+		OutBytesN:     atomic.LoadUint64(&c.OutBytesN),
+	}
+}
+
+// GetMINPacketCounterType
+// @Description: 	根据 MINPacket 第一个标识的类型判断其在流量统计中的类型
+//		在不解码的情况下无法区分兴趣包和 Nack ，所以标识类型为内容兴趣标识的包都被当做兴趣包统计
+// @param minPacket
+// @return CounterPacketType
+// @return bool		无法识别时返回 false
+//
+func GetMINPacketCounterType(minPacket *packet.MINPacket) (CounterPacketType, bool) {
+	identifier, err := minPacket.GetIdentifier(0)
+	if err != nil {
+		return 0, false
+	}
+	switch identifier.GetIdentifierType() {
+	case encoding.TlvIdentifierCommon:
+		return CounterPacketTypeGPPkt, true
+	case encoding.TlvIdentifierContentInterest:
+		return CounterPacketTypeInterest, true
+	case encoding.TlvIdentifierContentData:
+		return CounterPacketTypeData, true
+	}
+	return 0, false
+}
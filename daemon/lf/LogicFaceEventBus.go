@@ -0,0 +1,117 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package lf
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 11:00 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package lf
+
+import (
+	"sort"
+	"sync"
+)
+
+// LogicFaceEventType LogicFace 生命周期事件的类型
+type LogicFaceEventType int
+
+const (
+	LogicFaceEventCreated   LogicFaceEventType = iota // LogicFace 被添加到 LogicFaceTable 中
+	LogicFaceEventDestroyed                           // LogicFace 被关闭
+)
+
+// LogicFaceEvent
+// @Description: LogicFace 生命周期事件
+//
+type LogicFaceEvent struct {
+	Type      LogicFaceEventType // 事件类型
+	LogicFace *LogicFace         // 触发事件的 LogicFace
+}
+
+// LogicFaceEventHandler LogicFace 生命周期事件的处理函数
+type LogicFaceEventHandler func(event *LogicFaceEvent)
+
+// LogicFaceEventBus
+// @Description: LogicFace 生命周期事件总线，用于将 LogicFace 的创建和销毁通知给其它模块（转发器、策略等）
+//		1. 事件在发布者的协程中同步地依次调用所有订阅者，订阅者如果需要访问只能在特定协程中访问的状态，应该自行将处理逻辑投递到对应的协程；
+//		2. 订阅者的处理函数中不能再调用 Subscribe 或者 Unsubscribe。
+//
+type LogicFaceEventBus struct {
+	handlers map[uint64]LogicFaceEventHandler // 订阅编号 => 处理函数
+	lastId   uint64                           // 最后分配的订阅编号
+	lock     sync.RWMutex
+}
+
+// CreateLogicFaceEventBus
+// @Description: 创建一个 LogicFace 生命周期事件总线
+// @return *LogicFaceEventBus
+//
+func CreateLogicFaceEventBus() *LogicFaceEventBus {
+	return &LogicFaceEventBus{
+		handlers: make(map[uint64]LogicFaceEventHandler),
+	}
+}
+
+// Subscribe
+// @Description: 订阅 LogicFace 生命周期事件
+// @receiver b
+// @param handler
+// @return uint64	订阅编号，用于取消订阅
+//
+func (b *LogicFaceEventBus) Subscribe(handler LogicFaceEventHandler) uint64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.lastId++
+	b.handlers[b.lastId] = handler
+	return b.lastId
+}
+
+// Unsubscribe
+// @Description: 取消订阅
+// @receiver b
+// @param subscriptionId	Subscribe 返回的订阅编号
+//
+func (b *LogicFaceEventBus) Unsubscribe(subscriptionId uint64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.handlers, subscriptionId)
+}
+
+// Publish
+// @Description: 发布一个事件，按照订阅的先后顺序依次调用所有订阅者
+// @receiver b
+// @param event
+//
+func (b *LogicFaceEventBus) Publish(event *LogicFaceEvent) {
+	b.lock.RLock()
+	ids := make([]uint64, 0, len(b.handlers))
+	for id := range b.handlers {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	handlers := make([]LogicFaceEventHandler, 0, len(ids))
+	for _, id := range ids {
+		handlers = append(handlers, b.handlers[id])
+	}
+	b.lock.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
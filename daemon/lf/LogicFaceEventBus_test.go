@@ -0,0 +1,50 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package lf
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 11:00 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package lf
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLogicFaceEventBus(t *testing.T) {
+	bus := CreateLogicFaceEventBus()
+	var received []string
+	first := bus.Subscribe(func(event *LogicFaceEvent) {
+		received = append(received, fmt.Sprint("first:", event.Type, ":", event.LogicFace.LogicFaceId))
+	})
+	bus.Subscribe(func(event *LogicFaceEvent) {
+		received = append(received, fmt.Sprint("second:", event.Type, ":", event.LogicFace.LogicFaceId))
+	})
+
+	face := &LogicFace{LogicFaceId: 3}
+	bus.Publish(&LogicFaceEvent{Type: LogicFaceEventCreated, LogicFace: face})
+	bus.Unsubscribe(first)
+	bus.Publish(&LogicFaceEvent{Type: LogicFaceEventDestroyed, LogicFace: face})
+
+	// 按订阅顺序调用，取消订阅之后不再收到事件
+	expected := []string{"first:0:3", "second:0:3", "second:1:3"}
+	if fmt.Sprint(received) != fmt.Sprint(expected) {
+		t.Fatal("unexpected events", received)
+	}
+	fmt.Println(received)
+}
@@ -94,6 +94,58 @@ func (l *LogicFaceSystem) Start() {
 	utils.GoroutineNoPanic(l.faceCleaner)
 }
 
+// StopListeners
+// @Description: 关闭所有类型的Face监听，不再接受新的 LogicFace
+// @receiver l
+//
+func (l *LogicFaceSystem) StopListeners() {
+	l.ethernetListener.Close()
+	if l.config.SupportTCP {
+		l.tcpListener.Close()
+	}
+	if l.config.SupportUDP {
+		l.udpListener.Close()
+	}
+	if l.config.SupportUnix {
+		l.unixListener.Close()
+	}
+}
+
+// StopReceiving
+// @Description: 让所有的 logicFace 停止接收，退出时在排空转发器的包队列之前调用，这样包队列中只剩下有限的已经收到的包
+// @receiver l
+//
+func (l *LogicFaceSystem) StopReceiving() {
+	for _, logicFace := range l.logicFaceTable.GetAllFaceList() {
+		logicFace.StopReceive()
+	}
+}
+
+// FlushAllFaces
+// @Description: 等待所有 logicFace 发送队列中的包都发送完成，最多等待 timeout
+// @receiver l
+// @param timeout
+//
+func (l *LogicFaceSystem) FlushAllFaces(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for _, logicFace := range l.logicFaceTable.GetAllFaceList() {
+		if !logicFace.Flush(deadline) {
+			common2.LogWarn("Flush logic face timeout, the remaining packets will be dropped, id = ", logicFace.LogicFaceId)
+		}
+	}
+}
+
+// ShutdownAllFaces
+// @Description: 关闭 logicFaceTable 中所有的 logicFace，并将其从表中删除
+// @receiver l
+//
+func (l *LogicFaceSystem) ShutdownAllFaces() {
+	for _, logicFace := range l.logicFaceTable.GetAllFaceList() {
+		logicFace.Shutdown()
+		l.logicFaceTable.RemoveByLogicFaceId(logicFace.LogicFaceId)
+	}
+}
+
 func (l *LogicFaceSystem) destroyFace(logicFaceId uint64, logicFace *LogicFace) {
 	if logicFace.logicFaceType == LogicFaceTypeUDP {
 		l.udpListener.DeleteLogicFace(logicFace.transport.GetRemoteAddr())
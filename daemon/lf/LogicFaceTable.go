@@ -34,6 +34,7 @@ type LogicFaceTable struct {
 	lastId          utils.ThreadFreeUint64 // 下一个分配的 LogicFace Id
 	version         utils.ThreadFreeUint64 // 版本
 	OnEvicted       func(uint64)
+	eventBus        *LogicFaceEventBus // LogicFace 生命周期事件总线
 }
 
 // Init 初始化 LogicFace Table
@@ -45,6 +46,7 @@ func (l *LogicFaceTable) Init() {
 	l.lastId.SetValue(0)
 	l.mSize.SetValue(0)
 	l.version.SetValue(0)
+	l.eventBus = CreateLogicFaceEventBus()
 }
 
 // GetVersion 获取版本号
@@ -57,6 +59,17 @@ func (l *LogicFaceTable) GetVersion() uint64 {
 	return l.version.GetValue()
 }
 
+// GetEventBus
+// @Description: 获取 LogicFace 生命周期事件总线
+//		1. LogicFace 被添加到表中时发布 LogicFaceEventCreated 事件；
+//		2. LogicFace 被关闭时发布 LogicFaceEventDestroyed 事件，此时 OnEvicted 回调已经执行完毕（FIB 中对应的下一跳已经被清除）。
+// @receiver l
+// @return *LogicFaceEventBus
+//
+func (l *LogicFaceTable) GetEventBus() *LogicFaceEventBus {
+	return l.eventBus
+}
+
 // Size
 // @Description: 获得当前表大小。
 // @receiver logicFaceTable
@@ -78,8 +91,12 @@ func (l *LogicFaceTable) AddLogicFace(logicFacePtr *LogicFace) uint64 {
 	l.mSize.AddAndGet(1)
 	l.version.AddAndGet(1)
 	logicFacePtr.SetOnShutdownCallback(func(logicFaceId uint64) {
-		l.OnEvicted(logicFaceId)
+		if l.OnEvicted != nil {
+			l.OnEvicted(logicFaceId)
+		}
+		l.eventBus.Publish(&LogicFaceEvent{Type: LogicFaceEventDestroyed, LogicFace: logicFacePtr})
 	})
+	l.eventBus.Publish(&LogicFaceEvent{Type: LogicFaceEventCreated, LogicFace: logicFacePtr})
 	return logicFacePtr.LogicFaceId
 }
 
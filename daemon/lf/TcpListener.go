@@ -27,6 +27,7 @@ import (
 	"mir-go/daemon/utils"
 	"net"
 	"strconv"
+	"sync/atomic"
 )
 
 // TcpListener
@@ -37,6 +38,7 @@ type TcpListener struct {
 	TcpPort  uint16       // TCP端口号
 	listener net.Listener // TCP监听句柄
 	config   *common.MIRConfig
+	closed   uint32 // 监听器是否已经被关闭，0 表示没有关闭，需要使用 atomic 读写
 }
 
 // Init
@@ -66,6 +68,10 @@ func (t *TcpListener) accept() {
 	for true {
 		newConnect, err := t.listener.Accept()
 		if err != nil {
+			if atomic.LoadUint32(&t.closed) == 1 {
+				// 监听器被主动关闭，正常退出
+				return
+			}
 			common2.LogFatal(err)
 		}
 		t.tryCreateTcpLogicFace(newConnect)
@@ -85,3 +91,16 @@ func (t *TcpListener) Start() {
 	t.listener = listener
 	utils.GoroutineNoPanic(t.accept)
 }
+
+// Close
+// @Description:  关闭监听器，不再接收新的TCP连接，已经建立的 LogicFace 不受影响
+// @receiver t
+//
+func (t *TcpListener) Close() {
+	if t.listener == nil || !atomic.CompareAndSwapUint32(&t.closed, 0, 1) {
+		return
+	}
+	if err := t.listener.Close(); err != nil {
+		common2.LogWarn(err)
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"mir-go/daemon/utils"
 	"net"
 	"strconv"
+	"sync/atomic"
 )
 
 // UdpPacket
@@ -58,6 +59,7 @@ type UdpListener struct {
 	recvBuf           []byte // 接收缓冲区，大小为  9000
 	receiveRoutineNum int
 	config            *common.MIRConfig
+	closed            uint32 // 监听器是否已经被关闭，0 表示没有关闭，需要使用 atomic 读写
 }
 
 func (u *UdpListener) Init(config *common.MIRConfig) {
@@ -164,7 +166,9 @@ func (u *UdpListener) doReceive() {
 		var udpPacket UdpPacket
 		packetLen, remoteAddr, err := u.conn.ReadFromUDP(udpPacket.recvBuf[:])
 		if err != nil {
-			common2.LogWarn(err)
+			if atomic.LoadUint32(&u.closed) == 0 {
+				common2.LogWarn(err)
+			}
 			break
 		}
 		udpPacket.remoteAddr = remoteAddr
@@ -173,6 +177,19 @@ func (u *UdpListener) doReceive() {
 	}
 }
 
+// Close
+// @Description:  关闭监听器，不再接收新的UDP对端，已经建立的 LogicFace 不受影响
+// @receiver u
+//
+func (u *UdpListener) Close() {
+	if u.conn == nil || !atomic.CompareAndSwapUint32(&u.closed, 0, 1) {
+		return
+	}
+	if err := u.conn.Close(); err != nil {
+		common2.LogWarn(err)
+	}
+}
+
 func (u *UdpListener) DeleteLogicFace(remoteAddr string) {
 	u.DeleteLogicFace(remoteAddr)
 }
@@ -50,8 +50,8 @@ import (
 func TestUdpTransport_Init(t *testing.T) {
 	var faceSystem lf.LogicFaceSystem
 	var packetValidator fw.PacketValidator
-	blockQueue := utils.BlockQueue{}
-	packetValidator.Init(100, true, &blockQueue)
+	blockQueue := make(chan *lf.IncomingPacketData, 10)
+	packetValidator.Init(100, true, blockQueue)
 	var mir common.MIRConfig
 	mir.Init()
 	faceSystem.Init(&packetValidator, &mir)
@@ -139,8 +139,8 @@ var udpRoutineNum = flag.Int("udpRoutineNum", 1, "number of routine to send UDP
 func TestUdpTransport_Speed(t *testing.T) {
 	var faceSystem lf.LogicFaceSystem
 	var packetValidator fw.PacketValidator
-	blockQueue := utils.BlockQueue{}
-	packetValidator.Init(100, true, &blockQueue)
+	blockQueue := make(chan *lf.IncomingPacketData, 10)
+	packetValidator.Init(100, true, blockQueue)
 	var mir common.MIRConfig
 	mir.Init()
 	faceSystem.Init(&packetValidator, &mir)
@@ -159,8 +159,8 @@ func TestUdpTransport_Speed(t *testing.T) {
 func TestUdpTransport_SpeedAndSign(t *testing.T) {
 	var faceSystem lf.LogicFaceSystem
 	var packetValidator fw.PacketValidator
-	blockQueue := utils.BlockQueue{}
-	packetValidator.Init(100, true, &blockQueue)
+	blockQueue := make(chan *lf.IncomingPacketData, 10)
+	packetValidator.Init(100, true, blockQueue)
 	var mir common.MIRConfig
 	mir.Init()
 	faceSystem.Init(&packetValidator, &mir)
@@ -179,7 +179,7 @@ func TestUdpTransport_SpeedAndSign(t *testing.T) {
 func TestUdpTransport_Receive(t *testing.T) {
 	var faceSystem lf.LogicFaceSystem
 	var packetValidator fw.PacketValidator
-	blockQueue := utils.NewBlockQueue(10)
+	blockQueue := make(chan *lf.IncomingPacketData, 10)
 	packetValidator.Init(1, false, blockQueue)
 	var mir common.MIRConfig
 	mir.Init()
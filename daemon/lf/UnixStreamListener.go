@@ -28,12 +28,14 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"sync/atomic"
 )
 
 type UnixStreamListener struct {
 	listener *net.UnixListener
 	filepath string
 	config   *common.MIRConfig
+	closed   uint32 // 监听器是否已经被关闭，0 表示没有关闭，需要使用 atomic 读写
 }
 
 func (u *UnixStreamListener) Init(config *common.MIRConfig) {
@@ -58,6 +60,10 @@ func (u *UnixStreamListener) accept() {
 	for true {
 		newConnect, err := u.listener.Accept()
 		if err != nil {
+			if atomic.LoadUint32(&u.closed) == 1 {
+				// 监听器被主动关闭，正常退出
+				return
+			}
 			common2.LogFatal(err)
 		}
 		u.createTcpLogicFace(newConnect)
@@ -92,3 +98,20 @@ func (u *UnixStreamListener) Start() {
 	u.listener = listener
 	utils.GoroutineNoPanic(u.accept)
 }
+
+// Close
+// @Description:  关闭监听器，不再接收新的unix连接，并删除unix socket文件
+// @receiver u
+//
+func (u *UnixStreamListener) Close() {
+	if u.listener == nil || !atomic.CompareAndSwapUint32(&u.closed, 0, 1) {
+		return
+	}
+	if err := u.listener.Close(); err != nil {
+		common2.LogWarn(err)
+	}
+	// 关闭 listener 时一般会自动删除 socket 文件，这边再确认一次，避免下次启动时残留
+	if err := os.Remove(u.filepath); err != nil && !os.IsNotExist(err) {
+		common2.LogWarn(err)
+	}
+}
@@ -22,24 +22,58 @@
 package mgmt
 
 import (
+	"fmt"
+	"github.com/sirupsen/logrus"
 	"minlib/common"
 	"minlib/component"
 	"minlib/mgmt"
 	"minlib/packet"
+	common2 "mir-go/daemon/common"
+	"mir-go/daemon/fw"
 	"mir-go/daemon/lf"
 	"mir-go/daemon/table"
+	"strconv"
+	"strings"
 )
 
+const (
+	ManagementModuleCsMgmt   = "cs-mgmt" // CS管理模块名
+	CsManagementActionErase  = "erase"   // 删除某个前缀下的缓存
+	CsManagementActionConfig = "config"  // 修改CS的配置
+	CsManagementActionInfo   = "info"    // 获取CS的配置和统计信息
+
+	CsConfigAdmit       = "admit"       // 是否缓存数据包
+	CsConfigServe       = "serve"       // 是否使用缓存的数据包响应兴趣包
+	CsConfigAdmission   = "admission"   // 准入策略 admit-all | probabilistic | prefix | second-hit
+	CsConfigProbability = "probability" // probabilistic 准入策略的缓存概率
+	CsConfigAllow       = "allow"       // prefix 准入策略的前缀白名单，多个前缀以分号分隔
+	CsConfigDeny        = "deny"        // prefix 准入策略的前缀黑名单，多个前缀以分号分隔
+	CsConfigHistory     = "history"     // second-hit 准入策略最多记录的请求标识的个数
+)
+
+// CsInfo
+// CS的配置和统计信息
+//
+// @Description:
+//
+type CsInfo struct {
+	Capacity        int64  // 缓存的容量，memory 缓存的单位为包个数，two-tier 缓存为内存层的字节数
+	NEntries        uint64 // 已缓存的数据包的数量
+	EnableAdmit     bool   // 是否缓存数据包
+	EnableServe     bool   // 是否使用缓存的数据包响应兴趣包
+	AdmissionPolicy string // 准入策略
+	NHits           uint64 // 命中次数
+	NMisses         uint64 // 未命中次数
+}
+
 // CsManager
 // CS管理模块结构体
 //
 // @Description:CS管理模块结构体
 //
 type CsManager struct {
-	cs             *table.CS // CS表
+	forwarder      *fw.Forwarder // 转发器，通过其内嵌的CS表进行管理
 	logicFaceTable *lf.LogicFaceTable
-	enableServe    bool // 是否可以展示信息
-	enableAdd      bool // 是否可以添加缓存
 }
 
 // CreateCsManager
@@ -49,112 +83,227 @@ type CsManager struct {
 // @Return:*CsManager
 //
 func CreateCsManager() *CsManager {
-	return &CsManager{
-		cs:          new(table.CS),
-		enableServe: true,
-		enableAdd:   true,
-	}
+	return &CsManager{}
 }
 
 // Init
 // CS管理模块初始化注册行为函数
 //
-// @Description:对CS管理模块注册三个必须的函数add、delete、list
+// @Description:注册 erase、config 两个控制命令以及 info 数据集
 // @receiver c
 //
 func (c *CsManager) Init(dispatcher *Dispatcher, logicFaceTable *lf.LogicFaceTable) {
 	c.logicFaceTable = logicFaceTable
-	identifier, _ := component.CreateIdentifierByString("/cs-mgmt/delete")
-	err := dispatcher.AddControlCommand(identifier, dispatcher.authorization, c.ValidateParameters, c.changeConfig)
+
+	// /cs-mgmt/erase => 删除某个前缀下的缓存
+	identifier, _ := component.CreateIdentifierByStringArray(ManagementModuleCsMgmt, CsManagementActionErase)
+	err := dispatcher.AddControlCommand(identifier, dispatcher.authorization, func(parameters *component.ControlParameters) bool {
+		return parameters.ControlParameterPrefix.IsInitial()
+	}, c.EraseEntries)
 	if err != nil {
-		common.LogError("cs add delete-command fail,the err is:", err)
+		common.LogError("add cs erase-command fail,the err is:", err)
 	}
-	identifier, _ = component.CreateIdentifierByString("/cs-mgmt/list")
-	err = dispatcher.AddStatusDataset(
-		identifier,
-		dispatcher.authorization,
-		func(parameters *component.ControlParameters) bool {
-			return true
-		},
-		c.serveInfo,
-	)
+
+	// /cs-mgmt/config => 修改CS的配置
+	identifier, _ = component.CreateIdentifierByStringArray(ManagementModuleCsMgmt, CsManagementActionConfig)
+	err = dispatcher.AddControlCommand(identifier, dispatcher.authorization, func(parameters *component.ControlParameters) bool {
+		return parameters.ControlParameterCapacity.IsInitial() ||
+			parameters.ControlParameterCommonString.IsInitial()
+	}, c.ChangeConfig)
+	if err != nil {
+		common.LogError("add cs config-command fail,the err is:", err)
+	}
+
+	// /cs-mgmt/info => 获取CS的配置和统计信息
+	identifier, _ = component.CreateIdentifierByStringArray(ManagementModuleCsMgmt, CsManagementActionInfo)
+	err = dispatcher.AddStatusDataset(identifier, dispatcher.authorization, func(parameters *component.ControlParameters) bool {
+		return true
+	}, c.ServeInfo)
 	if err != nil {
-		common.LogError("cs add list-command fail,the err is:", err)
+		common.LogError("add cs info-command fail,the err is:", err)
 	}
 }
 
-// TODO:后续进行实现，配置CS表读写权限等
+// EraseEntries
+// 删除某个前缀下的缓存
 //
-// 修改配置函数
-//
-// @Description:对CS管理模块配置进行修改，如是否可读，是否可以插入新的数据
+// @Description:前缀通过 ControlParameterPrefix 传递；ControlParameterCount 可选，表示最多删除的条目个数，不传时删除所有匹配的条目。
+// 返回数据为删除的条目个数
 // @receiver c
 //
-func (c *CsManager) changeConfig(topPrefix *component.Identifier, interest *packet.Interest,
+func (c *CsManager) EraseEntries(topPrefix *component.Identifier, interest *packet.Interest,
 	parameters *component.ControlParameters) *mgmt.ControlResponse {
-	c.enableServe = true
-	return nil
+	prefix := parameters.ControlParameterPrefix.Prefix()
+	limit := 0
+	if parameters.ControlParameterCount.IsInitial() {
+		count := parameters.ControlParameterCount.Count()
+		if count == 0 {
+			return MakeControlResponse(400, "the count must be greater than 0", "")
+		}
+		limit = int(count)
+	}
+	erased := c.forwarder.ICS.Erase(prefix, limit)
+	common.LogInfo("Erase cs entries success:", prefix.ToUri(), "->", erased)
+	return MakeControlResponse(200, "erase cs entries success", fmt.Sprint(erased))
 }
 
+// ChangeConfig
+// 修改CS的配置
 //
-// 获取CS管理模块的服务信息
+// @Description:对CS管理模块配置进行修改，ControlParameterCapacity 可选，表示新的容量；ControlParameterCommonString 可选，
+// 格式为逗号分隔的 name=value 列表，例如 admit=on,serve=off,admission=prefix,deny=/video/live 。
+// 所有配置都先检查，任何一项无效时不做任何修改。返回数据为修改之后的配置
+// @receiver c
 //
-// @Description:获取CS管理模块的服务信息，分片发送给客户端，信息包括配置信息、条目数量、命中缓存次数等
+func (c *CsManager) ChangeConfig(topPrefix *component.Identifier, interest *packet.Interest,
+	parameters *component.ControlParameters) *mgmt.ControlResponse {
+	var switches map[string]bool
+	var admissionPolicy table.ICSAdmissionPolicy
+	if parameters.ControlParameterCommonString.IsInitial() {
+		var admissionConfig *table.CSAdmissionPolicyConfig
+		var err error
+		if switches, admissionConfig, err = parseCsConfig(parameters.ControlParameterCommonString.Value()); err != nil {
+			return MakeControlResponse(400, err.Error(), "")
+		}
+		if admissionConfig != nil {
+			if admissionPolicy, err = table.CreateCSAdmissionPolicy(admissionConfig); err != nil {
+				return MakeControlResponse(400, err.Error(), "")
+			}
+		}
+	}
+	if parameters.ControlParameterCapacity.IsInitial() {
+		if err := c.forwarder.ICS.SetCapacity(int64(parameters.ControlParameterCapacity.Capacity())); err != nil {
+			common.LogDebugWithFields(logrus.Fields{
+				"capacity": parameters.ControlParameterCapacity.Capacity(),
+				"error":    err,
+			}, "set cs capacity fail")
+			return MakeControlResponse(400, err.Error(), "")
+		}
+	}
+	if enable, ok := switches[CsConfigAdmit]; ok {
+		c.forwarder.ICS.SetEnableAdmit(enable)
+	}
+	if enable, ok := switches[CsConfigServe]; ok {
+		c.forwarder.ICS.SetEnableServe(enable)
+	}
+	if admissionPolicy != nil {
+		c.forwarder.ICS.SetAdmissionPolicy(admissionPolicy)
+	}
+
+	config := fmt.Sprintf("capacity=%d,%s=%s,%s=%s,%s=%s", c.forwarder.ICS.GetCapacity(),
+		CsConfigAdmit, formatCsConfigSwitch(c.forwarder.ICS.IsAdmitEnabled()),
+		CsConfigServe, formatCsConfigSwitch(c.forwarder.ICS.IsServeEnabled()),
+		CsConfigAdmission, c.forwarder.ICS.GetAdmissionPolicy().GetName())
+	common.LogInfo("Change cs config success:", config)
+	return MakeControlResponse(200, "change cs config success", config)
+}
+
+// ServeInfo
+// 获取CS的配置和统计信息
+//
+// @Description:获取CS的配置和统计信息，信息包括容量、条目数量、开关、命中和未命中次数等。
+// 信息是实时生成的，使用当前时间作为数据集的版本号
 // @receiver c
 //
-func (c *CsManager) serveInfo(topPrefix *component.Identifier, interest *packet.Interest,
+func (c *CsManager) ServeInfo(topPrefix *component.Identifier, interest *packet.Interest,
 	parameters *component.ControlParameters,
 	context *StatusDatasetContext) {
-	//var response *mgmt.ControlResponse
-	//if c.enableServe {
-	//	var CSInfo = struct {
-	//		enableServe bool
-	//		enableAdd   bool
-	//		size        uint64
-	//		hits        uint64
-	//		misses      uint64
-	//	}{
-	//		enableServe: c.enableServe,
-	//		enableAdd:   c.enableAdd,
-	//		size:        c.cs.Size(),
-	//		hits:        c.cs.Hits,
-	//		misses:      c.cs.Misses,
-	//	}
-	//	data, err := json.Marshal(CSInfo)
-	//	if err != nil {
-	//		response = MakeControlResponse(400, "mashal CSInfo fail , the err is:"+err.Error(), "")
-	//		context.responseSender(response, interest)
-	//	}
-	//	context.data = data
-	//	dataList := context.Append()
-	//	if dataList == nil {
-	//		response = MakeControlResponse(400, "slice data packet err!", "")
-	//		context.responseSender(response, interest)
-	//		return
-	//	} else {
-	//		for _, data := range dataList {
-	//			// 包编码放在dataSender中
-	//			context.dataSender(data)
-	//		}
-	//	}
-	//} else {
-	//	response = MakeControlResponse(400, "have no Permission to get CsInfo!", "")
-	//	context.responseSender(response, interest)
-	//}
+	cs := c.forwarder.ICS
+	context.Append(CsInfo{
+		Capacity:        cs.GetCapacity(),
+		NEntries:        uint64(cs.Size()),
+		EnableAdmit:     cs.IsAdmitEnabled(),
+		EnableServe:     cs.IsServeEnabled(),
+		AdmissionPolicy: cs.GetAdmissionPolicy().GetName(),
+		NHits:           cs.GetHits(),
+		NMisses:         cs.GetMisses(),
+	})
+	_ = context.Done(common2.GetCurrentTime())
 }
 
-// ValidateParameters
-// 参数验证函数
 //
-// @Description:对传入的控制参数进行参数验证，条件判断语句中的为必需字段，有一项不存在则错误
-// @receiver c
-// @Return:bool
+// 解析 config 命令的配置列表
 //
-func (c *CsManager) ValidateParameters(parameters *component.ControlParameters) bool {
-	if parameters.ControlParameterPrefix.IsInitial() &&
-		parameters.ControlParameterCount.IsInitial() &&
-		parameters.ControlParameterCapacity.IsInitial() {
-		return true
+// @Description:格式为逗号分隔的 name=value ：
+//  1. admit 和 serve 为开关，value 为 on/off 或者 true/false ；
+//  2. admission 为新的准入策略的名字，probability、allow、deny 和 history 为准入策略的参数，只能和 admission 一起使用，
+//     allow 和 deny 中的多个前缀以分号分隔
+// @param str
+// @return map[string]bool					开关
+// @return *table.CSAdmissionPolicyConfig		新的准入策略的配置，不修改准入策略时为 nil
+// @return error
+//
+func parseCsConfig(str string) (map[string]bool, *table.CSAdmissionPolicyConfig, error) {
+	switches := make(map[string]bool)
+	admissionConfig := &table.CSAdmissionPolicyConfig{}
+	hasAdmission, hasAdmissionParameter := false, false
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return nil, nil, fmt.Errorf("invalid cs config: %s, require: name=value", item)
+		}
+		name, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch name {
+		case CsConfigAdmit, CsConfigServe:
+			switch strings.ToLower(value) {
+			case "on", "true":
+				switches[name] = true
+			case "off", "false":
+				switches[name] = false
+			default:
+				return nil, nil, fmt.Errorf("invalid cs config value: %s, require: on|off", item)
+			}
+		case CsConfigAdmission:
+			hasAdmission = true
+			admissionConfig.Name = value
+		case CsConfigProbability:
+			hasAdmissionParameter = true
+			probability, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid cs config value: %s, require: a float in (0, 1]", item)
+			}
+			admissionConfig.Probability = probability
+		case CsConfigAllow:
+			hasAdmissionParameter = true
+			admissionConfig.AllowPrefixes = strings.Split(value, ";")
+		case CsConfigDeny:
+			hasAdmissionParameter = true
+			admissionConfig.DenyPrefixes = strings.Split(value, ";")
+		case CsConfigHistory:
+			hasAdmissionParameter = true
+			historySize, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid cs config value: %s, require: an integer", item)
+			}
+			admissionConfig.HistorySize = historySize
+		default:
+			return nil, nil, fmt.Errorf("invalid cs config: %s, require one of: %s, %s, %s, %s, %s, %s, %s", item,
+				CsConfigAdmit, CsConfigServe, CsConfigAdmission, CsConfigProbability, CsConfigAllow, CsConfigDeny, CsConfigHistory)
+		}
+	}
+	if !hasAdmission {
+		if hasAdmissionParameter {
+			return nil, nil, fmt.Errorf("admission policy parameters require %s=<policy>", CsConfigAdmission)
+		}
+		return switches, nil, nil
+	}
+	return switches, admissionConfig, nil
+}
+
+//
+// 将开关格式化为 on/off
+//
+// @Description:
+// @param enable
+// @return string
+//
+func formatCsConfigSwitch(enable bool) string {
+	if enable {
+		return "on"
 	}
-	return false
+	return "off"
 }
@@ -0,0 +1,83 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package mgmt
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 9:20 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package mgmt
+
+import (
+	"minlib/component"
+	"minlib/packet"
+	"mir-go/daemon/common"
+	"mir-go/daemon/fw"
+	"mir-go/daemon/table"
+	"testing"
+)
+
+func createTestCsManager(t *testing.T) (*CsManager, table.ICS) {
+	config := new(common.MIRConfig)
+	config.Init()
+	cs, err := table.NewUniversalCS(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &CsManager{forwarder: &fw.Forwarder{ICS: cs}}, cs
+}
+
+func changeTestCsConfig(manager *CsManager, config string) int {
+	parameters := &component.ControlParameters{}
+	parameters.SetCommonString(config)
+	return manager.ChangeConfig(nil, nil, parameters).Code
+}
+
+func TestCsManager_ChangeAdmissionPolicy(t *testing.T) {
+	manager, cs := createTestCsManager(t)
+	if cs.GetAdmissionPolicy().GetName() != table.CSAdmissionPolicyAdmitAll {
+		t.Fatal("expect admit-all by default, got", cs.GetAdmissionPolicy().GetName())
+	}
+
+	// 运行时替换为 prefix 策略
+	if code := changeTestCsConfig(manager, "admission=prefix,deny=/video/live;/audio/live"); code != 200 {
+		t.Fatal("expect admission policy changed, got", code)
+	}
+	if cs.GetAdmissionPolicy().GetName() != table.CSAdmissionPolicyPrefix {
+		t.Fatal("expect prefix policy, got", cs.GetAdmissionPolicy().GetName())
+	}
+	identifier, _ := component.CreateIdentifierByString("/audio/live/seg1")
+	data := &packet.Data{}
+	data.SetName(identifier)
+	if _, err := cs.Insert(data); err == nil {
+		t.Fatal("expect /audio/live/seg1 not admitted")
+	}
+
+	// 任何一项无效时不做任何修改
+	if code := changeTestCsConfig(manager, "admit=off,admission=probabilistic,probability=2"); code != 400 {
+		t.Fatal("expect invalid probability rejected, got", code)
+	}
+	if !cs.IsAdmitEnabled() || cs.GetAdmissionPolicy().GetName() != table.CSAdmissionPolicyPrefix {
+		t.Fatal("expect config unchanged after invalid command")
+	}
+	if code := changeTestCsConfig(manager, "deny=/video"); code != 400 {
+		t.Fatal("expect admission parameters without admission rejected, got", code)
+	}
+	if code := changeTestCsConfig(manager, "admission=unknown"); code != 400 {
+		t.Fatal("expect unknown admission policy rejected, got", code)
+	}
+}
@@ -32,10 +32,12 @@ import (
 	"minlib/mgmt"
 	"minlib/packet"
 	"minlib/security"
+	"mir-go/daemon/audit"
 	common2 "mir-go/daemon/common"
 	"mir-go/daemon/lf"
 	"mir-go/daemon/table"
 	"mir-go/daemon/utils"
+	"strconv"
 	"sync"
 )
 
@@ -70,6 +72,7 @@ type Dispatcher struct {
 	keyChain      *security.KeyChain               // 网络包签名和验签 发送数据包的时候使用
 	SignInfo      *component.SignatureInfo         // 表示签名的元数据
 	Cache         *Cache                           // 存储数据包分片缓存
+	auditLog      *audit.AuditLog                  // 审计日志，为 nil 时不记录
 }
 
 // CreateDispatcher
@@ -190,6 +193,7 @@ func (d *Dispatcher) Start() {
 							return
 						} else {
 							response := module.ccHandler(topPrefix, interest, parameters)
+							d.recordAudit(audit.AuditEventMgmtCommand, prefix, response.Code)
 							d.sendControlResponse(response, interest)
 							return
 						}
@@ -206,12 +210,45 @@ func (d *Dispatcher) Start() {
 				})
 			}, func(errorType int) {
 				// Reject => 权限验证失败，返回错误
+				d.recordAudit(audit.AuditEventMgmtReject, prefix, errorType)
 				d.sendControlResponse(MakeControlResponse(errorType, "Authorization Failed!", ""), interest)
 			})
 		}
 	})
 }
 
+// SetAuditLog
+// 设置审计日志，执行的管理命令和权限验证失败的管理命令会被记录到审计日志中
+//
+// @Description:
+// @receiver d
+// @param auditLog
+//
+func (d *Dispatcher) SetAuditLog(auditLog *audit.AuditLog) {
+	d.auditLog = auditLog
+}
+
+//
+// 将一个管理命令记录到审计日志中
+//
+// @Description:
+// @receiver d
+// @param eventType		事件类型
+// @param prefix			管理命令兴趣包的名字
+// @param code			管理命令的返回码或者权限验证失败的错误码
+//
+func (d *Dispatcher) recordAudit(eventType string, prefix *component.Identifier, code int) {
+	if d.auditLog == nil {
+		return
+	}
+	if err := d.auditLog.Record(eventType, map[string]string{
+		"command": prefix.ToUri(),
+		"code":    strconv.Itoa(code),
+	}); err != nil {
+		common.LogError("Record management command to audit log failed: ", err)
+	}
+}
+
 //
 // 授权验证函数
 //
@@ -26,7 +26,6 @@ import (
 	"minlib/component"
 	"minlib/encoding"
 	"minlib/packet"
-	"minlib/utils"
 	"mir-go/daemon/fw"
 	"mir-go/daemon/lf"
 	"testing"
@@ -37,7 +36,7 @@ func Test(t *testing.T) {
 
 	var Fsystem lf.LogicFaceSystem
 	var packetValidator = &fw.PacketValidator{}
-	blockQueue := utils.NewBlockQueue(100)
+	blockQueue := make(chan *lf.IncomingPacketData, 100)
 	packetValidator.Init(100, false, blockQueue)
 	Fsystem.Init(packetValidator, nil)
 	Fsystem.Start()
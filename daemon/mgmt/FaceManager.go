@@ -32,11 +32,17 @@ import (
 	"strings"
 )
 
+// FaceInfo
+// 逻辑接口的信息，包括该接口完整的流量统计
+//
+// @Description:流量统计以内嵌的方式展开，序列化之后和其它字段处于同一层级
+//
 type FaceInfo struct {
 	LogicFaceId uint64
 	RemoteUri   string
 	LocalUri    string
 	Mtu         uint64
+	lf.LogicFaceCounters
 }
 
 // FaceManager face管理模块结构体
@@ -217,10 +223,11 @@ func (f *FaceManager) listLogicFace(topPrefix *component.Identifier, interest *p
 	for _, face := range faceList {
 		if face.GetState() { // 只提取 UP 状态的逻辑接口
 			faceInfo := &FaceInfo{
-				LogicFaceId: face.LogicFaceId,
-				RemoteUri:   face.GetRemoteUri(),
-				LocalUri:    face.GetLocalUri(),
-				Mtu:         face.Mtu,
+				LogicFaceId:       face.LogicFaceId,
+				RemoteUri:         face.GetRemoteUri(),
+				LocalUri:          face.GetLocalUri(),
+				Mtu:               face.Mtu,
+				LogicFaceCounters: face.GetCounters().Snapshot(),
 			}
 			context.Append(faceInfo)
 		}
@@ -23,15 +23,18 @@
 package mgmt
 
 import (
+	"mir-go/daemon/fw"
 	"mir-go/daemon/lf"
 	"mir-go/daemon/table"
 )
 
 type ManagementSystem struct {
-	csManager       *CsManager
-	fibManager      *FibManager
-	faceManager     *FaceManager
-	identityManager *IdentityManager
+	csManager             *CsManager
+	fibManager            *FibManager
+	faceManager           *FaceManager
+	identityManager       *IdentityManager
+	strategyChoiceManager *StrategyChoiceManager
+	statusManager         *StatusManager
 }
 
 func (m *ManagementSystem) Init(dispatcher *Dispatcher, logicFaceTable *lf.LogicFaceTable) {
@@ -40,20 +43,30 @@ func (m *ManagementSystem) Init(dispatcher *Dispatcher, logicFaceTable *lf.Logic
 	m.csManager.Init(dispatcher, logicFaceTable)
 	m.identityManager = CreateIdentityManager(dispatcher.keyChain)
 	m.identityManager.Init(dispatcher)
+	m.strategyChoiceManager.Init(dispatcher)
+	m.statusManager.Init(dispatcher, logicFaceTable)
 }
 
 func (m *ManagementSystem) SetFIB(fib *table.FIB) {
 	m.fibManager.fib = fib
 }
 
+func (m *ManagementSystem) SetForwarder(forwarder *fw.Forwarder) {
+	m.csManager.forwarder = forwarder
+	m.strategyChoiceManager.forwarder = forwarder
+	m.statusManager.forwarder = forwarder
+}
+
 func (m *ManagementSystem) BindFibCleaner(l *lf.LogicFaceTable) {
 	l.OnEvicted = m.fibManager.NextHopCleaner
 }
 
 func CreateMgmtSystem() *ManagementSystem {
 	return &ManagementSystem{
-		csManager:   CreateCsManager(),
-		faceManager: CreateFaceManager(),
-		fibManager:  CreateFibManager(),
+		csManager:             CreateCsManager(),
+		faceManager:           CreateFaceManager(),
+		fibManager:            CreateFibManager(),
+		strategyChoiceManager: CreateStrategyChoiceManager(),
+		statusManager:         CreateStatusManager(),
 	}
 }
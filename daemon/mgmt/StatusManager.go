@@ -0,0 +1,125 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package mgmt
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 10:30 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package mgmt
+
+import (
+	"minlib/common"
+	"minlib/component"
+	"minlib/packet"
+	common2 "mir-go/daemon/common"
+	"mir-go/daemon/fw"
+	"mir-go/daemon/lf"
+)
+
+const (
+	ManagementModuleStatusMgmt    = "status"  // 转发器状态管理模块名
+	StatusManagementActionGeneral = "general" // 获取转发器的总体状态
+)
+
+// GeneralStatus
+// 转发器的总体状态
+//
+// @Description:包括版本号、运行时间、各个表的大小以及转发器全局的统计信息
+//
+type GeneralStatus struct {
+	Version                          string            // 转发器的版本号
+	StartTime                        uint64            // 转发器的启动时间，单位 ms
+	CurrentTime                      uint64            // 生成本状态的时间，单位 ms
+	UpTime                           uint64            // 转发器已经运行的时间，单位 ms
+	NPITEntries                      uint64            // PIT 条目的个数
+	NFIBEntries                      uint64            // FIB 条目的个数
+	NCSEntries                       uint64            // CS 中缓存的数据包的个数
+	NLogicFaces                      uint64            // 逻辑接口的个数
+	NDuplicateGPPktDrops             uint64            // 因为重复被丢弃的普通推式包的个数
+	IdentifierTypeDrops              map[uint64]uint64 // 注册了处理函数的标识类型 => 因为不在白名单中而被丢弃的包的个数
+	NUnregisteredIdentifierTypeDrops uint64            // 因为标识类型没有注册处理函数而被丢弃的包的个数
+	fw.ForwarderCounters                               // 转发器全局的统计信息
+}
+
+// StatusManager
+// 转发器状态管理模块结构体
+//
+// @Description:用于查询转发器的总体状态
+//
+type StatusManager struct {
+	forwarder      *fw.Forwarder
+	logicFaceTable *lf.LogicFaceTable
+}
+
+// CreateStatusManager
+// 创建转发器状态管理模块
+//
+// @Description:
+// @return *StatusManager
+//
+func CreateStatusManager() *StatusManager {
+	return &StatusManager{}
+}
+
+// Init
+// 转发器状态管理模块初始化注册命令函数
+//
+// @Description:注册 general 数据集
+// @receiver s
+// @param dispatcher
+// @param logicFaceTable
+//
+func (s *StatusManager) Init(dispatcher *Dispatcher, logicFaceTable *lf.LogicFaceTable) {
+	s.logicFaceTable = logicFaceTable
+
+	// /status/general => 获取转发器的总体状态
+	identifier, _ := component.CreateIdentifierByStringArray(ManagementModuleStatusMgmt, StatusManagementActionGeneral)
+	err := dispatcher.AddStatusDataset(identifier, dispatcher.authorization, func(parameters *component.ControlParameters) bool {
+		return true
+	}, s.GetGeneralStatus)
+	if err != nil {
+		common.LogError("add status general-command fail,the err is:", err)
+	}
+}
+
+// GetGeneralStatus
+// 获取转发器的总体状态
+//
+// @Description:总体状态是实时生成的，使用当前时间作为数据集的版本号，保证每次请求都能获取到最新的状态
+// @receiver s
+//
+func (s *StatusManager) GetGeneralStatus(topPrefix *component.Identifier, interest *packet.Interest,
+	parameters *component.ControlParameters,
+	context *StatusDatasetContext) {
+	currentTime := common2.GetCurrentTime()
+	status := GeneralStatus{
+		Version:                          common2.MIRVersion,
+		StartTime:                        s.forwarder.GetStartTime(),
+		CurrentTime:                      currentTime,
+		UpTime:                           currentTime - s.forwarder.GetStartTime(),
+		NPITEntries:                      s.forwarder.PIT.Size(),
+		NFIBEntries:                      s.forwarder.FIB.Size(),
+		NCSEntries:                       uint64(s.forwarder.ICS.Size()),
+		NLogicFaces:                      s.logicFaceTable.Size(),
+		NDuplicateGPPktDrops:             s.forwarder.GetDuplicateGPPktDropCount(),
+		IdentifierTypeDrops:              s.forwarder.GetIdentifierTypeDropCounts(),
+		NUnregisteredIdentifierTypeDrops: s.forwarder.GetUnregisteredIdentifierTypeDropCount(),
+		ForwarderCounters:                s.forwarder.GetCounters(),
+	}
+	context.Append(status)
+	_ = context.Done(currentTime)
+}
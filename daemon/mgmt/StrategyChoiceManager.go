@@ -0,0 +1,191 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package mgmt
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 4:20 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package mgmt
+
+import (
+	"github.com/sirupsen/logrus"
+	"minlib/common"
+	"minlib/component"
+	"minlib/mgmt"
+	"minlib/packet"
+	"mir-go/daemon/fw"
+	"mir-go/daemon/table"
+	"strconv"
+)
+
+const (
+	ManagementModuleStrategyChoiceMgmt  = "strategy-choice" // 策略选择管理模块名
+	StrategyChoiceManagementActionSet   = "set"             // 为某个前缀设置转发策略
+	StrategyChoiceManagementActionUnset = "unset"           // 取消某个前缀的转发策略
+	StrategyChoiceManagementActionList  = "list"            // 列出所有前缀的转发策略
+)
+
+// StrategyChoiceInfo
+// 策略选择表中一个条目的信息
+//
+// @Description:
+//
+type StrategyChoiceInfo struct {
+	Prefix   string // 前缀
+	Strategy string // 策略名（带版本号和参数）
+}
+
+// StrategyChoiceManager
+// 策略选择管理模块结构体
+//
+// @Description:用于在运行时修改某个前缀使用的转发策略
+//
+type StrategyChoiceManager struct {
+	forwarder *fw.Forwarder
+}
+
+// CreateStrategyChoiceManager
+// 创建策略选择管理模块
+//
+// @Description:
+// @return *StrategyChoiceManager
+//
+func CreateStrategyChoiceManager() *StrategyChoiceManager {
+	return &StrategyChoiceManager{}
+}
+
+// Init
+// 策略选择管理模块初始化注册命令函数
+//
+// @Description:注册 set、unset 两个控制命令以及 list 数据集
+// @receiver s
+// @param dispatcher
+//
+func (s *StrategyChoiceManager) Init(dispatcher *Dispatcher) {
+	// /strategy-choice/set => 为某个前缀设置转发策略
+	identifier, _ := component.CreateIdentifierByStringArray(ManagementModuleStrategyChoiceMgmt, StrategyChoiceManagementActionSet)
+	err := dispatcher.AddControlCommand(identifier, dispatcher.authorization, func(parameters *component.ControlParameters) bool {
+		return parameters.ControlParameterPrefix.IsInitial() &&
+			parameters.ControlParameterCommonString.IsInitial()
+	}, s.SetStrategy)
+	if err != nil {
+		common.LogError("add strategy-choice set-command fail,the err is:", err)
+	}
+
+	// /strategy-choice/unset => 取消某个前缀的转发策略
+	identifier, _ = component.CreateIdentifierByStringArray(ManagementModuleStrategyChoiceMgmt, StrategyChoiceManagementActionUnset)
+	err = dispatcher.AddControlCommand(identifier, dispatcher.authorization, func(parameters *component.ControlParameters) bool {
+		return parameters.ControlParameterPrefix.IsInitial()
+	}, s.UnsetStrategy)
+	if err != nil {
+		common.LogError("add strategy-choice unset-command fail,the err is:", err)
+	}
+
+	// /strategy-choice/list => 展示所有前缀的转发策略
+	identifier, _ = component.CreateIdentifierByStringArray(ManagementModuleStrategyChoiceMgmt, StrategyChoiceManagementActionList)
+	err = dispatcher.AddStatusDataset(identifier, dispatcher.authorization, func(parameters *component.ControlParameters) bool {
+		return true
+	}, s.ListChoices)
+	if err != nil {
+		common.LogError("add strategy-choice list-command fail,the err is:", err)
+	}
+}
+
+// SetStrategy
+// 为某个前缀设置转发策略
+//
+// @Description:策略名通过 ControlParameterCommonString 传递，例如：/strategy/best-route、/strategy/asf/v=1/probing-interval=30000
+// @receiver s
+//
+func (s *StrategyChoiceManager) SetStrategy(topPrefix *component.Identifier, interest *packet.Interest,
+	parameters *component.ControlParameters) *mgmt.ControlResponse {
+	prefix := parameters.ControlParameterPrefix.Prefix()
+	strategyName := parameters.ControlParameterCommonString.Value()
+
+	// 标识前缀 不能太长 太长返回错误信息
+	if prefix.Size() > table.MAX_DEPTH {
+		common.LogDebugWithFields(logrus.Fields{
+			"max depth":          table.MAX_DEPTH,
+			"the size of prefix": prefix.Size(),
+		}, "the prefix is too long")
+		return MakeControlResponse(400, "the prefix is too long ,cannot exceed "+strconv.Itoa(table.MAX_DEPTH)+"components", "")
+	}
+
+	if err := s.forwarder.SetStrategy(prefix, strategyName); err != nil {
+		common.LogDebugWithFields(logrus.Fields{
+			"prefix":   prefix.ToUri(),
+			"strategy": strategyName,
+			"error":    err,
+		}, "set strategy fail")
+		// 策略名、版本号或者参数不合法，属于请求参数错误
+		return MakeControlResponse(400, err.Error(), "")
+	}
+
+	// 返回规范化之后的策略名
+	entry := s.forwarder.StrategyTable.FindExactMatch(prefix)
+	if entry == nil {
+		return MakeControlResponse(500, "set strategy fail", "")
+	}
+	common.LogInfo("Set strategy success:", prefix.ToUri(), "->", entry.GetStrategyName())
+	return MakeControlResponse(200, "set strategy success", entry.GetStrategyName())
+}
+
+// UnsetStrategy
+// 取消某个前缀的转发策略，之后该前缀下的网络包由更短前缀上配置的策略处理
+//
+// @Description:根前缀 / 上的策略不能被取消，保证所有的网络包都能匹配到一个策略
+// @receiver s
+//
+func (s *StrategyChoiceManager) UnsetStrategy(topPrefix *component.Identifier, interest *packet.Interest,
+	parameters *component.ControlParameters) *mgmt.ControlResponse {
+	prefix := parameters.ControlParameterPrefix.Prefix()
+	if prefix.Size() == 0 {
+		return MakeControlResponse(400, "the strategy of / can't be unset", "")
+	}
+	if s.forwarder.StrategyTable.FindExactMatch(prefix) == nil {
+		common.LogDebugWithFields(logrus.Fields{
+			"prefix": prefix.ToUri(),
+		}, "strategy choice is not found")
+		return MakeControlResponse(400, "the strategy choice is not found", "")
+	}
+	if err := s.forwarder.StrategyTable.Erase(prefix); err != nil {
+		common.LogDebugWithFields(logrus.Fields{
+			"error": err,
+		}, "unset strategy fail")
+		return MakeControlResponse(400, err.Error(), "")
+	}
+	common.LogInfo("Unset strategy success:", prefix.ToUri())
+	return MakeControlResponse(200, "unset strategy success", "")
+}
+
+// ListChoices
+// 获取策略选择表中所有的条目
+//
+// @Description:获取策略选择表中所有的条目，并分片发送给客户端
+// @receiver s
+//
+func (s *StrategyChoiceManager) ListChoices(topPrefix *component.Identifier, interest *packet.Interest,
+	parameters *component.ControlParameters,
+	context *StatusDatasetContext) {
+	for _, entry := range s.forwarder.StrategyTable.GetAllEntry() {
+		context.Append(StrategyChoiceInfo{
+			Prefix:   entry.GetPrefix().ToUri(),
+			Strategy: entry.GetStrategyName(),
+		})
+	}
+	_ = context.Done(s.forwarder.StrategyTable.GetVersion())
+}
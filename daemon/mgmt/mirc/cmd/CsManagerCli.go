@@ -0,0 +1,265 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package cmd
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 9:30 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/desertbit/grumble"
+	"github.com/olekukonko/tablewriter"
+	"minlib/common"
+	"minlib/component"
+	mgmtlib "minlib/mgmt"
+	"mir-go/daemon/mgmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CreateCsCommands 创建一个 CsCommands
+//
+// @Description:
+// @return grumble.Command
+//
+func CreateCsCommands(controller *mgmtlib.MIRController) *grumble.Command {
+	cc := new(grumble.Command)
+	cc.Name = "cs"
+	cc.Help = "Content Store Management"
+
+	// erase
+	cc.AddCommand(&grumble.Command{
+		Name: "erase",
+		Help: "Erase cached data under specific prefix",
+		Args: func(a *grumble.Args) {
+			a.String("prefix", "Target identifier prefix")
+		},
+		Flags: func(f *grumble.Flags) {
+			f.Uint64("n", "count", 0, "Max number of entries to erase, 0 means all")
+		},
+		Run: func(c *grumble.Context) error {
+			return EraseCs(c, controller)
+		},
+	})
+
+	// config
+	cc.AddCommand(&grumble.Command{
+		Name: "config",
+		Help: "Change content store config",
+		Flags: func(f *grumble.Flags) {
+			f.String("a", "admit", "", "Whether to cache incoming data, on/off")
+			f.String("s", "serve", "", "Whether to satisfy interests with cached data, on/off")
+			f.Uint64("c", "capacity", 0, "New capacity, packets for memory storage or memory tier bytes for two-tier storage, 0 means unchanged")
+			f.String("p", "admission", "", "New admission policy, admit-all/probabilistic/prefix/second-hit")
+			f.Float64("r", "probability", 0, "Admission probability of probabilistic policy, in (0, 1]")
+			f.String("w", "allow", "", "Comma separated allowed prefixes of prefix policy")
+			f.String("d", "deny", "", "Comma separated denied prefixes of prefix policy")
+			f.Int("t", "history", 0, "Max recorded request names of second-hit policy, 0 means default")
+		},
+		Run: func(c *grumble.Context) error {
+			return ConfigCs(c, controller)
+		},
+	})
+
+	// info
+	cc.AddCommand(&grumble.Command{
+		Name: "info",
+		Help: "Show content store config and counters",
+		Run: func(c *grumble.Context) error {
+			return ShowCsInfo(c, controller)
+		},
+	})
+
+	return cc
+}
+
+// EraseCs 删除指定前缀下的缓存
+//
+// @Description:
+// @param c
+// @return error
+//
+func EraseCs(c *grumble.Context, controller *mgmtlib.MIRController) error {
+	// 解析命令行参数
+	prefix := c.Args.String("prefix")
+	count := c.Flags.Uint64("count")
+
+	parameters := &component.ControlParameters{}
+	identifier, err := component.CreateIdentifierByString(prefix)
+	if err != nil {
+		return err
+	}
+	parameters.SetPrefix(identifier)
+	if count > 0 {
+		parameters.SetCount(count)
+	}
+
+	// 构造一个命令执行器
+	commandExecutor, err := controller.PrepareCommandExecutor(createCsEraseCommand(parameters))
+	if err != nil {
+		return err
+	}
+	commandExecutor.SetAutoShutdown(true)
+
+	// 执行命令
+	response, err := commandExecutor.Start()
+	if err != nil {
+		return err
+	}
+
+	// 如果请求成功，则输出结果
+	if response.Code == mgmtlib.ControlResponseCodeSuccess {
+		common.LogInfo(fmt.Sprintf("Erase cs entries under %s success! erased: %s", prefix, response.GetString()))
+	} else {
+		// 请求失败，则输出错误信息
+		common.LogError(fmt.Sprintf("Erase cs entries under %s failed! errMsg: %s", prefix, response.Msg))
+	}
+	return nil
+}
+
+// ConfigCs 修改CS的配置
+//
+// @Description:
+// @param c
+// @return error
+//
+func ConfigCs(c *grumble.Context, controller *mgmtlib.MIRController) error {
+	// 解析命令行参数
+	admit := c.Flags.String("admit")
+	serve := c.Flags.String("serve")
+	capacity := c.Flags.Uint64("capacity")
+	admission := c.Flags.String("admission")
+	probability := c.Flags.Float64("probability")
+	allow := c.Flags.String("allow")
+	deny := c.Flags.String("deny")
+	history := c.Flags.Int("history")
+
+	parameters := &component.ControlParameters{}
+	switches := make([]string, 0, 2)
+	if admit != "" {
+		switches = append(switches, mgmt.CsConfigAdmit+"="+admit)
+	}
+	if serve != "" {
+		switches = append(switches, mgmt.CsConfigServe+"="+serve)
+	}
+	if admission != "" {
+		switches = append(switches, mgmt.CsConfigAdmission+"="+admission)
+		if probability > 0 {
+			switches = append(switches, mgmt.CsConfigProbability+"="+strconv.FormatFloat(probability, 'f', -1, 64))
+		}
+		// 命令行中的前缀以逗号分隔，配置列表本身使用逗号分隔，所以转换成分号
+		if allow != "" {
+			switches = append(switches, mgmt.CsConfigAllow+"="+strings.ReplaceAll(allow, ",", ";"))
+		}
+		if deny != "" {
+			switches = append(switches, mgmt.CsConfigDeny+"="+strings.ReplaceAll(deny, ",", ";"))
+		}
+		if history > 0 {
+			switches = append(switches, mgmt.CsConfigHistory+"="+strconv.Itoa(history))
+		}
+	} else if probability > 0 || allow != "" || deny != "" || history > 0 {
+		return errors.New("--probability, --allow, --deny and --history require --admission")
+	}
+	if len(switches) > 0 {
+		parameters.SetCommonString(strings.Join(switches, ","))
+	}
+	if capacity > 0 {
+		parameters.SetCapacity(capacity)
+	}
+	if len(switches) == 0 && capacity == 0 {
+		return errors.New("nothing to change, require at least one of --admit, --serve, --capacity and --admission")
+	}
+
+	// 构造一个命令执行器
+	commandExecutor, err := controller.PrepareCommandExecutor(createCsConfigCommand(parameters))
+	if err != nil {
+		return err
+	}
+	commandExecutor.SetAutoShutdown(true)
+
+	// 执行命令
+	response, err := commandExecutor.Start()
+	if err != nil {
+		return err
+	}
+
+	// 如果请求成功，则输出结果
+	if response.Code == mgmtlib.ControlResponseCodeSuccess {
+		common.LogInfo(fmt.Sprintf("Change cs config success! current config: %s", response.GetString()))
+	} else {
+		// 请求失败，则输出错误信息
+		common.LogError(fmt.Sprintf("Change cs config failed! errMsg: %s", response.Msg))
+	}
+	return nil
+}
+
+// ShowCsInfo 显示CS的配置和统计信息
+//
+// @Description:
+// @param c
+// @return error
+//
+func ShowCsInfo(c *grumble.Context, controller *mgmtlib.MIRController) error {
+	// 构造一个命令执行器
+	commandExecutor, err := controller.PrepareCommandExecutor(createCsInfoCommand())
+	if err != nil {
+		return err
+	}
+	commandExecutor.SetAutoShutdown(true)
+
+	// 执行命令
+	response, err := commandExecutor.Start()
+	if err != nil {
+		return err
+	}
+
+	// 反序列化，输出结果
+	var csInfoList []mgmt.CsInfo
+	err = json.Unmarshal(response.GetBytes(), &csInfoList)
+	if err != nil {
+		return err
+	}
+	if len(csInfoList) == 0 {
+		return errors.New("cs info is empty")
+	}
+	info := csInfoList[0]
+
+	// 使用表格美化输出
+	table := tablewriter.NewWriter(os.Stdout)
+	table.AppendBulk([][]string{
+		{"Capacity", fmt.Sprint(info.Capacity)},
+		{"NEntries", fmt.Sprint(info.NEntries)},
+		{"EnableAdmit", fmt.Sprint(info.EnableAdmit)},
+		{"EnableServe", fmt.Sprint(info.EnableServe)},
+		{"AdmissionPolicy", info.AdmissionPolicy},
+		{"NHits", fmt.Sprint(info.NHits)},
+		{"NMisses", fmt.Sprint(info.NMisses)},
+	})
+	table.SetHeader([]string{"Item", "Value"})
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.FgHiRedColor, tablewriter.Bold},
+		tablewriter.Colors{tablewriter.FgHiRedColor, tablewriter.Bold})
+	table.SetCaption(true, "Content Store Info")
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.Render()
+	return nil
+}
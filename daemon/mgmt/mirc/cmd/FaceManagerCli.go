@@ -57,6 +57,18 @@ func CreateLogicFaceCommands(controller *mgmtlib.MIRController) *grumble.Command
 		},
 	})
 
+	// show
+	lfc.AddCommand(&grumble.Command{
+		Name: "show",
+		Help: "Show detail info and traffic counters of a LogicFace",
+		Args: func(a *grumble.Args) {
+			a.Uint64("id", "The LogicFaceId you want to show")
+		},
+		Run: func(c *grumble.Context) error {
+			return ShowLogicFace(c, controller)
+		},
+	})
+
 	// add
 	lfc.AddCommand(&grumble.Command{
 		Name: "add",
@@ -95,21 +107,7 @@ func CreateLogicFaceCommands(controller *mgmtlib.MIRController) *grumble.Command
 // @return error
 //
 func ListLogicFace(c *grumble.Context, controller *mgmtlib.MIRController) error {
-	commandExecutor, err := controller.PrepareCommandExecutor(mgmtlib.CreateLogicFaceListCommand(topPrefix))
-	if err != nil {
-		return err
-	}
-	commandExecutor.SetAutoShutdown(true)
-
-	// 执行命令拉取结果
-	response, err := commandExecutor.Start()
-	if err != nil {
-		return err
-	}
-
-	// 反序列化，输出结果
-	var faceInfoList []mgmt.FaceInfo
-	err = json.Unmarshal(response.GetBytes(), &faceInfoList)
+	faceInfoList, err := fetchFaceInfoList(controller)
 	if err != nil {
 		return err
 	}
@@ -134,6 +132,86 @@ func ListLogicFace(c *grumble.Context, controller *mgmtlib.MIRController) error
 	return nil
 }
 
+// ShowLogicFace 展示指定 LogicFace 的详细信息以及流量统计
+//
+// @Description:
+// @param c
+// @return error
+//
+func ShowLogicFace(c *grumble.Context, controller *mgmtlib.MIRController) error {
+	logicFaceId := c.Args.Uint64("id")
+	faceInfoList, err := fetchFaceInfoList(controller)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range faceInfoList {
+		if v.LogicFaceId != logicFaceId {
+			continue
+		}
+		// 使用表格美化输出
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Append([]string{"LogicFaceId", strconv.FormatUint(v.LogicFaceId, 10)})
+		table.Append([]string{"LocalUri", v.LocalUri})
+		table.Append([]string{"RemoteUri", v.RemoteUri})
+		table.Append([]string{"Mtu", strconv.FormatUint(v.Mtu, 10)})
+		table.Append([]string{"Interest (in/out/drop)", formatCounters(v.InInterestN, v.OutInterestN, v.DropInterestN)})
+		table.Append([]string{"Data (in/out/drop)", formatCounters(v.InDataN, v.OutDataN, v.DropDataN)})
+		table.Append([]string{"Nack (in/out/drop)", formatCounters(v.InNackN, v.OutNackN, v.DropNackN)})
+		table.Append([]string{"GPPkt (in/out/drop)", formatCounters(v.InGPPktN, v.OutGPPktN, v.DropGPPktN)})
+		table.Append([]string{"Bytes (in/out)", strconv.FormatUint(v.InBytesN, 10) + " / " + strconv.FormatUint(v.OutBytesN, 10)})
+		table.SetHeader([]string{"Item", "Value"})
+		table.SetHeaderColor(
+			tablewriter.Colors{tablewriter.FgHiRedColor, tablewriter.Bold},
+			tablewriter.Colors{tablewriter.FgHiRedColor, tablewriter.Bold})
+		table.SetCaption(true, "LogicFace Info")
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.Render()
+		return nil
+	}
+	common.LogError("LogicFace not found, id =", logicFaceId)
+	return nil
+}
+
+//
+// 拉取所有处于 UP 状态的 LogicFace 的信息
+//
+// @Description:
+// @param controller
+// @return []mgmt.FaceInfo
+// @return error
+//
+func fetchFaceInfoList(controller *mgmtlib.MIRController) ([]mgmt.FaceInfo, error) {
+	commandExecutor, err := controller.PrepareCommandExecutor(mgmtlib.CreateLogicFaceListCommand(topPrefix))
+	if err != nil {
+		return nil, err
+	}
+	commandExecutor.SetAutoShutdown(true)
+
+	// 执行命令拉取结果
+	response, err := commandExecutor.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	// 反序列化
+	var faceInfoList []mgmt.FaceInfo
+	if err := json.Unmarshal(response.GetBytes(), &faceInfoList); err != nil {
+		return nil, err
+	}
+	return faceInfoList, nil
+}
+
+//
+// 将 in/out/drop 三个计数格式化成一个字符串
+//
+// @Description:
+// @return string
+//
+func formatCounters(in uint64, out uint64, drop uint64) string {
+	return strconv.FormatUint(in, 10) + " / " + strconv.FormatUint(out, 10) + " / " + strconv.FormatUint(drop, 10)
+}
+
 // AddLogicFace 创建一个新的 LogicFace 连接到另一个路由器
 //
 // @Description:
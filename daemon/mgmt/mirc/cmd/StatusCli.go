@@ -0,0 +1,156 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package cmd
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 10:30 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/desertbit/grumble"
+	"github.com/olekukonko/tablewriter"
+	mgmtlib "minlib/mgmt"
+	"mir-go/daemon/mgmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CreateStatusCommands 创建一个 StatusCommands
+//
+// @Description:
+// @return grumble.Command
+//
+func CreateStatusCommands(controller *mgmtlib.MIRController) *grumble.Command {
+	return &grumble.Command{
+		Name: "status",
+		Help: "Show general status of forwarder",
+		Run: func(c *grumble.Context) error {
+			return ShowGeneralStatus(c, controller)
+		},
+	}
+}
+
+// ShowGeneralStatus 显示转发器的总体状态
+//
+// @Description:
+// @param c
+// @return error
+//
+func ShowGeneralStatus(c *grumble.Context, controller *mgmtlib.MIRController) error {
+	// 构造一个命令执行器
+	commandExecutor, err := controller.PrepareCommandExecutor(createGeneralStatusCommand())
+	if err != nil {
+		return err
+	}
+	commandExecutor.SetAutoShutdown(true)
+
+	// 执行命令
+	response, err := commandExecutor.Start()
+	if err != nil {
+		return err
+	}
+
+	// 反序列化，输出结果
+	var generalStatusList []mgmt.GeneralStatus
+	err = json.Unmarshal(response.GetBytes(), &generalStatusList)
+	if err != nil {
+		return err
+	}
+	if len(generalStatusList) == 0 {
+		return errors.New("general status is empty")
+	}
+	status := generalStatusList[0]
+
+	// 使用表格美化输出
+	table := tablewriter.NewWriter(os.Stdout)
+	table.AppendBulk([][]string{
+		{"Version", status.Version},
+		{"StartTime", formatTimestamp(status.StartTime)},
+		{"CurrentTime", formatTimestamp(status.CurrentTime)},
+		{"UpTime", (time.Duration(status.UpTime) * time.Millisecond).String()},
+		{"NPITEntries", fmt.Sprint(status.NPITEntries)},
+		{"NFIBEntries", fmt.Sprint(status.NFIBEntries)},
+		{"NCSEntries", fmt.Sprint(status.NCSEntries)},
+		{"NLogicFaces", fmt.Sprint(status.NLogicFaces)},
+		{"NInInterests", fmt.Sprint(status.NInInterests)},
+		{"NOutInterests", fmt.Sprint(status.NOutInterests)},
+		{"NInData", fmt.Sprint(status.NInData)},
+		{"NOutData", fmt.Sprint(status.NOutData)},
+		{"NInNacks", fmt.Sprint(status.NInNacks)},
+		{"NOutNacks", fmt.Sprint(status.NOutNacks)},
+		{"NInGPPkts", fmt.Sprint(status.NInGPPkts)},
+		{"NOutGPPkts", fmt.Sprint(status.NOutGPPkts)},
+		{"NCsHits", fmt.Sprint(status.NCsHits)},
+		{"NCsMisses", fmt.Sprint(status.NCsMisses)},
+		{"NUnsolicitedData", fmt.Sprint(status.NUnsolicitedData)},
+		{"NInterestLoops", fmt.Sprint(status.NInterestLoops)},
+		{"NSatisfiedInterests", fmt.Sprint(status.NSatisfiedInterests)},
+		{"NUnsatisfiedInterests", fmt.Sprint(status.NUnsatisfiedInterests)},
+		{"NShardQueueDrops", fmt.Sprint(status.NShardQueueDrops)},
+		{"NDuplicateGPPktDrops", fmt.Sprint(status.NDuplicateGPPktDrops)},
+		{"IdentifierTypeDrops", formatIdentifierTypeDrops(status.IdentifierTypeDrops)},
+		{"NUnregisteredIdentifierTypeDrops", fmt.Sprint(status.NUnregisteredIdentifierTypeDrops)},
+	})
+	table.SetHeader([]string{"Item", "Value"})
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.FgHiRedColor, tablewriter.Bold},
+		tablewriter.Colors{tablewriter.FgHiRedColor, tablewriter.Bold})
+	table.SetCaption(true, "General Status")
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.Render()
+	return nil
+}
+
+// formatTimestamp 将毫秒时间戳格式化为本地时间
+//
+// @Description:
+// @param timestamp
+// @return string
+//
+func formatTimestamp(timestamp uint64) string {
+	return time.Unix(0, int64(timestamp)*int64(time.Millisecond)).Format("2006-01-02 15:04:05")
+}
+
+// formatIdentifierTypeDrops 将每种标识类型的丢包数按照标识类型排序之后格式化为 "类型=丢包数" 的列表
+//
+// @Description:
+// @param drops
+// @return string
+//
+func formatIdentifierTypeDrops(drops map[uint64]uint64) string {
+	if len(drops) == 0 {
+		return "0"
+	}
+	identifierTypes := make([]uint64, 0, len(drops))
+	for identifierType := range drops {
+		identifierTypes = append(identifierTypes, identifierType)
+	}
+	sort.Slice(identifierTypes, func(i, j int) bool {
+		return identifierTypes[i] < identifierTypes[j]
+	})
+	items := make([]string, 0, len(identifierTypes))
+	for _, identifierType := range identifierTypes {
+		items = append(items, fmt.Sprintf("%d=%d", identifierType, drops[identifierType]))
+	}
+	return strings.Join(items, " ")
+}
@@ -0,0 +1,205 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package cmd
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 4:50 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/desertbit/grumble"
+	"github.com/olekukonko/tablewriter"
+	"minlib/common"
+	"minlib/component"
+	mgmtlib "minlib/mgmt"
+	"mir-go/daemon/mgmt"
+	"os"
+)
+
+// CreateStrategyChoiceCommands 创建一个 StrategyChoiceCommands
+//
+// @Description:
+// @return grumble.Command
+//
+func CreateStrategyChoiceCommands(controller *mgmtlib.MIRController) *grumble.Command {
+	sc := new(grumble.Command)
+	sc.Name = "strategy"
+	sc.Help = "Strategy Choice Management"
+
+	// set
+	sc.AddCommand(&grumble.Command{
+		Name: "set",
+		Help: "Set forwarding strategy for specific prefix",
+		Args: func(a *grumble.Args) {
+			a.String("prefix", "Target identifier prefix")
+			a.String("strategy", "Strategy name, e.g. /strategy/best-route or /strategy/asf/v=1/probing-interval=30000")
+		},
+		Run: func(c *grumble.Context) error {
+			return SetStrategy(c, controller)
+		},
+	})
+
+	// unset
+	sc.AddCommand(&grumble.Command{
+		Name: "unset",
+		Help: "Unset forwarding strategy for specific prefix",
+		Args: func(a *grumble.Args) {
+			a.String("prefix", "Target identifier prefix")
+		},
+		Run: func(c *grumble.Context) error {
+			return UnsetStrategy(c, controller)
+		},
+	})
+
+	// list
+	sc.AddCommand(&grumble.Command{
+		Name: "list",
+		Help: "Show all strategy choices",
+		Run: func(c *grumble.Context) error {
+			return ListStrategyChoices(c, controller)
+		},
+	})
+
+	return sc
+}
+
+// SetStrategy 为指定前缀设置转发策略
+//
+// @Description:
+// @param c
+// @return error
+//
+func SetStrategy(c *grumble.Context, controller *mgmtlib.MIRController) error {
+	// 解析命令行参数
+	prefix := c.Args.String("prefix")
+	strategyName := c.Args.String("strategy")
+
+	parameters := &component.ControlParameters{}
+	identifier, err := component.CreateIdentifierByString(prefix)
+	if err != nil {
+		return err
+	}
+	parameters.SetPrefix(identifier)
+	parameters.SetCommonString(strategyName)
+
+	// 构造一个命令执行器
+	commandExecutor, err := controller.PrepareCommandExecutor(createStrategyChoiceSetCommand(parameters))
+	if err != nil {
+		return err
+	}
+	commandExecutor.SetAutoShutdown(true)
+
+	// 执行命令
+	response, err := commandExecutor.Start()
+	if err != nil {
+		return err
+	}
+
+	// 如果请求成功，则输出结果
+	if response.Code == mgmtlib.ControlResponseCodeSuccess {
+		common.LogInfo(fmt.Sprintf("Set strategy for %s => %s success!", prefix, response.GetString()))
+	} else {
+		// 请求失败，则输出错误信息
+		common.LogError(fmt.Sprintf("Set strategy for %s => %s failed! errMsg: %s", prefix, strategyName, response.Msg))
+	}
+	return nil
+}
+
+// UnsetStrategy 取消指定前缀的转发策略
+//
+// @Description:
+// @param c
+// @return error
+//
+func UnsetStrategy(c *grumble.Context, controller *mgmtlib.MIRController) error {
+	// 解析命令行参数
+	prefix := c.Args.String("prefix")
+
+	parameters := &component.ControlParameters{}
+	identifier, err := component.CreateIdentifierByString(prefix)
+	if err != nil {
+		return err
+	}
+	parameters.SetPrefix(identifier)
+
+	// 构造一个命令执行器
+	commandExecutor, err := controller.PrepareCommandExecutor(createStrategyChoiceUnsetCommand(parameters))
+	if err != nil {
+		return err
+	}
+	commandExecutor.SetAutoShutdown(true)
+
+	// 执行命令
+	response, err := commandExecutor.Start()
+	if err != nil {
+		return err
+	}
+
+	// 如果请求成功，则输出结果
+	if response.Code == mgmtlib.ControlResponseCodeSuccess {
+		common.LogInfo(fmt.Sprintf("Unset strategy for %s success!", prefix))
+	} else {
+		// 请求失败，则输出错误信息
+		common.LogError(fmt.Sprintf("Unset strategy for %s failed! errMsg: %s", prefix, response.Msg))
+	}
+	return nil
+}
+
+// ListStrategyChoices 显示所有前缀对应的转发策略
+//
+// @Description:
+// @param c
+// @return error
+//
+func ListStrategyChoices(c *grumble.Context, controller *mgmtlib.MIRController) error {
+	// 构造一个命令执行器
+	commandExecutor, err := controller.PrepareCommandExecutor(createStrategyChoiceListCommand())
+	if err != nil {
+		return err
+	}
+	commandExecutor.SetAutoShutdown(true)
+
+	// 执行命令
+	response, err := commandExecutor.Start()
+	if err != nil {
+		return err
+	}
+
+	// 反序列化，输出结果
+	var strategyChoiceInfoList []mgmt.StrategyChoiceInfo
+	err = json.Unmarshal(response.GetBytes(), &strategyChoiceInfoList)
+	if err != nil {
+		return err
+	}
+
+	// 使用表格美化输出
+	table := tablewriter.NewWriter(os.Stdout)
+	for _, info := range strategyChoiceInfoList {
+		table.Append([]string{info.Prefix, info.Strategy})
+	}
+	table.SetHeader([]string{"Prefix", "Strategy"})
+	table.SetHeaderColor(
+		tablewriter.Colors{tablewriter.FgHiRedColor, tablewriter.Bold},
+		tablewriter.Colors{tablewriter.FgHiRedColor, tablewriter.Bold})
+	table.SetCaption(true, "Strategy Choice Info")
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.Render()
+	return nil
+}
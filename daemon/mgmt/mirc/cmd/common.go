@@ -28,6 +28,7 @@ import (
 	mgmtlib "minlib/mgmt"
 	"minlib/packet"
 	"minlib/security"
+	"mir-go/daemon/mgmt"
 )
 
 // 全局前缀
@@ -66,6 +67,91 @@ func newCommandInterest(moduleName string, action string) *packet.Interest {
 	return interest
 }
 
+// newControlCommand 构造一个发往本地转发器的管理命令
+//
+// @Description:
+//  minlib 只提供了 face、fib 和 identity 管理模块的命令，在转发器中新增的管理模块的命令通过这个函数构造
+// @param moduleName
+// @param action
+// @param parameters
+// @return *mgmtlib.ControlCommand
+//
+func newControlCommand(moduleName string, action string, parameters *component.ControlParameters) *mgmtlib.ControlCommand {
+	identifier, _ := component.CreateIdentifierByString(buildPrefix(moduleName, action))
+	return &mgmtlib.ControlCommand{
+		Prefix:     identifier,
+		Parameters: parameters,
+	}
+}
+
+// createCsEraseCommand 构造删除某个前缀下的缓存的命令
+//
+// @Description:
+// @param parameters
+// @return *mgmtlib.ControlCommand
+//
+func createCsEraseCommand(parameters *component.ControlParameters) *mgmtlib.ControlCommand {
+	return newControlCommand(mgmt.ManagementModuleCsMgmt, mgmt.CsManagementActionErase, parameters)
+}
+
+// createCsConfigCommand 构造修改CS的配置的命令
+//
+// @Description:
+// @param parameters
+// @return *mgmtlib.ControlCommand
+//
+func createCsConfigCommand(parameters *component.ControlParameters) *mgmtlib.ControlCommand {
+	return newControlCommand(mgmt.ManagementModuleCsMgmt, mgmt.CsManagementActionConfig, parameters)
+}
+
+// createCsInfoCommand 构造获取CS的配置和统计信息的命令
+//
+// @Description:
+// @return *mgmtlib.ControlCommand
+//
+func createCsInfoCommand() *mgmtlib.ControlCommand {
+	return newControlCommand(mgmt.ManagementModuleCsMgmt, mgmt.CsManagementActionInfo, &component.ControlParameters{})
+}
+
+// createStrategyChoiceSetCommand 构造为某个前缀设置转发策略的命令
+//
+// @Description:
+// @param parameters
+// @return *mgmtlib.ControlCommand
+//
+func createStrategyChoiceSetCommand(parameters *component.ControlParameters) *mgmtlib.ControlCommand {
+	return newControlCommand(mgmt.ManagementModuleStrategyChoiceMgmt, mgmt.StrategyChoiceManagementActionSet, parameters)
+}
+
+// createStrategyChoiceUnsetCommand 构造取消某个前缀的转发策略的命令
+//
+// @Description:
+// @param parameters
+// @return *mgmtlib.ControlCommand
+//
+func createStrategyChoiceUnsetCommand(parameters *component.ControlParameters) *mgmtlib.ControlCommand {
+	return newControlCommand(mgmt.ManagementModuleStrategyChoiceMgmt, mgmt.StrategyChoiceManagementActionUnset, parameters)
+}
+
+// createStrategyChoiceListCommand 构造列出所有前缀的转发策略的命令
+//
+// @Description:
+// @return *mgmtlib.ControlCommand
+//
+func createStrategyChoiceListCommand() *mgmtlib.ControlCommand {
+	return newControlCommand(mgmt.ManagementModuleStrategyChoiceMgmt, mgmt.StrategyChoiceManagementActionList,
+		&component.ControlParameters{})
+}
+
+// createGeneralStatusCommand 构造获取转发器的总体状态的命令
+//
+// @Description:
+// @return *mgmtlib.ControlCommand
+//
+func createGeneralStatusCommand() *mgmtlib.ControlCommand {
+	return newControlCommand(mgmt.ManagementModuleStatusMgmt, mgmt.StatusManagementActionGeneral, &component.ControlParameters{})
+}
+
 // GetController 构造一个通用的用 Unix 通信的本地命令控制器
 //
 // @Description:
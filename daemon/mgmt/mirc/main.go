@@ -118,8 +118,14 @@ func mirc(mirConfig *common2.MIRConfig) {
 	app.AddCommand(cmd.CreateLogicFaceCommands(controller))
 	// 添加 Fib 管理命令
 	app.AddCommand(cmd.CreateFibCommands(controller))
+	// 添加策略选择管理命令
+	app.AddCommand(cmd.CreateStrategyChoiceCommands(controller))
 	// 添加 Identity 管理命令
 	app.AddCommand(cmd.CreateIdentityCommands(controller))
+	// 添加 CS 管理命令
+	app.AddCommand(cmd.CreateCsCommands(controller))
+	// 添加转发器状态查询命令
+	app.AddCommand(cmd.CreateStatusCommands(controller))
 
 	grumble.Main(app)
 }
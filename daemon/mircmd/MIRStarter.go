@@ -26,7 +26,7 @@ import (
 	common2 "minlib/common"
 	"minlib/component"
 	"minlib/security"
-	"minlib/utils"
+	"mir-go/daemon/audit"
 	"mir-go/daemon/common"
 	"mir-go/daemon/fw"
 	"mir-go/daemon/lf"
@@ -35,10 +35,16 @@ import (
 	"mir-go/daemon/table"
 	utils2 "mir-go/daemon/utils"
 	"net"
+	"strconv"
+	"strings"
 	"time"
 )
 
-const defaultConfigFilePath = "/usr/local/etc/mir/mirconf.ini"
+const (
+	defaultConfigFilePath = "/usr/local/etc/mir/mirconf.ini"
+	defaultDrainTimeout   = 3 * time.Second // 退出时排空包队列的最长时间
+	defaultFlushTimeout   = 1 * time.Second // 退出时等待 LogicFace 发送队列发送完成的最长时间
+)
 
 // MIRStarter MIR 启动器
 //
@@ -51,6 +57,8 @@ type MIRStarter struct {
 	forwarder                  *fw.Forwarder       //转发器
 	logicFaceSystem            *lf.LogicFaceSystem // 管理LogicFace
 	dispatcher                 *mgmt.Dispatcher    // 管理命令分发器
+	packetValidator            *fw.PacketValidator // 包验证器
+	auditLog                   *audit.AuditLog     // 审计日志，没有开启时为 nil
 }
 
 // NewMIRStarter 新建一个 MIR 启动器
@@ -75,8 +83,8 @@ func (m *MIRStarter) Init(mirConfig *common.MIRConfig) {
 		common2.LogFatal(err)
 	}
 
-	// 初始化 BlockQueue
-	packetQueue := utils.NewBlockQueue(uint(m.mirConfig.ForwarderConfig.PacketQueueSize))
+	// 初始化包队列
+	packetQueue := make(chan *lf.IncomingPacketData, m.mirConfig.ForwarderConfig.PacketQueueSize)
 
 	// 初始化转发器
 	m.forwarder = new(fw.Forwarder)
@@ -85,17 +93,32 @@ func (m *MIRStarter) Init(mirConfig *common.MIRConfig) {
 	}
 
 	// PacketValidator
-	packetValidator := new(fw.PacketValidator)
-	packetValidator.Init(m.mirConfig.ParallelVerifyNum, m.mirConfig.VerifyPacket, packetQueue)
+	m.packetValidator = new(fw.PacketValidator)
+	// 开启中间路由器签名时，需要验证之前每一跳追加的签名；配置了信任模式时，也需要先验证签名
+	needValidate := m.mirConfig.VerifyPacket || m.mirConfig.MiddleRouterSignature || m.mirConfig.TrustSchemaPath != ""
+	m.packetValidator.Init(m.mirConfig.ParallelVerifyNum, needValidate, packetQueue)
+	if m.mirConfig.MiddleRouterSignature {
+		m.packetValidator.SetMaxRouterSignatureNum(m.mirConfig.MaxRouterSignatureNum)
+	}
+	if m.mirConfig.TrustSchemaPath != "" {
+		trustSchema, err := fw.LoadTrustSchema(utils2.GetRelPath(m.mirConfig.TrustSchemaPath))
+		if err != nil {
+			common2.LogFatal(err)
+		}
+		m.packetValidator.SetTrustSchema(trustSchema)
+	}
 
 	// LogicFaceSystem
 	m.logicFaceSystem = new(lf.LogicFaceSystem)
-	m.logicFaceSystem.Init(packetValidator, m.mirConfig)
+	m.logicFaceSystem.Init(m.packetValidator, m.mirConfig)
+	// 转发器需要在 LogicFace 被关闭时清理 PIT 并通知策略
+	m.forwarder.SubscribeLogicFaceEvents(m.logicFaceSystem.LogicFaceTable().GetEventBus())
 
 	// 管理模块
 	faceServer, faceClient := lf.CreateInnerLogicFacePair()
 	mgmtSystem := mgmt.CreateMgmtSystem()
 	mgmtSystem.SetFIB(m.forwarder.GetFIB())
+	mgmtSystem.SetForwarder(m.forwarder)
 	mgmtSystem.BindFibCleaner(m.logicFaceSystem.LogicFaceTable())
 	m.dispatcher = mgmt.CreateDispatcher(m.mirConfig, &m.keyChain)
 	m.dispatcher.FaceClient = faceClient
@@ -103,6 +126,17 @@ func (m *MIRStarter) Init(mirConfig *common.MIRConfig) {
 	m.dispatcher.AddTopPrefix(topPrefix, m.forwarder.GetFIB(), faceServer)
 	mgmtSystem.Init(m.dispatcher, m.logicFaceSystem.LogicFaceTable())
 
+	// 开启证书获取时，通过一对内部 LogicFace 从网络中获取未知签名者的证书
+	if needValidate && m.mirConfig.FetchCertificate {
+		trustAnchors := parseCertificateTrustAnchors(m.mirConfig.CertificateTrustAnchors)
+		if len(trustAnchors) == 0 {
+			common2.LogFatal("FetchCertificate need at least one CertificateTrustAnchors")
+		}
+		certFaceServer, certFaceClient := lf.CreateInnerLogicFacePair()
+		m.packetValidator.EnableCertificateFetcher(certFaceServer, trustAnchors, uint64(m.mirConfig.CertificateCacheLifetime),
+			uint64(m.mirConfig.CertificateFetchTimeout)).Start(certFaceClient)
+	}
+
 	// 加载静态路由配置
 	utils2.GoroutineNoPanic(func() {
 		SetUpDefaultRoute(m.mirConfig.DefaultRouteConfigPath, m.forwarder.GetFIB())
@@ -112,6 +146,7 @@ func (m *MIRStarter) Init(mirConfig *common.MIRConfig) {
 // Start 传入所使用身份的密码，启动MIR
 //
 // @Description:
+//  阻塞直到收到退出信号或者转发器崩溃，然后执行退出流程。返回的错误不为 nil 时，表示 MIR 是异常退出的
 // @param pwd
 //
 func (m *MIRStarter) Start(pwd string) (string, error) {
@@ -120,13 +155,82 @@ func (m *MIRStarter) Start(pwd string) (string, error) {
 		return "", err
 	}
 
+	// 网络身份解锁之后才能开启中间路由器签名
+	if m.mirConfig.MiddleRouterSignature {
+		signers, err := m.createRouterSigners(pwd, m.forwarder.GetShardNum())
+		if err != nil {
+			return "", err
+		}
+		m.forwarder.EnableRouterSignature(signers, m.mirConfig.MaxRouterSignatureNum)
+	}
+
+	// 开启 Log2BlockChain 时，安全相关的事件会被记录到本地的审计日志中，审计记录使用解锁之后的路由器网络身份签名
+	if m.mirConfig.Log2BlockChain {
+		if err := m.initAuditLog(); err != nil {
+			return "", err
+		}
+	}
+
 	// 启动 LogicFaceSystem
 	m.logicFaceSystem.Start()
 
 	// 启动命令分发程序
 	m.dispatcher.Start()
-	// 启动转发处理流程（死循环阻塞）
-	return m.forwarder.Start()
+	// 启动转发处理流程（阻塞直到收到退出信号或者转发器崩溃）
+	resMsg, resErr := m.forwarder.Start()
+	m.shutdown(resErr == nil)
+	return resMsg, resErr
+}
+
+// shutdown 执行 MIR 的退出流程
+//
+// @Description:
+//  1. 关闭所有的监听器，不再接受新的 LogicFace，并让所有的 LogicFace 停止接收，包队列中只剩下有限的已经收到的网络包；
+//  2. 排空包队列，处理完已经收到的网络包，并向所有仍然 pending 的兴趣包的下游发送 Nack（转发器崩溃时跳过这一步）；
+//  3. 停止包验证器往包队列写入，释放阻塞在包队列上的收包协程和验签协程；
+//  4. 等待所有 LogicFace 发送队列中的包（包括第 2 步发送的 Nack ）发送完成，之后关闭所有的 LogicFace；
+//  5. 释放包验证器，关闭CS（two-tier 缓存会将内存层中的条目持久化到磁盘），并关闭审计日志。
+// @param drain		是否需要排空包队列
+//
+func (m *MIRStarter) shutdown(drain bool) {
+	common2.LogInfo("MIR is shutting down...")
+	m.logicFaceSystem.StopListeners()
+	m.logicFaceSystem.StopReceiving()
+	if drain {
+		m.forwarder.Stop(defaultDrainTimeout)
+	}
+	// 转发器已经不再读取包队列
+	m.packetValidator.Stop()
+	if drain {
+		m.logicFaceSystem.FlushAllFaces(defaultFlushTimeout)
+	}
+	m.logicFaceSystem.ShutdownAllFaces()
+	m.packetValidator.Close()
+	if err := m.forwarder.ICS.Close(); err != nil {
+		common2.LogWarn("Close content store failed: ", err)
+	}
+	if m.auditLog != nil {
+		_ = m.auditLog.Close()
+	}
+	common2.LogInfo("MIR shutdown complete")
+}
+
+// recordLogicFaceEvent 将 LogicFace 的创建记录到审计日志中
+//
+// @Description:
+// @param event
+//
+func (m *MIRStarter) recordLogicFaceEvent(event *lf.LogicFaceEvent) {
+	if event.Type != lf.LogicFaceEventCreated {
+		return
+	}
+	if err := m.auditLog.Record(audit.AuditEventFaceCreated, map[string]string{
+		"faceId":    strconv.FormatUint(event.LogicFace.LogicFaceId, 10),
+		"localUri":  event.LogicFace.GetLocalUri(),
+		"remoteUri": event.LogicFace.GetRemoteUri(),
+	}); err != nil {
+		common2.LogError("Record LogicFace creation to audit log failed: ", err)
+	}
 }
 
 // IsExistDefaultIdentity 判断默认身份是否存在
@@ -166,6 +270,74 @@ func (m *MIRStarter) initKeyChain(passwd string) error {
 	return nil
 }
 
+// initAuditLog 打开审计日志，并让包验证器、命令分发程序和 LogicFace 表将安全相关的事件记录到审计日志中
+//
+// @Description:
+//  需要在 initKeyChain 之后、启动 LogicFaceSystem 之前调用。审计日志已经存在但是头部缺失、签名者不是当前网络身份或者被截断时
+//  返回错误，MIR 拒绝启动，而不是重新开始一条新的哈希链
+// @return error
+//
+func (m *MIRStarter) initAuditLog() error {
+	signer, err := audit.CreateIdentityAuditSigner(&m.keyChain)
+	if err != nil {
+		return err
+	}
+	auditLog, err := audit.OpenFileAuditLog(utils2.GetRelPath(m.mirConfig.AuditLogPath), signer)
+	if err != nil {
+		return err
+	}
+	m.auditLog = auditLog
+	m.packetValidator.SetAuditLog(auditLog)
+	m.dispatcher.SetAuditLog(auditLog)
+	m.logicFaceSystem.LogicFaceTable().GetEventBus().Subscribe(m.recordLogicFaceEvent)
+	return nil
+}
+
+// createRouterSigners 为每个转发分片创建一个中间路由器签名实现
+//
+// @Description:
+//  每个签名实现使用一个单独打开并解锁的 KeyChain ，不和其它分片以及管理模块共享 m.keyChain ，所以各个分片可以并发的签名而不需要加锁
+// @param passwd		路由器网络身份的密码
+// @param num			需要创建的签名实现的个数
+// @return []fw.IRouterSigner
+// @return error
+//
+func (m *MIRStarter) createRouterSigners(passwd string, num int) ([]fw.IRouterSigner, error) {
+	signers := make([]fw.IRouterSigner, 0, num)
+	for i := 0; i < num; i++ {
+		keyChain := new(security.KeyChain)
+		if err := keyChain.InitialKeyChainByPath(utils2.GetRelPath(m.mirConfig.SecurityConfig.IdentityDBPath)); err != nil {
+			return nil, err
+		}
+		identity := keyChain.GetIdentityByName(m.mirConfig.GeneralConfig.DefaultId)
+		if identity == nil {
+			return nil, errors.New("identify must not be nil!")
+		}
+		if err := keyChain.SetCurrentIdentity(identity, passwd); err != nil {
+			return nil, err
+		}
+		signers = append(signers, fw.CreateKeyChainRouterSigner(keyChain))
+	}
+	return signers, nil
+}
+
+//
+// 解析以逗号分隔的信任锚配置
+//
+// @Description:
+// @param config
+// @return []string
+//
+func parseCertificateTrustAnchors(config string) []string {
+	trustAnchors := make([]string, 0)
+	for _, anchor := range strings.Split(config, ",") {
+		if anchor = strings.TrimSpace(anchor); anchor != "" {
+			trustAnchors = append(trustAnchors, anchor)
+		}
+	}
+	return trustAnchors
+}
+
 // SetUpDefaultRoute
 // @Description: 加载静态路由配置文件
 // @param defaultRouteConfigPath	静态路由配置文件的文件路径
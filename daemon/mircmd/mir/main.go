@@ -63,7 +63,13 @@ func main() {
 			passwd = askSetPasswd(mirConfig.GeneralConfig.DefaultId)
 		}
 		passwd = utils.GetEncryptPasswd(passwd)
-		starter.Start(passwd)
+		resMsg, err := starter.Start(passwd)
+		if err != nil {
+			// 异常退出，返回非 0 的状态码
+			common2.LogError(resMsg, ": ", err)
+			os.Exit(1)
+		}
+		common2.LogInfo(resMsg)
 		return nil
 	}
 
@@ -0,0 +1,84 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package main
+// @Author: Jianming Que
+// @Description:
+//	1. 本命令行工具用于离线校验 MIR 的审计日志，检查哈希链是否完整以及每条记录的签名是否有效
+// @Version: 1.0.0
+// @Date: 2026/10/19 2:10 AM
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package main
+
+import (
+	"flag"
+	"fmt"
+	"minlib/security"
+	"mir-go/daemon/audit"
+	"mir-go/daemon/common"
+	"os"
+)
+
+const (
+	defaultConfigFilePath = "/usr/local/etc/mir/mirconf.ini" // 默认的配置文件路径
+)
+
+var (
+	// 参数
+	configFilePath = ""
+	auditLogPath   = ""
+	identityName   = ""
+)
+
+func init() {
+	flag.StringVar(&configFilePath, "c", defaultConfigFilePath, "config file path for MIR")
+	flag.StringVar(&auditLogPath, "f", "", "audit log file path (default AuditLogPath in config file)")
+	flag.StringVar(&identityName, "i", "", "identity that signed the audit log (default DefaultId in config file)")
+}
+
+func main() {
+	flag.Parse()
+
+	mirConfig, err := common.ParseConfig(configFilePath)
+	if err != nil {
+		fmt.Println("Parse config file failed:", err)
+		os.Exit(1)
+	}
+	if auditLogPath == "" {
+		auditLogPath = mirConfig.AuditLogPath
+	}
+	if identityName == "" {
+		identityName = mirConfig.GeneralConfig.DefaultId
+	}
+
+	// 审计日志使用路由器的网络身份签名，校验时只需要其公钥，不需要输入密码
+	keyChain := new(security.KeyChain)
+	if err := keyChain.InitialKeyChainByPath(mirConfig.IdentityDBPath); err != nil {
+		fmt.Println("Open identity database failed:", err)
+		os.Exit(1)
+	}
+	identity := keyChain.GetIdentityByName(identityName)
+	if identity == nil {
+		fmt.Println("Identity", identityName, "not exists")
+		os.Exit(1)
+	}
+
+	verified, err := audit.VerifyAuditLogFile(auditLogPath, audit.CreateIdentityAuditVerifier(identity))
+	if err != nil {
+		fmt.Println("Audit log verification failed after", verified, "records:", err)
+		os.Exit(1)
+	}
+	fmt.Println("Audit log verified,", verified, "records")
+}
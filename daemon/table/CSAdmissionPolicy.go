@@ -0,0 +1,407 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 6:30 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package table
+
+import (
+	"fmt"
+	"github.com/bluele/gcache"
+	"math/rand"
+	"minlib/component"
+	"minlib/packet"
+	"mir-go/daemon/common"
+	"strings"
+	"sync"
+)
+
+const (
+	CSAdmissionPolicyAdmitAll      = "admit-all"     // 缓存所有数据包
+	CSAdmissionPolicyProbabilistic = "probabilistic" // 以一定的概率缓存数据包
+	CSAdmissionPolicyPrefix        = "prefix"        // 根据数据包的标识是否在前缀白名单或者黑名单中决定是否缓存
+	CSAdmissionPolicySecondHit     = "second-hit"    // 只缓存被请求过至少两次的数据包
+
+	DefaultCSAdmissionProbability  = 0.1   // probabilistic 策略默认的缓存概率
+	DefaultCSSecondHitHistorySize  = 10000 // second-hit 策略默认最多记录的请求标识的个数
+	csSecondHitAdmissionRequestNum = 2     // second-hit 策略中数据包被缓存之前至少需要被请求的次数
+	csSecondHitDefaultPendingTime  = 4000  // 兴趣包没有设置生存期时，second-hit 策略认为其未决的时间，单位 ms
+)
+
+// ICSAdmissionPolicy
+// CS 的准入策略，决定一个数据包是否可以被缓存
+//
+// @Description:
+//  准入策略在缓存替换策略之前起作用：不被准入的数据包不会被插入 CS ，也就不会挤占已经缓存的数据包，可以用来保护缓存免受只会被请求一次的流量的冲击
+//
+type ICSAdmissionPolicy interface {
+	// GetName 获取准入策略的名字
+	GetName() string
+
+	// AfterLookupMiss 一个兴趣包查找 CS 未命中之后调用，准入策略可以据此统计请求
+	AfterLookupMiss(interest *packet.Interest)
+
+	// Admit 判断一个数据包是否可以被缓存
+	Admit(data *packet.Data) bool
+}
+
+// CSAdmissionPolicyConfig
+// 创建准入策略所需的配置
+//
+// @Description:
+//
+type CSAdmissionPolicyConfig struct {
+	Name          string   // 准入策略的名字 admit-all | probabilistic | prefix | second-hit
+	Probability   float64  // probabilistic 策略的缓存概率，取值范围 (0, 1]
+	AllowPrefixes []string // prefix 策略的前缀白名单，为空时表示允许所有前缀
+	DenyPrefixes  []string // prefix 策略的前缀黑名单，优先于白名单
+	HistorySize   int      // second-hit 策略最多记录的请求标识的个数
+}
+
+// CreateCSAdmissionPolicy
+// 根据配置创建一个准入策略
+//
+// @Description:
+//  名字为空时使用 admit-all
+// @param config
+// @return ICSAdmissionPolicy
+// @return error
+//
+func CreateCSAdmissionPolicy(config *CSAdmissionPolicyConfig) (ICSAdmissionPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(config.Name)) {
+	case "", CSAdmissionPolicyAdmitAll:
+		return CreateAdmitAllCSAdmissionPolicy(), nil
+	case CSAdmissionPolicyProbabilistic:
+		return CreateProbabilisticCSAdmissionPolicy(config.Probability)
+	case CSAdmissionPolicyPrefix:
+		return CreatePrefixCSAdmissionPolicy(config.AllowPrefixes, config.DenyPrefixes)
+	case CSAdmissionPolicySecondHit:
+		return CreateSecondHitCSAdmissionPolicy(config.HistorySize)
+	default:
+		return nil, createCSAdmissionPolicyErrorByType(UnknownCSAdmissionPolicyError, config.Name)
+	}
+}
+
+// AdmitAllCSAdmissionPolicy
+// 缓存所有数据包的准入策略
+//
+// @Description:
+//
+type AdmitAllCSAdmissionPolicy struct {
+}
+
+// CreateAdmitAllCSAdmissionPolicy
+// 创建一个缓存所有数据包的准入策略
+//
+// @Description:
+// @return *AdmitAllCSAdmissionPolicy
+//
+func CreateAdmitAllCSAdmissionPolicy() *AdmitAllCSAdmissionPolicy {
+	return &AdmitAllCSAdmissionPolicy{}
+}
+
+func (a *AdmitAllCSAdmissionPolicy) GetName() string {
+	return CSAdmissionPolicyAdmitAll
+}
+
+func (a *AdmitAllCSAdmissionPolicy) AfterLookupMiss(interest *packet.Interest) {
+}
+
+func (a *AdmitAllCSAdmissionPolicy) Admit(data *packet.Data) bool {
+	return true
+}
+
+// ProbabilisticCSAdmissionPolicy
+// 以一定的概率缓存数据包的准入策略
+//
+// @Description:
+//
+type ProbabilisticCSAdmissionPolicy struct {
+	probability float64 // 缓存概率
+}
+
+// CreateProbabilisticCSAdmissionPolicy
+// 创建一个以 probability 的概率缓存数据包的准入策略
+//
+// @Description:
+// @param probability		缓存概率，取值范围 (0, 1]
+// @return *ProbabilisticCSAdmissionPolicy
+// @return error
+//
+func CreateProbabilisticCSAdmissionPolicy(probability float64) (*ProbabilisticCSAdmissionPolicy, error) {
+	if probability <= 0 || probability > 1 {
+		return nil, createCSAdmissionPolicyErrorByType(InvalidCSAdmissionProbabilityError, fmt.Sprint(probability))
+	}
+	return &ProbabilisticCSAdmissionPolicy{probability: probability}, nil
+}
+
+func (p *ProbabilisticCSAdmissionPolicy) GetName() string {
+	return CSAdmissionPolicyProbabilistic
+}
+
+func (p *ProbabilisticCSAdmissionPolicy) AfterLookupMiss(interest *packet.Interest) {
+}
+
+func (p *ProbabilisticCSAdmissionPolicy) Admit(data *packet.Data) bool {
+	return rand.Float64() < p.probability
+}
+
+// GetProbability
+// 获取缓存概率
+//
+// @Description:
+// @receiver p
+// @return float64
+//
+func (p *ProbabilisticCSAdmissionPolicy) GetProbability() float64 {
+	return p.probability
+}
+
+// PrefixCSAdmissionPolicy
+// 根据前缀白名单和黑名单缓存数据包的准入策略
+//
+// @Description:
+//  数据包的标识在黑名单中任意一个前缀之下时不缓存；否则白名单为空，或者数据包的标识在白名单中任意一个前缀之下时缓存
+//
+type PrefixCSAdmissionPolicy struct {
+	allowPrefixes [][]string // 前缀白名单，每个前缀为一个组件列表
+	denyPrefixes  [][]string // 前缀黑名单，每个前缀为一个组件列表
+}
+
+// CreatePrefixCSAdmissionPolicy
+// 创建一个根据前缀白名单和黑名单缓存数据包的准入策略
+//
+// @Description:
+// @param allowPrefixes		前缀白名单，为空时表示允许所有前缀
+// @param denyPrefixes		前缀黑名单
+// @return *PrefixCSAdmissionPolicy
+// @return error
+//
+func CreatePrefixCSAdmissionPolicy(allowPrefixes []string, denyPrefixes []string) (*PrefixCSAdmissionPolicy, error) {
+	allow, err := parseCSAdmissionPrefixes(allowPrefixes)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := parseCSAdmissionPrefixes(denyPrefixes)
+	if err != nil {
+		return nil, err
+	}
+	return &PrefixCSAdmissionPolicy{allowPrefixes: allow, denyPrefixes: deny}, nil
+}
+
+func (p *PrefixCSAdmissionPolicy) GetName() string {
+	return CSAdmissionPolicyPrefix
+}
+
+func (p *PrefixCSAdmissionPolicy) AfterLookupMiss(interest *packet.Interest) {
+}
+
+func (p *PrefixCSAdmissionPolicy) Admit(data *packet.Data) bool {
+	components := getCSNameComponents(data.GetName())
+	if matchCSAdmissionPrefixes(p.denyPrefixes, components) {
+		return false
+	}
+	return len(p.allowPrefixes) == 0 || matchCSAdmissionPrefixes(p.allowPrefixes, components)
+}
+
+// SecondHitCSAdmissionPolicy
+// 只缓存被请求过至少两次的数据包的准入策略
+//
+// @Description:
+//  1. 记录最近查找 CS 未命中的兴趣包的名字及其请求次数（按照 LRU 淘汰），数据包被请求过至少两次时才缓存，
+//     所以只被请求一次的数据包不会挤占缓存；
+//  2. 兴趣包和数据包使用同一种键：CanBePrefix = false 的兴趣包记录在其名字上，CanBePrefix = true 的兴趣包记录在其名字作为前缀时的键上，
+//     判断数据包时累加其标识以及标识的每个前缀被前缀兴趣包请求的次数；
+//  3. 一次请求在兴趣包的生存期内（或者数据包到达之前）是未决的，这期间到达的同名兴趣包是重传，不会被重复计数。
+//
+type SecondHitCSAdmissionPolicy struct {
+	history gcache.Cache // 请求的键 => *csSecondHitRecord
+	lock    sync.Mutex   // 多个转发分片会并发的统计请求和判断数据包，这边串行化对请求记录的修改
+}
+
+// csSecondHitRecord second-hit 策略中一个键的请求记录
+type csSecondHitRecord struct {
+	count        int    // 请求次数
+	pendingUntil uint64 // 最近一次请求未决的截止时间，单位 ms ，数据包到达之后清零
+}
+
+// CreateSecondHitCSAdmissionPolicy
+// 创建一个只缓存被请求过至少两次的数据包的准入策略
+//
+// @Description:
+// @param historySize		最多记录的请求标识的个数，小于等于0时使用默认值
+// @return *SecondHitCSAdmissionPolicy
+// @return error
+//
+func CreateSecondHitCSAdmissionPolicy(historySize int) (*SecondHitCSAdmissionPolicy, error) {
+	if historySize <= 0 {
+		historySize = DefaultCSSecondHitHistorySize
+	}
+	return &SecondHitCSAdmissionPolicy{history: gcache.New(historySize).LRU().Build()}, nil
+}
+
+func (s *SecondHitCSAdmissionPolicy) GetName() string {
+	return CSAdmissionPolicySecondHit
+}
+
+func (s *SecondHitCSAdmissionPolicy) AfterLookupMiss(interest *packet.Interest) {
+	key := makeCSSecondHitKey(getCSNameComponents(interest.GetName()), interest.GetCanBePrefix())
+	now := common.GetCurrentTime()
+	lifetime := interest.InterestLifeTime.GetInterestLifeTime()
+	if lifetime == 0 {
+		lifetime = csSecondHitDefaultPendingTime
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	record := &csSecondHitRecord{}
+	if value, err := s.history.Get(key); err == nil {
+		record = value.(*csSecondHitRecord)
+	}
+	if now < record.pendingUntil {
+		// 上一次请求还是未决的，这是一个重传
+		return
+	}
+	record.count++
+	record.pendingUntil = now + lifetime
+	_ = s.history.Set(key, record)
+}
+
+func (s *SecondHitCSAdmissionPolicy) Admit(data *packet.Data) bool {
+	components := getCSNameComponents(data.GetName())
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	count := 0
+	keys := []string{makeCSSecondHitKey(components, false)}
+	for i := len(components); i >= 0; i-- {
+		keys = append(keys, makeCSSecondHitKey(components[:i], true))
+	}
+	for _, key := range keys {
+		if value, err := s.history.Get(key); err == nil {
+			record := value.(*csSecondHitRecord)
+			count += record.count
+			// 数据包到达之后请求不再是未决的，之后同名的兴趣包是新的请求
+			record.pendingUntil = 0
+		}
+	}
+	return count >= csSecondHitAdmissionRequestNum
+}
+
+//
+// 获取 second-hit 策略中请求的键
+//
+// @Description:
+// @param components		兴趣包的名字或者数据包的标识的组件列表
+// @param canBePrefix		是否是前缀请求
+// @return string
+//
+func makeCSSecondHitKey(components []string, canBePrefix bool) string {
+	key := "/" + strings.Join(components, "/")
+	if canBePrefix {
+		return "prefix:" + key
+	}
+	return "exact:" + key
+}
+
+//
+// 将前缀列表解析成组件列表
+//
+// @Description:
+// @param prefixes
+// @return [][]string
+// @return error
+//
+func parseCSAdmissionPrefixes(prefixes []string) ([][]string, error) {
+	result := make([][]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" {
+			continue
+		}
+		identifier, err := component.CreateIdentifierByString(prefix)
+		if err != nil {
+			return nil, createCSAdmissionPolicyErrorByType(InvalidCSAdmissionPrefixError, prefix)
+		}
+		result = append(result, getCSNameComponents(identifier))
+	}
+	return result, nil
+}
+
+//
+// 判断组件列表是否在任意一个前缀之下
+//
+// @Description:
+// @param prefixes
+// @param components
+// @return bool
+//
+func matchCSAdmissionPrefixes(prefixes [][]string, components []string) bool {
+	for _, prefix := range prefixes {
+		if len(prefix) > len(components) {
+			continue
+		}
+		matched := true
+		for i := range prefix {
+			if prefix[i] != components[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+///// 错误处理
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	UnknownCSAdmissionPolicyError = iota
+	InvalidCSAdmissionProbabilityError
+	InvalidCSAdmissionPrefixError
+	DataNotAdmittedError
+)
+
+type CSAdmissionPolicyError struct {
+	msg string
+}
+
+func (c CSAdmissionPolicyError) Error() string {
+	return fmt.Sprintf("CSAdmissionPolicyError: %s", c.msg)
+}
+
+func createCSAdmissionPolicyErrorByType(errorType int, detail string) (err CSAdmissionPolicyError) {
+	switch errorType {
+	case UnknownCSAdmissionPolicyError:
+		err.msg = fmt.Sprintf("unknown admission policy: %s, require: %s, %s, %s, %s", detail, CSAdmissionPolicyAdmitAll,
+			CSAdmissionPolicyProbabilistic, CSAdmissionPolicyPrefix, CSAdmissionPolicySecondHit)
+	case InvalidCSAdmissionProbabilityError:
+		err.msg = fmt.Sprintf("invalid admission probability: %s, require: (0, 1]", detail)
+	case InvalidCSAdmissionPrefixError:
+		err.msg = fmt.Sprintf("invalid admission prefix: %s", detail)
+	case DataNotAdmittedError:
+		err.msg = fmt.Sprintf("data not admitted by %s policy", detail)
+	default:
+		err.msg = "Unknown error"
+	}
+	return
+}
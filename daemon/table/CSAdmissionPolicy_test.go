@@ -0,0 +1,100 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 6:40 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package table
+
+import (
+	"fmt"
+	"minlib/packet"
+	"testing"
+)
+
+func TestPrefixCSAdmissionPolicy_Admit(t *testing.T) {
+	policy, err := CreatePrefixCSAdmissionPolicy([]string{"/video"}, []string{"/video/live"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !policy.Admit(createTestCSData("/video/movie/seg1", 1000)) {
+		t.Fatal("expect /video/movie/seg1 admitted")
+	}
+	if policy.Admit(createTestCSData("/video/live/seg1", 1000)) {
+		t.Fatal("expect /video/live/seg1 denied")
+	}
+	if policy.Admit(createTestCSData("/videos/seg1", 1000)) {
+		t.Fatal("expect /videos/seg1 not in allow list")
+	}
+}
+
+func TestSecondHitCSAdmissionPolicy_Admit(t *testing.T) {
+	policy, _ := CreateSecondHitCSAdmissionPolicy(0)
+	data := createTestCSData("/min/once", 1000)
+	interest := &packet.Interest{}
+	interest.SetName(data.GetName())
+
+	policy.AfterLookupMiss(interest)
+	// 未决期间到达的重传不计数
+	policy.AfterLookupMiss(interest)
+	if policy.Admit(data) {
+		t.Fatal("expect data requested once not admitted")
+	}
+	// 数据包到达之后，同名的兴趣包是新的请求
+	policy.AfterLookupMiss(interest)
+	if !policy.Admit(data) {
+		t.Fatal("expect data requested twice admitted")
+	}
+}
+
+func TestSecondHitCSAdmissionPolicy_CanBePrefix(t *testing.T) {
+	policy, _ := CreateSecondHitCSAdmissionPolicy(0)
+	data := createTestCSData("/video/seg/v2", 1000)
+	prefixInterest := &packet.Interest{}
+	prefixInterest.SetName(createTestCSData("/video/seg", 1000).GetName())
+	prefixInterest.SetCanBePrefix(true)
+	exactInterest := &packet.Interest{}
+	exactInterest.SetName(createTestCSData("/video/seg", 1000).GetName())
+
+	// 精确匹配的兴趣包不能统计到更长的数据包标识上
+	policy.AfterLookupMiss(exactInterest)
+	policy.AfterLookupMiss(prefixInterest)
+	if policy.Admit(data) {
+		t.Fatal("expect data requested once by prefix not admitted")
+	}
+	// 前缀兴趣包和数据包使用同一种键
+	policy.AfterLookupMiss(prefixInterest)
+	if !policy.Admit(data) {
+		t.Fatal("expect data requested twice by prefix admitted")
+	}
+}
+
+func TestCreateCSAdmissionPolicy(t *testing.T) {
+	if _, err := CreateCSAdmissionPolicy(&CSAdmissionPolicyConfig{Name: "unknown"}); err == nil {
+		t.Fatal("expect unknown policy error")
+	}
+	if _, err := CreateCSAdmissionPolicy(&CSAdmissionPolicyConfig{Name: CSAdmissionPolicyProbabilistic, Probability: 1.5}); err == nil {
+		t.Fatal("expect invalid probability error")
+	}
+	policy, err := CreateCSAdmissionPolicy(&CSAdmissionPolicyConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(policy.GetName())
+}
@@ -0,0 +1,645 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 7:10 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package table
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"hash/crc32"
+	"io"
+	common2 "minlib/common"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const (
+	csDiskSegmentFileName = "cs.seg"         // 段文件的文件名
+	csDiskCompactFileName = "cs.seg.compact" // 压缩时临时段文件的文件名
+	csDiskRecordHeaderLen = 19               // 记录头的长度 crc32(4) | op(1) | staleTime(8) | keyLen(2) | valueLen(4)
+	csDiskCompactMinBytes = 1 << 20          // 段文件中无效的字节数超过该值并且超过有效的字节数时才压缩
+	csDiskMaxKeyLen       = 1<<16 - 1        // 键的最大长度
+
+	csDiskOpPut    byte = 1 // 写入一个条目
+	csDiskOpDelete byte = 2 // 删除一个条目（墓碑记录）
+)
+
+// CSDiskRecord
+// 磁盘存储中的一个条目
+//
+// @Description:
+//
+type CSDiskRecord struct {
+	Key       string // 条目的键，为数据包标识的 URI
+	StaleTime int64  // 条目的不新鲜时间，单位 ms
+	offset    int64  // 记录在段文件中的偏移
+	length    int64  // 记录的总长度，包括记录头
+}
+
+// ValueSize
+// 获取条目的值的长度
+//
+// @Description:
+// @receiver r
+// @return int64
+//
+func (r *CSDiskRecord) ValueSize() int64 {
+	return r.length - csDiskRecordHeaderLen - int64(len(r.Key))
+}
+
+// CSDiskStore
+// CS 的磁盘存储，由一个只追加的段文件和一个内存中的索引组成
+//
+// @Description:
+//  1. 每次写入或者删除都在段文件的末尾追加一条记录：crc32(4) | op(1) | staleTime(8) | keyLen(2) | valueLen(4) | key | value ，
+//     其中 crc32 校验记录中除自身以外的所有字节；
+//  2. 索引记录每个有效条目在段文件中的位置，打开时按顺序扫描段文件重建，末尾不完整或者校验失败的记录（例如进程崩溃时写了一半）会被截断；
+//  3. 段文件中无效的字节（被覆盖或者被删除的条目）超过有效的字节时，由后台协程将有效的记录按顺序重写到新的段文件中以回收空间，
+//     重写期间仍然可以读写，重写期间追加的记录在切换段文件时复制到新的段文件末尾；
+//  4. CSDiskStore 是线程安全的：lock 保护索引并串行化追加，fileLock 保护段文件的切换，读取时只持有 fileLock 的读锁，
+//     所以读取不会被追加阻塞。加锁的顺序总是先 fileLock 再 lock 。
+//
+type CSDiskStore struct {
+	dir        string                   // 存储目录
+	file       *os.File                 // 段文件
+	fileSize   int64                    // 段文件的长度
+	liveBytes  int64                    // 有效的记录的总长度
+	valueSize  int64                    // 有效的条目的值的总长度
+	records    map[string]*CSDiskRecord // 索引，键 => 有效的记录
+	compacting bool                     // 是否有后台协程正在重写段文件
+	compactWg  sync.WaitGroup           // 等待后台重写结束
+	lock       sync.Mutex
+	fileLock   sync.RWMutex
+}
+
+// OpenCSDiskStore
+// 打开（不存在时创建）目录 dir 下的磁盘存储
+//
+// @Description:
+// @param dir
+// @return *CSDiskStore
+// @return error
+//
+func OpenCSDiskStore(dir string) (*CSDiskStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	store := &CSDiskStore{
+		dir:     dir,
+		records: make(map[string]*CSDiskRecord),
+	}
+	file, err := os.OpenFile(store.segmentPath(), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	store.file = file
+	if err := store.load(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// Put
+// 写入一个条目，已经存在的条目会被覆盖
+//
+// @Description:
+// @receiver s
+// @param key
+// @param staleTime
+// @param value
+// @return error
+//
+func (s *CSDiskStore) Put(key string, staleTime int64, value []byte) error {
+	if len(key) > csDiskMaxKeyLen {
+		return createCSDiskStoreErrorByType(CSDiskKeyTooLongError, key)
+	}
+	s.fileLock.RLock()
+	defer s.fileLock.RUnlock()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	record, err := s.append(csDiskOpPut, key, staleTime, value)
+	if err != nil {
+		return err
+	}
+	s.removeRecord(key)
+	s.records[key] = record
+	s.liveBytes += record.length
+	s.valueSize += record.ValueSize()
+	s.compactIfNeeded()
+	return nil
+}
+
+// Get
+// 读取一个条目的值
+//
+// @Description:
+// @receiver s
+// @param key
+// @return []byte
+// @return error
+//
+func (s *CSDiskStore) Get(key string) ([]byte, error) {
+	// 持有 fileLock 的读锁期间段文件不会被切换，记录的偏移一直有效
+	s.fileLock.RLock()
+	defer s.fileLock.RUnlock()
+	s.lock.Lock()
+	record, ok := s.records[key]
+	var offset, length int64
+	if ok {
+		offset, length = record.offset, record.length
+	}
+	s.lock.Unlock()
+	if !ok {
+		return nil, createCSDiskStoreErrorByType(CSDiskRecordNotFoundError, key)
+	}
+	buf := make([]byte, length)
+	if _, err := s.file.ReadAt(buf, offset); err != nil {
+		return nil, err
+	}
+	_, recordKey, _, value, ok := parseCSDiskRecord(buf)
+	if !ok || recordKey != key {
+		return nil, createCSDiskStoreErrorByType(CSDiskRecordCorruptedError, key)
+	}
+	return value, nil
+}
+
+// Delete
+// 删除一个条目，不存在时什么也不做
+//
+// @Description:
+// @receiver s
+// @param key
+// @return error
+//
+func (s *CSDiskStore) Delete(key string) error {
+	s.fileLock.RLock()
+	defer s.fileLock.RUnlock()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.records[key]; !ok {
+		return nil
+	}
+	if _, err := s.append(csDiskOpDelete, key, 0, nil); err != nil {
+		return err
+	}
+	s.removeRecord(key)
+	s.compactIfNeeded()
+	return nil
+}
+
+// Records
+// 获取所有有效的条目，按照写入的先后顺序排列
+//
+// @Description:
+// @receiver s
+// @return []*CSDiskRecord
+//
+func (s *CSDiskStore) Records() []*CSDiskRecord {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.sortedRecords()
+}
+
+//
+// 获取所有有效的条目，按照在段文件中的偏移排列
+//
+// @Description:
+//  只会在持有 lock 时调用
+// @receiver s
+// @return []*CSDiskRecord
+//
+func (s *CSDiskStore) sortedRecords() []*CSDiskRecord {
+	records := make([]*CSDiskRecord, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].offset < records[j].offset
+	})
+	return records
+}
+
+// Count
+// 获取有效的条目的个数
+//
+// @Description:
+// @receiver s
+// @return int
+//
+func (s *CSDiskStore) Count() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.records)
+}
+
+// Size
+// 获取有效的条目的值的总长度，单位 Byte
+//
+// @Description:
+// @receiver s
+// @return int64
+//
+func (s *CSDiskStore) Size() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.valueSize
+}
+
+// Close
+// 等待后台重写结束，将段文件刷到磁盘并关闭
+//
+// @Description:
+// @receiver s
+// @return error
+//
+func (s *CSDiskStore) Close() error {
+	s.compactWg.Wait()
+	s.fileLock.Lock()
+	defer s.fileLock.Unlock()
+	if err := s.file.Sync(); err != nil {
+		_ = s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
+
+//
+// 按顺序扫描段文件重建索引，截断末尾不完整或者校验失败的记录
+//
+// @Description:
+//  只在打开时调用。记录头中的长度超过段文件剩余的字节数时，认为是末尾不完整的记录，不会按照损坏的长度分配内存
+// @receiver s
+// @return error
+//
+func (s *CSDiskStore) load() error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(s.file)
+	header := make([]byte, csDiskRecordHeaderLen)
+	var offset int64
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			break
+		}
+		keyLen := int64(binary.BigEndian.Uint16(header[13:15]))
+		valueLen := int64(binary.BigEndian.Uint32(header[15:19]))
+		if keyLen+valueLen > info.Size()-offset-csDiskRecordHeaderLen {
+			break
+		}
+		buf := make([]byte, csDiskRecordHeaderLen+keyLen+valueLen)
+		copy(buf, header)
+		if _, err := io.ReadFull(reader, buf[csDiskRecordHeaderLen:]); err != nil {
+			break
+		}
+		op, key, staleTime, _, ok := parseCSDiskRecord(buf)
+		if !ok {
+			break
+		}
+		s.removeRecord(key)
+		if op == csDiskOpPut {
+			record := &CSDiskRecord{Key: key, StaleTime: staleTime, offset: offset, length: int64(len(buf))}
+			s.records[key] = record
+			s.liveBytes += record.length
+			s.valueSize += record.ValueSize()
+		}
+		offset += int64(len(buf))
+	}
+	s.fileSize = offset
+	if err := s.file.Truncate(offset); err != nil {
+		return err
+	}
+	_, err = s.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+//
+// 在段文件的末尾追加一条记录
+//
+// @Description:
+//  只会在持有 fileLock 的读锁和 lock 时调用
+// @receiver s
+// @param op
+// @param key
+// @param staleTime
+// @param value
+// @return *CSDiskRecord
+// @return error
+//
+func (s *CSDiskStore) append(op byte, key string, staleTime int64, value []byte) (*CSDiskRecord, error) {
+	buf := encodeCSDiskRecord(op, key, staleTime, value)
+	if _, err := s.file.WriteAt(buf, s.fileSize); err != nil {
+		return nil, err
+	}
+	record := &CSDiskRecord{Key: key, StaleTime: staleTime, offset: s.fileSize, length: int64(len(buf))}
+	s.fileSize += record.length
+	return record, nil
+}
+
+//
+// 从索引中删除一个条目
+//
+// @Description:
+//  只会在持有 lock 时调用
+// @receiver s
+// @param key
+//
+func (s *CSDiskStore) removeRecord(key string) {
+	if record, ok := s.records[key]; ok {
+		delete(s.records, key)
+		s.liveBytes -= record.length
+		s.valueSize -= record.ValueSize()
+	}
+}
+
+//
+// 段文件中无效的字节过多时，启动一个后台协程重写段文件
+//
+// @Description:
+//  只会在持有 lock 时调用，同一时刻最多只有一个后台协程在重写
+// @receiver s
+//
+func (s *CSDiskStore) compactIfNeeded() {
+	if s.compacting || !s.needCompact() {
+		return
+	}
+	s.compacting = true
+	s.compactWg.Add(1)
+	go s.compactLoop()
+}
+
+//
+// 段文件中无效的字节数是否超过阈值并且超过有效的字节数
+//
+// @Description:
+//  只会在持有 lock 时调用
+// @receiver s
+// @return bool
+//
+func (s *CSDiskStore) needCompact() bool {
+	garbage := s.fileSize - s.liveBytes
+	return garbage >= csDiskCompactMinBytes && garbage > s.liveBytes
+}
+
+//
+// 后台重写段文件，直到无效的字节数不再超过阈值
+//
+// @Description:
+//  重写期间追加的记录可能很快又产生大量无效的字节，所以重写结束之后再检查一次
+// @receiver s
+//
+func (s *CSDiskStore) compactLoop() {
+	defer s.compactWg.Done()
+	for {
+		if err := s.compact(); err != nil {
+			common2.LogErrorWithFields(logrus.Fields{
+				"dir":   s.dir,
+				"error": err,
+			}, "compact cs segment file failed")
+		}
+		s.lock.Lock()
+		if !s.needCompact() {
+			s.compacting = false
+			s.lock.Unlock()
+			return
+		}
+		s.lock.Unlock()
+	}
+}
+
+//
+// 将有效的记录按顺序重写到新的段文件中
+//
+// @Description:
+//  1. 先只持有 fileLock 的读锁，把开始时有效的记录复制到临时文件中，这期间读取和追加都可以继续；
+//  2. 然后持有 fileLock 的写锁，把开始之后追加的记录原样复制到临时文件末尾，刷到磁盘之后替换原来的段文件，并刷新目录，
+//     所以重写过程中崩溃不会丢失数据；
+//  3. 最后更新索引中的偏移：开始时有效的记录使用复制之后的偏移，开始之后追加的记录按照末尾的偏移平移。
+// @receiver s
+// @return error
+//
+func (s *CSDiskStore) compact() error {
+	compactPath := filepath.Join(s.dir, csDiskCompactFileName)
+	compactFile, err := os.OpenFile(compactPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	s.fileLock.RLock()
+	s.lock.Lock()
+	records := s.sortedRecords()
+	snapshot := make(map[*CSDiskRecord]int64, len(records))
+	snapshotOffsets := make([]int64, len(records))
+	for i, record := range records {
+		snapshotOffsets[i] = record.offset
+	}
+	snapshotSize := s.fileSize
+	s.lock.Unlock()
+	var offset int64
+	for i, record := range records {
+		buf := make([]byte, record.length)
+		if _, err = s.file.ReadAt(buf, snapshotOffsets[i]); err != nil {
+			break
+		}
+		if _, err = compactFile.WriteAt(buf, offset); err != nil {
+			break
+		}
+		snapshot[record] = offset
+		offset += record.length
+	}
+	s.fileLock.RUnlock()
+	if err != nil {
+		_ = compactFile.Close()
+		_ = os.Remove(compactPath)
+		return err
+	}
+
+	s.fileLock.Lock()
+	defer s.fileLock.Unlock()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tailOffset := offset
+	if tailSize := s.fileSize - snapshotSize; tailSize > 0 {
+		buf := make([]byte, tailSize)
+		if _, err = s.file.ReadAt(buf, snapshotSize); err == nil {
+			_, err = compactFile.WriteAt(buf, tailOffset)
+		}
+		offset += tailSize
+	}
+	if err == nil {
+		err = compactFile.Sync()
+	}
+	if err == nil {
+		err = os.Rename(compactPath, s.segmentPath())
+	}
+	if err == nil {
+		err = syncCSDiskDir(s.dir)
+	}
+	if err != nil {
+		_ = compactFile.Close()
+		_ = os.Remove(compactPath)
+		return err
+	}
+
+	// 切换到新的段文件，并更新索引中的偏移
+	_ = s.file.Close()
+	s.file = compactFile
+	for _, record := range s.records {
+		if record.offset >= snapshotSize {
+			record.offset = tailOffset + record.offset - snapshotSize
+		} else {
+			record.offset = snapshot[record]
+		}
+	}
+	s.fileSize = offset
+	return nil
+}
+
+//
+// 等待后台重写结束
+//
+// @Description:
+// @receiver s
+//
+func (s *CSDiskStore) waitCompaction() {
+	s.compactWg.Wait()
+}
+
+//
+// 获取段文件的路径
+//
+// @Description:
+// @receiver s
+// @return string
+//
+func (s *CSDiskStore) segmentPath() string {
+	return filepath.Join(s.dir, csDiskSegmentFileName)
+}
+
+//
+// 将目录刷到磁盘，使得目录中文件的重命名持久化
+//
+// @Description:
+// @param dir
+// @return error
+//
+func syncCSDiskDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return file.Sync()
+}
+
+//
+// 将一条记录编码成字节数组
+//
+// @Description:
+// @param op
+// @param key
+// @param staleTime
+// @param value
+// @return []byte
+//
+func encodeCSDiskRecord(op byte, key string, staleTime int64, value []byte) []byte {
+	buf := make([]byte, csDiskRecordHeaderLen+len(key)+len(value))
+	buf[4] = op
+	binary.BigEndian.PutUint64(buf[5:13], uint64(staleTime))
+	binary.BigEndian.PutUint16(buf[13:15], uint16(len(key)))
+	binary.BigEndian.PutUint32(buf[15:19], uint32(len(value)))
+	copy(buf[csDiskRecordHeaderLen:], key)
+	copy(buf[csDiskRecordHeaderLen+len(key):], value)
+	binary.BigEndian.PutUint32(buf[0:4], crc32.ChecksumIEEE(buf[4:]))
+	return buf
+}
+
+//
+// 解析一条完整的记录
+//
+// @Description:
+// @param buf
+// @return op
+// @return key
+// @return staleTime
+// @return value
+// @return ok		长度或者校验和不正确时为 false
+//
+func parseCSDiskRecord(buf []byte) (op byte, key string, staleTime int64, value []byte, ok bool) {
+	if len(buf) < csDiskRecordHeaderLen {
+		return
+	}
+	keyLen := int(binary.BigEndian.Uint16(buf[13:15]))
+	valueLen := int(binary.BigEndian.Uint32(buf[15:19]))
+	if len(buf) != csDiskRecordHeaderLen+keyLen+valueLen || binary.BigEndian.Uint32(buf[0:4]) != crc32.ChecksumIEEE(buf[4:]) {
+		return
+	}
+	op = buf[4]
+	if op != csDiskOpPut && op != csDiskOpDelete {
+		return
+	}
+	staleTime = int64(binary.BigEndian.Uint64(buf[5:13]))
+	key = string(buf[csDiskRecordHeaderLen : csDiskRecordHeaderLen+keyLen])
+	value = buf[csDiskRecordHeaderLen+keyLen:]
+	ok = true
+	return
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+///// 错误处理
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	CSDiskRecordNotFoundError = iota
+	CSDiskRecordCorruptedError
+	CSDiskKeyTooLongError
+)
+
+type CSDiskStoreError struct {
+	msg string
+}
+
+func (c CSDiskStoreError) Error() string {
+	return fmt.Sprintf("CSDiskStoreError: %s", c.msg)
+}
+
+func createCSDiskStoreErrorByType(errorType int, key string) (err CSDiskStoreError) {
+	switch errorType {
+	case CSDiskRecordNotFoundError:
+		err.msg = fmt.Sprintf("record not found: %s", key)
+	case CSDiskRecordCorruptedError:
+		err.msg = fmt.Sprintf("record corrupted: %s", key)
+	case CSDiskKeyTooLongError:
+		err.msg = fmt.Sprintf("key too long: %d bytes", len(key))
+	default:
+		err.msg = "Unknown error"
+	}
+	return
+}
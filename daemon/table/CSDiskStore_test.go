@@ -0,0 +1,137 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 8:10 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package table
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCSDiskStore_Reopen(t *testing.T) {
+	dir, err := os.MkdirTemp("", "cs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := OpenCSDiskStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = store.Put("/min/a", 100, []byte("aaa"))
+	_ = store.Put("/min/b", 200, []byte("bbbb"))
+	_ = store.Put("/min/a", 300, []byte("a"))
+	_ = store.Delete("/min/b")
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 模拟进程崩溃时写了一半的记录
+	file, _ := os.OpenFile(filepath.Join(dir, csDiskSegmentFileName), os.O_WRONLY|os.O_APPEND, 0644)
+	_, _ = file.Write(encodeCSDiskRecord(csDiskOpPut, "/min/c", 400, []byte("ccc"))[:10])
+	_ = file.Close()
+
+	store, err = OpenCSDiskStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if store.Count() != 1 || store.Size() != 1 {
+		t.Fatal("expect only /min/a recovered, got", store.Count(), store.Size())
+	}
+	value, err := store.Get("/min/a")
+	if err != nil || string(value) != "a" || store.Records()[0].StaleTime != 300 {
+		t.Fatal("expect latest /min/a recovered", string(value), err)
+	}
+	if _, err := store.Get("/min/b"); err == nil {
+		t.Fatal("expect /min/b deleted")
+	}
+}
+
+func TestCSDiskStore_Compact(t *testing.T) {
+	dir, err := os.MkdirTemp("", "cs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := OpenCSDiskStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := make([]byte, 64*1024)
+	for i := 0; i < 64; i++ {
+		_ = store.Put("/min/video", int64(i), value)
+	}
+	store.waitCompaction()
+	info, _ := os.Stat(filepath.Join(dir, csDiskSegmentFileName))
+	if info.Size() > 2*csDiskCompactMinBytes {
+		t.Fatal("expect segment file compacted, size", info.Size())
+	}
+	if got, err := store.Get("/min/video"); err != nil || len(got) != len(value) {
+		t.Fatal("expect /min/video readable after compaction", err)
+	}
+	_ = store.Close()
+}
+
+func TestCSDiskStore_CorruptedLength(t *testing.T) {
+	dir, err := os.MkdirTemp("", "cs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := OpenCSDiskStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = store.Put("/min/a", 100, []byte("aaa"))
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 记录头中的长度远超过段文件剩余的字节数，应该被当作末尾不完整的记录截断，而不是按照这个长度分配内存
+	header := encodeCSDiskRecord(csDiskOpPut, "/min/b", 200, nil)[:csDiskRecordHeaderLen]
+	binary.BigEndian.PutUint16(header[13:15], 0xFFFF)
+	binary.BigEndian.PutUint32(header[15:19], 0xFFFFFFFF)
+	file, _ := os.OpenFile(filepath.Join(dir, csDiskSegmentFileName), os.O_WRONLY|os.O_APPEND, 0644)
+	_, _ = file.Write(append(header, []byte("/min/b")...))
+	_ = file.Close()
+
+	store, err = OpenCSDiskStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if store.Count() != 1 {
+		t.Fatal("expect only /min/a recovered, got", store.Count())
+	}
+	if err := store.Put("/min/c", 300, []byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := store.Get("/min/c"); err != nil || string(value) != "c" {
+		t.Fatal("expect /min/c appended after the truncated tail", err)
+	}
+}
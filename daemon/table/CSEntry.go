@@ -25,34 +25,55 @@ package table
 import (
 	"minlib/component"
 	"minlib/packet"
+	"mir-go/daemon/common"
 	"sync"
-	"time"
 )
 
 type CSEntry struct {
 	data      *packet.Data     // 数据包指针
-	StaleTime int64            // 不新鲜时间
+	StaleTime int64            // 不新鲜时间，单位 ms ，为数据包到达的时间加上数据包的 FreshnessPeriod
 	Interest  *packet.Interest // 兴趣包指针
 	RWlock    *sync.RWMutex    // 读写锁
 }
 
-// NewCSEntry 获取表项中的数据包指针
+// NewCSEntry 根据数据包创建一个表项，不新鲜时间由数据包的 FreshnessPeriod 计算得到
 func NewCSEntry(data *packet.Data) *CSEntry {
 	var c = &CSEntry{}
 	c.data = data
-	c.StaleTime = time.Now().Unix()
+	c.StaleTime = computeCSEntryStaleTime(data)
 	c.Interest = &packet.Interest{}
 	c.RWlock = new(sync.RWMutex)
 	return c
 }
 
+// newCSEntryWithStaleTime 根据数据包和已知的不新鲜时间创建一个表项，用于从磁盘中恢复表项，不新鲜时间不会被重新计算
+func newCSEntryWithStaleTime(data *packet.Data, staleTime int64) *CSEntry {
+	var c = &CSEntry{}
+	c.data = data
+	c.StaleTime = staleTime
+	c.Interest = &packet.Interest{}
+	c.RWlock = new(sync.RWMutex)
+	return c
+}
+
+// GetData 获取表项中的数据包指针
 func (c *CSEntry) GetData() *packet.Data {
+	c.RWlock.RLock()
+	defer c.RWlock.RUnlock()
 	return c.data
 }
 
+// Refresh 同一个标识的数据包再次到达时，使用新的数据包替换表项中的数据包，并根据其 FreshnessPeriod 重新计算不新鲜时间
+func (c *CSEntry) Refresh(data *packet.Data) {
+	c.RWlock.Lock()
+	defer c.RWlock.Unlock()
+	c.data = data
+	c.StaleTime = computeCSEntryStaleTime(data)
+}
+
 // GetIdentifier 获取表项中数据包的标识指针
 func (c *CSEntry) GetIdentifier() *component.Identifier {
-	return c.data.GetName()
+	return c.GetData().GetName()
 }
 
 // GetStaleTime 获得表项变旧时间
@@ -62,11 +83,11 @@ func (c *CSEntry) GetStaleTime() int64 {
 	return c.StaleTime
 }
 
-// IsStale 判断表项是否已经变得不新鲜
+// IsStale 判断表项是否已经变得不新鲜，FreshnessPeriod 为0的数据包一到达就是不新鲜的
 func (c *CSEntry) IsStale() bool {
 	c.RWlock.RLock()
 	defer c.RWlock.RUnlock()
-	return c.StaleTime < time.Now().Unix()
+	return c.StaleTime <= int64(common.GetCurrentTime())
 }
 
 // UpdateStaleTime 更新表项的变旧时间
@@ -78,7 +99,7 @@ func (c *CSEntry) UpdateStaleTime(newStaleTime int64) {
 
 // CanSatisfy 判断表项是否可以与某个兴趣包匹配 参考C++语言代码
 func (c *CSEntry) CanSatisfy(interest *packet.Interest) bool {
-	if !interest.MatchesData(c.data) {
+	if !interest.MatchesData(c.GetData()) {
 		return false
 	}
 	if interest.GetMustBeRefresh() == true && c.IsStale() {
@@ -86,3 +107,8 @@ func (c *CSEntry) CanSatisfy(interest *packet.Interest) bool {
 	}
 	return true
 }
+
+// computeCSEntryStaleTime 计算数据包的不新鲜时间，单位 ms
+func computeCSEntryStaleTime(data *packet.Data) int64 {
+	return int64(common.GetCurrentTime() + uint64(data.FreshnessPeriod.GetFreshnessPeriod()))
+}
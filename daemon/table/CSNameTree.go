@@ -0,0 +1,340 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 5:10 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package table
+
+import (
+	"math"
+	"sort"
+)
+
+// csNameTreeNode
+// CS 名字树中的一个节点，对应标识的一个前缀
+//
+// @Description:
+//
+type csNameTreeNode struct {
+	entry        *CSEntry                   // 标识恰好为本前缀的 CS 条目，没有则为 nil
+	children     map[string]*csNameTreeNode // 组件 => 子节点
+	keys         []string                   // 按照组件顺序排好序的子节点组件
+	maxStaleTime int64                      // 本节点及其子孙节点中所有条目的最晚的不新鲜时间，没有条目时为 math.MinInt64
+}
+
+// CSNameTree
+// CS 的有序名字树索引，支持按标识精确查找和按前缀查找
+//
+// @Description:
+//  1. 每个节点对应标识的一个前缀，CS 条目挂在其标识对应的节点上；
+//  2. 同一个节点的子节点按照组件的顺序排列：先比较组件的长度，长度相同时再按字节比较，所以前缀查找总是返回顺序最小的匹配条目；
+//  3. 删除条目时会同时删除不再有条目的空节点；
+//  4. 每个节点记录其子树中所有条目最晚的不新鲜时间，要求新鲜的前缀查找会跳过所有条目都已经不新鲜的子树，
+//     所以大量不新鲜的条目不会拖慢查找。条目的不新鲜时间变化之后（例如 CSEntry.Refresh ），需要重新 Insert 该条目；
+//  5. CSNameTree 本身不是线程安全的，由使用者负责加锁。
+//
+type CSNameTree struct {
+	root csNameTreeNode
+	size int
+}
+
+// CreateCSNameTree
+// 创建一个空的 CS 名字树
+//
+// @Description:
+// @return *CSNameTree
+//
+func CreateCSNameTree() *CSNameTree {
+	return &CSNameTree{root: csNameTreeNode{maxStaleTime: math.MinInt64}}
+}
+
+// Size
+// 获取名字树中 CS 条目的个数
+//
+// @Description:
+// @receiver t
+// @return int
+//
+func (t *CSNameTree) Size() int {
+	return t.size
+}
+
+// Insert
+// 将一个 CS 条目插入到其标识对应的节点上，已经存在的条目会被替换
+//
+// @Description:
+//  再次插入同一个条目会根据其当前的不新鲜时间更新路径上的节点
+// @receiver t
+// @param components		标识的组件列表
+// @param entry
+//
+func (t *CSNameTree) Insert(components []string, entry *CSEntry) {
+	path := make([]*csNameTreeNode, 0, len(components)+1)
+	current := &t.root
+	path = append(path, current)
+	for _, component := range components {
+		child, ok := current.children[component]
+		if !ok {
+			child = &csNameTreeNode{maxStaleTime: math.MinInt64}
+			current.addChild(component, child)
+		}
+		current = child
+		path = append(path, current)
+	}
+	if current.entry == nil {
+		t.size++
+	}
+	current.entry = entry
+	updateMaxStaleTime(path)
+}
+
+// Erase
+// 删除标识对应的 CS 条目
+//
+// @Description:
+// @receiver t
+// @param components		标识的组件列表
+// @return *CSEntry		被删除的条目，不存在时为 nil
+//
+func (t *CSNameTree) Erase(components []string) *CSEntry {
+	path := make([]*csNameTreeNode, 0, len(components)+1)
+	current := &t.root
+	path = append(path, current)
+	for _, component := range components {
+		child, ok := current.children[component]
+		if !ok {
+			return nil
+		}
+		current = child
+		path = append(path, current)
+	}
+	entry := current.entry
+	if entry == nil {
+		return nil
+	}
+	current.entry = nil
+	t.size--
+
+	// 自底向上删除空节点
+	depth := len(components)
+	for ; depth > 0; depth-- {
+		node := path[depth]
+		if node.entry != nil || len(node.keys) > 0 {
+			break
+		}
+		path[depth-1].removeChild(components[depth-1])
+	}
+	updateMaxStaleTime(path[:depth+1])
+	return entry
+}
+
+// FindExact
+// 查找标识恰好为 components 的 CS 条目
+//
+// @Description:
+// @receiver t
+// @param components		标识的组件列表
+// @return *CSEntry		不存在时为 nil
+//
+func (t *CSNameTree) FindExact(components []string) *CSEntry {
+	if node := t.findNode(components); node != nil {
+		return node.entry
+	}
+	return nil
+}
+
+// FindPrefix
+// 按照顺序查找第一个以 components 为前缀（包括恰好等于 components ）并且满足 predicate 的 CS 条目
+//
+// @Description:
+//  freshAt 大于 0 时只查找在 freshAt 时刻仍然新鲜的条目，所有条目都不新鲜的子树直接跳过，不会交给 predicate 判断
+// @receiver t
+// @param components		前缀的组件列表
+// @param freshAt			要求条目在该时刻仍然新鲜，单位 ms ，小于等于 0 时不要求
+// @param predicate		判断条目是否满足要求，为 nil 时所有条目都满足
+// @return *CSEntry		不存在时为 nil
+//
+func (t *CSNameTree) FindPrefix(components []string, freshAt int64, predicate func(entry *CSEntry) bool) *CSEntry {
+	node := t.findNode(components)
+	if node == nil {
+		return nil
+	}
+	minStaleTime := int64(math.MinInt64)
+	if freshAt > 0 {
+		minStaleTime = freshAt
+	}
+	return node.findFirst(minStaleTime, predicate)
+}
+
+// CollectPrefix
+// 按照顺序收集以 components 为前缀（包括恰好等于 components ）的 CS 条目
+//
+// @Description:
+// @receiver t
+// @param components		前缀的组件列表
+// @param limit			最多收集的条目个数，小于等于0时收集所有条目
+// @return []*CSEntry
+//
+func (t *CSNameTree) CollectPrefix(components []string, limit int) []*CSEntry {
+	entries := make([]*CSEntry, 0)
+	if node := t.findNode(components); node != nil {
+		node.findFirst(math.MinInt64, func(entry *CSEntry) bool {
+			entries = append(entries, entry)
+			return limit > 0 && len(entries) >= limit
+		})
+	}
+	return entries
+}
+
+//
+// 查找 components 对应的节点
+//
+// @Description:
+// @receiver t
+// @param components
+// @return *csNameTreeNode		不存在时为 nil
+//
+func (t *CSNameTree) findNode(components []string) *csNameTreeNode {
+	current := &t.root
+	for _, component := range components {
+		child, ok := current.children[component]
+		if !ok {
+			return nil
+		}
+		current = child
+	}
+	return current
+}
+
+//
+// 深度优先地按照顺序查找本节点及其子孙节点中第一个不新鲜时间晚于 minStaleTime 并且满足 predicate 的条目
+//
+// @Description:
+//  子树中最晚的不新鲜时间不晚于 minStaleTime 时，整棵子树都不会被访问
+// @receiver n
+// @param minStaleTime
+// @param predicate
+// @return *CSEntry
+//
+func (n *csNameTreeNode) findFirst(minStaleTime int64, predicate func(entry *CSEntry) bool) *CSEntry {
+	if minStaleTime != math.MinInt64 && n.maxStaleTime <= minStaleTime {
+		return nil
+	}
+	if n.entry != nil && (minStaleTime == math.MinInt64 || n.entry.GetStaleTime() > minStaleTime) &&
+		(predicate == nil || predicate(n.entry)) {
+		return n.entry
+	}
+	for _, key := range n.keys {
+		if entry := n.children[key].findFirst(minStaleTime, predicate); entry != nil {
+			return entry
+		}
+	}
+	return nil
+}
+
+//
+// 计算本节点及其子节点中所有条目最晚的不新鲜时间
+//
+// @Description:
+// @receiver n
+// @return int64
+//
+func (n *csNameTreeNode) computeMaxStaleTime() int64 {
+	maxStaleTime := int64(math.MinInt64)
+	if n.entry != nil {
+		maxStaleTime = n.entry.GetStaleTime()
+	}
+	for _, child := range n.children {
+		if child.maxStaleTime > maxStaleTime {
+			maxStaleTime = child.maxStaleTime
+		}
+	}
+	return maxStaleTime
+}
+
+//
+// 自底向上更新从根节点开始的一条路径上各个节点的最晚的不新鲜时间
+//
+// @Description:
+//  某一层没有变化时，它的祖先节点也不会变化
+// @param path
+//
+func updateMaxStaleTime(path []*csNameTreeNode) {
+	for i := len(path) - 1; i >= 0; i-- {
+		maxStaleTime := path[i].computeMaxStaleTime()
+		if maxStaleTime == path[i].maxStaleTime {
+			return
+		}
+		path[i].maxStaleTime = maxStaleTime
+	}
+}
+
+//
+// 添加一个子节点，并保持子节点有序
+//
+// @Description:
+// @receiver n
+// @param component
+// @param child
+//
+func (n *csNameTreeNode) addChild(component string, child *csNameTreeNode) {
+	if n.children == nil {
+		n.children = make(map[string]*csNameTreeNode)
+	}
+	n.children[component] = child
+	index := sort.Search(len(n.keys), func(i int) bool {
+		return !lessCSNameComponent(n.keys[i], component)
+	})
+	n.keys = append(n.keys, "")
+	copy(n.keys[index+1:], n.keys[index:])
+	n.keys[index] = component
+}
+
+//
+// 删除一个子节点
+//
+// @Description:
+// @receiver n
+// @param component
+//
+func (n *csNameTreeNode) removeChild(component string) {
+	if _, ok := n.children[component]; !ok {
+		return
+	}
+	delete(n.children, component)
+	index := sort.Search(len(n.keys), func(i int) bool {
+		return !lessCSNameComponent(n.keys[i], component)
+	})
+	n.keys = append(n.keys[:index], n.keys[index+1:]...)
+}
+
+//
+// 组件的顺序：先比较长度，长度相同时再按字节比较
+//
+// @Description:
+// @param a
+// @param b
+// @return bool		a 是否排在 b 之前
+//
+func lessCSNameComponent(a string, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
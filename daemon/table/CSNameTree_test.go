@@ -0,0 +1,117 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 5:30 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package table
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func newTestCSNameTreeEntry(staleTime int64) *CSEntry {
+	return &CSEntry{StaleTime: staleTime, RWlock: new(sync.RWMutex)}
+}
+
+func TestCSNameTree_FindPrefix(t *testing.T) {
+	tree := CreateCSNameTree()
+	v2 := newTestCSNameTreeEntry(0)
+	v10 := newTestCSNameTreeEntry(0)
+	v3 := newTestCSNameTreeEntry(0)
+	tree.Insert([]string{"video", "seg", "v10"}, v10)
+	tree.Insert([]string{"video", "seg", "v2"}, v2)
+	tree.Insert([]string{"video", "seg", "v3"}, v3)
+
+	if tree.FindExact([]string{"video", "seg"}) != nil {
+		t.Fatal("expect no exact match for /video/seg")
+	}
+	// 按照组件的顺序（先比较长度）返回第一个匹配的条目
+	if tree.FindPrefix([]string{"video", "seg"}, 0, nil) != v2 {
+		t.Fatal("expect /video/seg/v2 to be the first match")
+	}
+	// 跳过不满足条件的条目
+	if tree.FindPrefix([]string{"video"}, 0, func(entry *CSEntry) bool { return entry != v2 }) != v3 {
+		t.Fatal("expect /video/seg/v3 to be the first satisfied match")
+	}
+	if tree.FindPrefix([]string{"audio"}, 0, nil) != nil {
+		t.Fatal("expect no match for /audio")
+	}
+	fmt.Println(tree.Size())
+}
+
+func TestCSNameTree_Erase(t *testing.T) {
+	tree := CreateCSNameTree()
+	parent := newTestCSNameTreeEntry(0)
+	child := newTestCSNameTreeEntry(0)
+	tree.Insert([]string{"min", "pku"}, parent)
+	tree.Insert([]string{"min", "pku", "edu"}, child)
+
+	if tree.Erase([]string{"min"}) != nil {
+		t.Fatal("expect nothing erased for /min")
+	}
+	if tree.Erase([]string{"min", "pku", "edu"}) != child || tree.Size() != 1 {
+		t.Fatal("expect /min/pku/edu erased")
+	}
+	if tree.FindPrefix([]string{"min"}, 0, nil) != parent {
+		t.Fatal("expect /min/pku still exists")
+	}
+	tree.Erase([]string{"min", "pku"})
+	// 空节点被删除
+	if tree.Size() != 0 || len(tree.root.keys) != 0 {
+		t.Fatal("expect empty tree, got", tree.Size(), tree.root.keys)
+	}
+}
+
+func TestCSNameTree_FindPrefixFresh(t *testing.T) {
+	tree := CreateCSNameTree()
+	fresh := newTestCSNameTreeEntry(200)
+	tree.Insert([]string{"video", "fresh"}, fresh)
+	for i := 0; i < 100; i++ {
+		tree.Insert([]string{"video", "stale", fmt.Sprintf("s%d", i)}, newTestCSNameTreeEntry(100))
+	}
+
+	// 所有条目都不新鲜的子树不会交给 predicate 判断
+	visited := 0
+	predicate := func(entry *CSEntry) bool {
+		visited++
+		return true
+	}
+	if tree.FindPrefix([]string{"video"}, 150, predicate) != fresh || visited != 1 {
+		t.Fatal("expect only /video/fresh visited, got", visited)
+	}
+	visited = 0
+	if tree.FindPrefix([]string{"video", "stale"}, 150, predicate) != nil || visited != 0 {
+		t.Fatal("expect stale subtree skipped, got", visited)
+	}
+
+	// 重新插入之后使用新的不新鲜时间，删除之后不再计入
+	stale := tree.FindExact([]string{"video", "stale", "s1"})
+	stale.UpdateStaleTime(300)
+	tree.Insert([]string{"video", "stale", "s1"}, stale)
+	if tree.FindPrefix([]string{"video"}, 250, nil) != stale {
+		t.Fatal("expect refreshed /video/stale/s1 found")
+	}
+	tree.Erase([]string{"video", "stale", "s1"})
+	if tree.FindPrefix([]string{"video"}, 250, nil) != nil || tree.root.maxStaleTime != 200 {
+		t.Fatal("expect no fresh entry after erase, got", tree.root.maxStaleTime)
+	}
+}
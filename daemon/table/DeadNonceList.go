@@ -0,0 +1,255 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 8:10 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package table
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"minlib/component"
+	"mir-go/daemon/common"
+	"sync"
+)
+
+const (
+	DefaultDeadNonceListLifetime = 6000  // Dead Nonce List 中记录的默认存活时间，单位 ms
+	MinDeadNonceListLifetime     = 1000  // Dead Nonce List 中记录的最短存活时间，单位 ms
+	MaxDeadNonceListLifetime     = 60000 // Dead Nonce List 中记录的最长存活时间，单位 ms
+)
+
+// deadNonceEntry Dead Nonce List 中的一条记录
+type deadNonceEntry struct {
+	count      int    // 该 (标识, Nonce) 在队列中出现的次数
+	insertTime uint64 // 最后一次插入的时间
+}
+
+// deadNonceRecord 按插入顺序排列的记录，用来淘汰过期的记录
+type deadNonceRecord struct {
+	key        uint64 // (标识, Nonce) 的哈希值
+	insertTime uint64 // 插入时间
+}
+
+// DeadNonceList
+// Dead Nonce List，记录已经被移除的 PIT 条目中出现过的 (标识, Nonce) 对
+//
+// @Description:
+//  1. PIT 条目被移除之后就无法再通过 FindDuplicateNonce 检测回环，DNL 在 PIT 条目被移除时记录其中的 Nonce，
+//     在一段时间（lifetime）内仍然可以检测到回环的兴趣包；
+//  2. 只保存 (标识, Nonce) 的 64 位哈希值，存在极小的误判概率；
+//  3. lifetime 会根据观察到的回环情况自适应调整：每经过一个 lifetime 周期检查一次，如果有命中的记录已经接近过期，
+//     说明回环的路径比 lifetime 更长，则增大 lifetime；如果整个周期都没有命中，则逐渐减小 lifetime 以节省内存。
+//
+type DeadNonceList struct {
+	entries        map[uint64]*deadNonceEntry // 哈希值 => 记录
+	queue          []deadNonceRecord          // 按插入时间排序的记录队列
+	lifetime       uint64                     // 当前的记录存活时间，单位 ms
+	lastAdjustTime uint64                     // 上一次调整 lifetime 的时间
+	nHits          uint64                     // 当前周期内命中的次数
+	nLateHits      uint64                     // 当前周期内命中的记录中，已经接近过期的次数
+	lock           sync.Mutex
+}
+
+// CreateDeadNonceList
+// 创建一个 Dead Nonce List
+//
+// @Description:
+// @return *DeadNonceList
+//
+func CreateDeadNonceList() *DeadNonceList {
+	return &DeadNonceList{
+		entries:        make(map[uint64]*deadNonceEntry),
+		queue:          make([]deadNonceRecord, 0),
+		lifetime:       DefaultDeadNonceListLifetime,
+		lastAdjustTime: common.GetCurrentTime(),
+	}
+}
+
+// Add
+// 往 DNL 中添加一个 (标识, Nonce) 对
+//
+// @Description:
+// @receiver d
+// @param identifier
+// @param nonce
+//
+func (d *DeadNonceList) Add(identifier *component.Identifier, nonce *component.Nonce) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.add(deadNonceKey(identifier, nonce), common.GetCurrentTime())
+}
+
+// Has
+// 判断 DNL 中是否存在某个 (标识, Nonce) 对
+//
+// @Description:
+// @receiver d
+// @param identifier
+// @param nonce
+// @return bool
+//
+func (d *DeadNonceList) Has(identifier *component.Identifier, nonce *component.Nonce) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.has(deadNonceKey(identifier, nonce), common.GetCurrentTime())
+}
+
+// Size
+// 获取 DNL 中不同的 (标识, Nonce) 对的数量
+//
+// @Description:
+// @receiver d
+// @return int
+//
+func (d *DeadNonceList) Size() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return len(d.entries)
+}
+
+// GetLifetime
+// 获取当前的记录存活时间，单位 ms
+//
+// @Description:
+// @receiver d
+// @return uint64
+//
+func (d *DeadNonceList) GetLifetime() uint64 {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.lifetime
+}
+
+//
+// 在 now 时刻插入一条记录
+//
+// @Description:
+// @receiver d
+// @param key
+// @param now
+//
+func (d *DeadNonceList) add(key uint64, now uint64) {
+	d.evict(now)
+	d.adjust(now)
+	if entry, ok := d.entries[key]; ok {
+		entry.count++
+		entry.insertTime = now
+	} else {
+		d.entries[key] = &deadNonceEntry{count: 1, insertTime: now}
+	}
+	d.queue = append(d.queue, deadNonceRecord{key: key, insertTime: now})
+}
+
+//
+// 在 now 时刻查询一条记录，命中时统计回环情况
+//
+// @Description:
+// @receiver d
+// @param key
+// @param now
+// @return bool
+//
+func (d *DeadNonceList) has(key uint64, now uint64) bool {
+	d.evict(now)
+	d.adjust(now)
+	entry, ok := d.entries[key]
+	if !ok {
+		return false
+	}
+	d.nHits++
+	// 命中的记录已经度过了 lifetime 的 3/4，说明回环的时间和 lifetime 很接近，更长的回环可能检测不到
+	if (now-entry.insertTime)*4 >= d.lifetime*3 {
+		d.nLateHits++
+	}
+	return true
+}
+
+//
+// 淘汰所有已经过期的记录
+//
+// @Description:
+// @receiver d
+// @param now
+//
+func (d *DeadNonceList) evict(now uint64) {
+	i := 0
+	for ; i < len(d.queue); i++ {
+		record := d.queue[i]
+		if record.insertTime+d.lifetime > now {
+			break
+		}
+		if entry, ok := d.entries[record.key]; ok {
+			entry.count--
+			if entry.count <= 0 {
+				delete(d.entries, record.key)
+			}
+		}
+	}
+	if i > 0 {
+		d.queue = append(d.queue[:0], d.queue[i:]...)
+	}
+}
+
+//
+// 每经过一个 lifetime 周期，根据该周期内的命中情况调整 lifetime
+//
+// @Description:
+//  1. 存在接近过期的命中 => lifetime 增大 50%，不超过 MaxDeadNonceListLifetime；
+//  2. 没有任何命中 => lifetime 减小 10%，不低于 MinDeadNonceListLifetime；
+//  3. 其它情况保持不变。
+// @receiver d
+// @param now
+//
+func (d *DeadNonceList) adjust(now uint64) {
+	if now < d.lastAdjustTime+d.lifetime {
+		return
+	}
+	if d.nLateHits > 0 {
+		d.lifetime = d.lifetime * 3 / 2
+		if d.lifetime > MaxDeadNonceListLifetime {
+			d.lifetime = MaxDeadNonceListLifetime
+		}
+	} else if d.nHits == 0 {
+		d.lifetime = d.lifetime * 9 / 10
+		if d.lifetime < MinDeadNonceListLifetime {
+			d.lifetime = MinDeadNonceListLifetime
+		}
+	}
+	d.nHits = 0
+	d.nLateHits = 0
+	d.lastAdjustTime = now
+}
+
+//
+// 计算 (标识, Nonce) 对的哈希值
+//
+// @Description:
+// @param identifier
+// @param nonce
+// @return uint64
+//
+func deadNonceKey(identifier *component.Identifier, nonce *component.Nonce) uint64 {
+	hasher := fnv.New64a()
+	_, _ = hasher.Write([]byte(identifier.ToUri()))
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(nonce.GetNonce()))
+	_, _ = hasher.Write(buf[:])
+	return hasher.Sum64()
+}
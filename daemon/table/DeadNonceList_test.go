@@ -0,0 +1,90 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 8:10 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package table
+
+import (
+	"fmt"
+	"minlib/component"
+	"testing"
+)
+
+func TestDeadNonceListAddHas(t *testing.T) {
+	dnl := CreateDeadNonceList()
+	identifier, _ := component.CreateIdentifierByString("/min/pku/edu")
+	nonce := component.Nonce{}
+	nonce.SetNonce(1234)
+	otherNonce := component.Nonce{}
+	otherNonce.SetNonce(5678)
+
+	dnl.Add(identifier, &nonce)
+	if !dnl.Has(identifier, &nonce) {
+		t.Fatal("expect (identifier, nonce) in dead nonce list")
+	}
+	if dnl.Has(identifier, &otherNonce) {
+		t.Fatal("expect (identifier, otherNonce) not in dead nonce list")
+	}
+	fmt.Println(dnl.Size(), dnl.GetLifetime())
+}
+
+func TestDeadNonceListExpire(t *testing.T) {
+	dnl := CreateDeadNonceList()
+	now := dnl.lastAdjustTime
+
+	dnl.add(1, now)
+	dnl.add(1, now+1000)
+	dnl.add(2, now+2000)
+	// 第一次插入的记录过期之后，第二次插入的记录依然有效
+	if !dnl.has(1, now+DefaultDeadNonceListLifetime+500) {
+		t.Fatal("expect key 1 still alive")
+	}
+	if dnl.has(1, now+DefaultDeadNonceListLifetime+1000) {
+		t.Fatal("expect key 1 expired")
+	}
+	if !dnl.has(2, now+DefaultDeadNonceListLifetime+1000) {
+		t.Fatal("expect key 2 still alive")
+	}
+	fmt.Println(len(dnl.entries), len(dnl.queue))
+}
+
+func TestDeadNonceListAdaptiveLifetime(t *testing.T) {
+	dnl := CreateDeadNonceList()
+	now := dnl.lastAdjustTime
+
+	// 命中接近过期的记录 => 下一个周期 lifetime 增大
+	dnl.add(1, now)
+	dnl.has(1, now+DefaultDeadNonceListLifetime-100)
+	dnl.has(2, now+DefaultDeadNonceListLifetime)
+	if dnl.lifetime <= DefaultDeadNonceListLifetime {
+		t.Fatal("expect lifetime to grow, got", dnl.lifetime)
+	}
+	fmt.Println("grow =>", dnl.lifetime)
+
+	// 长时间没有命中 => lifetime 逐渐减小到下限
+	for i := 0; i < 100; i++ {
+		now = dnl.lastAdjustTime + dnl.lifetime
+		dnl.has(3, now)
+	}
+	if dnl.lifetime != MinDeadNonceListLifetime {
+		t.Fatal("expect lifetime to shrink to min, got", dnl.lifetime)
+	}
+	fmt.Println("shrink =>", dnl.lifetime)
+}
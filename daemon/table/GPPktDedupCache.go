@@ -0,0 +1,192 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 9:00 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package table
+
+import (
+	"crypto/sha256"
+	"minlib/packet"
+	"mir-go/daemon/common"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	DefaultGPPktDedupCacheSize = 10000 // GPPkt 去重缓存默认最多记录的包数
+	DefaultGPPktDedupLifetime  = 2000  // GPPkt 去重缓存中记录的默认存活时间，单位 ms
+)
+
+// gPPktDedupKey GPPkt 的去重键，取 (源标识, 目的标识, 负载) 的 SHA-256 摘要的前 16 字节
+type gPPktDedupKey [16]byte
+
+// gPPktDedupRecord 按插入顺序排列的记录，用来淘汰过期的记录
+type gPPktDedupRecord struct {
+	key        gPPktDedupKey // 去重键
+	insertTime uint64        // 插入时间
+}
+
+// GPPktDedupCache
+// GPPkt 去重缓存，记录最近一段时间内收到过的 GPPkt
+//
+// @Description:
+//  1. GPPkt 是推式语义的网络包，无法通过 PIT 检测重复，多路径或者多播转发时同一个 GPPkt 可能从不同的路径多次到达，
+//     如果不加以抑制，会在 TTL 耗尽之前不断被放大；
+//  2. 去重键由 (源标识, 目的标识, 负载) 计算得到，不包含 TTL 等逐跳变化的字段，所以从不同路径到达的同一个包具有相同的去重键；
+//  3. 缓存同时受容量和存活时间的限制：记录在插入 lifetime 之后过期，记录数超过容量时淘汰最早插入的记录。
+//
+type GPPktDedupCache struct {
+	entries   map[gPPktDedupKey]uint64 // 去重键 => 插入时间
+	queue     []gPPktDedupRecord       // 按插入时间排序的记录队列
+	capacity  int                      // 最多记录的包数
+	lifetime  uint64                   // 记录的存活时间，单位 ms
+	dropCount uint64                   // 因为重复被丢弃的包数
+	lock      sync.Mutex
+}
+
+// CreateGPPktDedupCache
+// 创建一个 GPPkt 去重缓存
+//
+// @Description:
+// @param capacity		最多记录的包数
+// @param lifetime		记录的存活时间，单位 ms
+// @return *GPPktDedupCache
+//
+func CreateGPPktDedupCache(capacity int, lifetime uint64) *GPPktDedupCache {
+	return &GPPktDedupCache{
+		entries:  make(map[gPPktDedupKey]uint64),
+		queue:    make([]gPPktDedupRecord, 0),
+		capacity: capacity,
+		lifetime: lifetime,
+	}
+}
+
+// IsDuplicate
+// 判断一个 GPPkt 最近是否已经收到过，如果没有收到过则将其记录到缓存中
+//
+// @Description:
+//  返回 true 时会同时增加丢包计数，调用者应该丢弃该包
+// @receiver g
+// @param gPPkt
+// @return bool
+//
+func (g *GPPktDedupCache) IsDuplicate(gPPkt *packet.GPPkt) bool {
+	key := makeGPPktDedupKey(gPPkt.SrcIdentifier().ToUri(), gPPkt.DstIdentifier().ToUri(), gPPkt.Payload.GetValue())
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.checkAndInsert(key, common.GetCurrentTime())
+}
+
+// Size
+// 获取缓存中的记录数
+//
+// @Description:
+// @receiver g
+// @return int
+//
+func (g *GPPktDedupCache) Size() int {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return len(g.entries)
+}
+
+// GetDropCount
+// 获取因为重复被丢弃的包数
+//
+// @Description:
+// @receiver g
+// @return uint64
+//
+func (g *GPPktDedupCache) GetDropCount() uint64 {
+	return atomic.LoadUint64(&g.dropCount)
+}
+
+//
+// 在 now 时刻检查一个去重键，存在则增加丢包计数，不存在则插入
+//
+// @Description:
+// @receiver g
+// @param key
+// @param now
+// @return bool		是否重复
+//
+func (g *GPPktDedupCache) checkAndInsert(key gPPktDedupKey, now uint64) bool {
+	g.evict(now)
+	if _, ok := g.entries[key]; ok {
+		atomic.AddUint64(&g.dropCount, 1)
+		return true
+	}
+	g.entries[key] = now
+	g.queue = append(g.queue, gPPktDedupRecord{key: key, insertTime: now})
+	// 超过容量则淘汰最早插入的记录
+	for len(g.entries) > g.capacity && len(g.queue) > 0 {
+		g.removeFront()
+	}
+	return false
+}
+
+//
+// 淘汰所有已经过期的记录
+//
+// @Description:
+// @receiver g
+// @param now
+//
+func (g *GPPktDedupCache) evict(now uint64) {
+	for len(g.queue) > 0 && g.queue[0].insertTime+g.lifetime <= now {
+		g.removeFront()
+	}
+}
+
+//
+// 移除队列中最早插入的记录
+//
+// @Description:
+// @receiver g
+//
+func (g *GPPktDedupCache) removeFront() {
+	record := g.queue[0]
+	g.queue[0] = gPPktDedupRecord{}
+	g.queue = g.queue[1:]
+	if insertTime, ok := g.entries[record.key]; ok && insertTime == record.insertTime {
+		delete(g.entries, record.key)
+	}
+}
+
+//
+// 计算 GPPkt 的去重键
+//
+// @Description:
+// @param src		源标识的 URI
+// @param dst		目的标识的 URI
+// @param payload	负载
+// @return gPPktDedupKey
+//
+func makeGPPktDedupKey(src string, dst string, payload []byte) gPPktDedupKey {
+	hasher := sha256.New()
+	_, _ = hasher.Write([]byte(src))
+	_, _ = hasher.Write([]byte{0})
+	_, _ = hasher.Write([]byte(dst))
+	_, _ = hasher.Write([]byte{0})
+	_, _ = hasher.Write(payload)
+	var key gPPktDedupKey
+	copy(key[:], hasher.Sum(nil))
+	return key
+}
@@ -0,0 +1,73 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 9:00 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package table
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGPPktDedupCacheDuplicate(t *testing.T) {
+	cache := CreateGPPktDedupCache(100, 1000)
+	key1 := makeGPPktDedupKey("/src", "/dst", []byte("hello"))
+	key2 := makeGPPktDedupKey("/src", "/dst", []byte("world"))
+
+	if cache.checkAndInsert(key1, 0) {
+		t.Fatal("first packet should not be duplicate")
+	}
+	if !cache.checkAndInsert(key1, 500) {
+		t.Fatal("second packet should be duplicate")
+	}
+	if cache.checkAndInsert(key2, 500) {
+		t.Fatal("packet with different payload should not be duplicate")
+	}
+	// key1 在 1000ms 时过期
+	if cache.checkAndInsert(key1, 1000) {
+		t.Fatal("expired packet should not be duplicate")
+	}
+	if cache.GetDropCount() != 1 {
+		t.Fatal("expect drop count 1, got", cache.GetDropCount())
+	}
+	fmt.Println(len(cache.entries), len(cache.queue), cache.GetDropCount())
+}
+
+func TestGPPktDedupCacheCapacity(t *testing.T) {
+	cache := CreateGPPktDedupCache(2, 1000)
+	key1 := makeGPPktDedupKey("/src", "/dst", []byte("1"))
+	key2 := makeGPPktDedupKey("/src", "/dst", []byte("2"))
+	key3 := makeGPPktDedupKey("/src", "/dst", []byte("3"))
+
+	cache.checkAndInsert(key1, 0)
+	cache.checkAndInsert(key2, 0)
+	cache.checkAndInsert(key3, 0)
+	if len(cache.entries) != 2 {
+		t.Fatal("expect 2 entries, got", len(cache.entries))
+	}
+	// 最早插入的 key1 被淘汰
+	if cache.checkAndInsert(key1, 10) {
+		t.Fatal("evicted packet should not be duplicate")
+	}
+	if !cache.checkAndInsert(key1, 20) {
+		t.Fatal("expect duplicate")
+	}
+	fmt.Println(len(cache.entries), len(cache.queue), cache.GetDropCount())
+}
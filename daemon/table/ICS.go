@@ -21,7 +21,10 @@
 //
 package table
 
-import "minlib/packet"
+import (
+	"minlib/component"
+	"minlib/packet"
+)
 
 // ICS 定义CS（ContentStore）表的通用行为，每一个CS表的实现都应该实现本接口
 //
@@ -31,10 +34,9 @@ type ICS interface {
 	// Find 根据传入的 Interest 查询CS表中是否缓存有与之匹配的 data
 	//
 	// @Description:
-	// 根据设计的不同，Interest和CS条目匹配的规则也可以有不同的定义：
-	//  1. NDN里面，Interest支持CanBePrefix字段，所以查询CS的时候不是精确匹配，是前缀匹配；
-	//  2. 在MIN当前的设计里面，为了加快并行转发并简化设计，可能会把Interest查询CS设计为hash查找；
-	//     (MIN里面也定义了CanBePrefix字段，但是暂时没有实现该效果，所以这个字段目前是无效字段）
+	//  1. Interest 的 CanBePrefix = false 时精确匹配，只有标识与兴趣包的名字相同的条目才能匹配；
+	//  2. CanBePrefix = true 时前缀匹配，以兴趣包的名字为前缀的条目都可以匹配；
+	//  3. 匹配的条目还需要满足 CSEntry.CanSatisfy ，例如 MustBeFresh = true 时不新鲜的条目不能匹配。
 	// @param interest
 	// @return *CSEntry
 	//
@@ -43,6 +45,7 @@ type ICS interface {
 	// Insert 将传入的 data 缓存到CS当中
 	//
 	// @Description:
+	// 插入之前先由准入策略判断是否可以缓存，不被准入的数据包直接返回错误；
 	// 插入过程需要根据CS自己定义的缓存替换策略，来替换、踢出或者更新CS条目
 	// @param data
 	// @return *CSEntry
@@ -55,4 +58,91 @@ type ICS interface {
 	// @return int
 	//
 	Size() int
+
+	// Erase 删除以 prefix 为前缀的条目
+	//
+	// @Description:
+	// @param prefix
+	// @param limit		最多删除的条目个数，小于等于0时删除所有匹配的条目
+	// @return int		删除的条目个数
+	//
+	Erase(prefix *component.Identifier, limit int) int
+
+	// SetCapacity 修改缓存的容量，超出新容量的条目会被淘汰
+	//
+	// @Description:
+	// @param capacity
+	// @return error
+	//
+	SetCapacity(capacity int64) error
+
+	// GetCapacity 获取缓存的容量
+	//
+	// @Description:
+	// @return int64
+	//
+	GetCapacity() int64
+
+	// SetEnableAdmit 设置是否缓存数据包，可以在运行时调用
+	//
+	// @Description:
+	// @param enable
+	//
+	SetEnableAdmit(enable bool)
+
+	// IsAdmitEnabled 判断是否缓存数据包
+	//
+	// @Description:
+	// @return bool
+	//
+	IsAdmitEnabled() bool
+
+	// SetEnableServe 设置是否使用缓存的数据包响应兴趣包，可以在运行时调用
+	//
+	// @Description:
+	// @param enable
+	//
+	SetEnableServe(enable bool)
+
+	// IsServeEnabled 判断是否使用缓存的数据包响应兴趣包
+	//
+	// @Description:
+	// @return bool
+	//
+	IsServeEnabled() bool
+
+	// GetHits 获取命中次数
+	//
+	// @Description:
+	// @return uint64
+	//
+	GetHits() uint64
+
+	// GetMisses 获取未命中次数
+	//
+	// @Description:
+	// @return uint64
+	//
+	GetMisses() uint64
+
+	// Close 关闭CS表，转发器退出时调用
+	//
+	// @Description:
+	// @return error
+	//
+	Close() error
+
+	// SetAdmissionPolicy 设置准入策略，可以在运行时调用
+	//
+	// @Description:
+	// @param policy
+	//
+	SetAdmissionPolicy(policy ICSAdmissionPolicy)
+
+	// GetAdmissionPolicy 获取当前使用的准入策略
+	//
+	// @Description:
+	// @return ICSAdmissionPolicy
+	//
+	GetAdmissionPolicy() ICSAdmissionPolicy
 }
@@ -21,7 +21,10 @@
 //
 package table
 
-import "minlib/packet"
+import (
+	"minlib/component"
+	"minlib/packet"
+)
 
 // ICSPolicy ContentStore 缓存替换策略接口，定义了CS缓存替换策略的通用行为，所有的CS缓存替换策略都需要实现本接口
 //
@@ -51,4 +54,36 @@ type ICSPolicy interface {
 	// @return int
 	//
 	Size() int
+
+	// Erase 删除以 prefix 为前缀的条目
+	//
+	// @Description:
+	// @param prefix
+	// @param limit		最多删除的条目个数，小于等于0时删除所有匹配的条目
+	// @return int		删除的条目个数
+	//
+	Erase(prefix *component.Identifier, limit int) int
+
+	// SetCapacity 修改缓存的容量，超出新容量的条目会被淘汰
+	//
+	// @Description:
+	//  容量的单位由缓存策略决定
+	// @param capacity
+	// @return error
+	//
+	SetCapacity(capacity int64) error
+
+	// GetCapacity 获取缓存的容量
+	//
+	// @Description:
+	// @return int64
+	//
+	GetCapacity() int64
+
+	// Close 关闭缓存策略，释放其占用的资源，例如将缓存的数据包持久化到磁盘
+	//
+	// @Description:
+	// @return error
+	//
+	Close() error
 }
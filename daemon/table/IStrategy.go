@@ -70,6 +70,17 @@ type IStrategy interface {
 	//
 	AfterReceiveData(ingress *lf.LogicFace, data *packet.Data, pitEntry *PITEntry)
 
+	// AfterReceiveUnsolicitedData
+	// 当收到一个没有匹配到 PIT 条目的 data 时，会触发本触发器
+	//
+	// @Description:
+	//	data 位于当前策略的命名空间下，触发之后 data 仍然按照 data unsolicited 管道的配置丢弃或者缓存，策略不能转发它，
+	//	只能据此更新策略自己维护的状态（例如统计 PIT 条目被满足之后才返回的探测 data 的 RTT）
+	// @param ingress		data 到来的入口 LogicFace
+	// @param data			收到的 data
+	//
+	AfterReceiveUnsolicitedData(ingress *lf.LogicFace, data *packet.Data)
+
 	// AfterReceiveNack
 	// 当收到一个 Nack 时，会触发本触发器
 	//
@@ -90,6 +101,38 @@ type IStrategy interface {
 	//
 	AfterReceiveGPPkt(ingress *lf.LogicFace, gPPkt *packet.GPPkt)
 
+	// BeforeExpirePendingInterest
+	// 当一个没有被满足的 PIT 条目即将被移除时，会触发本触发器
+	//
+	// @Description:
+	//	策略可以在此触发器内统计上游的超时信息，此时 PIT 条目中的 out-record 仍然可以访问
+	// @param pitEntry		即将被移除的PIT条目
+	//
+	BeforeExpirePendingInterest(pitEntry *PITEntry)
+
+	// OnFaceUp
+	// 当一个新的 LogicFace 被创建时，会触发本触发器
+	//
+	// @Description:
+	//	本触发器不在转发分片的协程中调用，策略不能在其中访问 PIT，只能更新策略自己维护的状态
+	// @param face			新创建的 LogicFace
+	//
+	OnFaceUp(face *lf.LogicFace)
+
+	// OnFaceDown
+	// 当一个 LogicFace 被关闭时，会触发本触发器
+	//
+	// @Description:
+	//	1. 转发器会在每个转发分片的协程中分别触发一次本触发器，此时该分片 PIT 中指向 face 的 in-record 和 out-record 都已经被清除，
+	//	   没有剩余 in-record 的 PIT 条目也已经被设置为立即过期；
+	//	2. pitEntries 是该分片中属于当前策略命名空间，原来有指向 face 的 out-record ，并且仍然有下游在等待的 PIT 条目，策略可以将其
+	//	   立即转发到其它的上游，而不用等待 PIT 条目超时；
+	//	3. 策略也可以在其中清除自己维护的和 face 相关的状态，因为会被触发多次，所以清除操作需要是幂等的。
+	// @param face			被关闭的 LogicFace
+	// @param pitEntries	失去了上游的 PIT 条目，可能为空
+	//
+	OnFaceDown(face *lf.LogicFace, pitEntries []*PITEntry)
+
 	////////////////////////////////////////////////////////////////////////////////////////////////////////
 	////// Actions
 	////////////////////////////////////////////////////////////////////////////////////////////////////////
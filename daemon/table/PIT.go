@@ -80,6 +80,28 @@ func (p *PIT) Size() uint64 {
 	})
 }
 
+// GetAllEntry
+// 返回PIT表中所有的表项
+//
+// @Description:
+// @return []*PITEntry
+//
+func (p *PIT) GetAllEntry() []*PITEntry {
+	var entries []*PITEntry
+	p.lpm.TraverseFunc(func(val interface{}) uint64 {
+		if pitEntry, ok := val.(*PITEntry); ok {
+			entries = append(entries, pitEntry)
+			return 1
+		} else {
+			common2.LogErrorWithFields(logrus.Fields{
+				"value": val,
+			}, "PITEntry transform fail")
+		}
+		return 0
+	})
+	return entries
+}
+
 // Find
 // 通过兴趣包在前缀树中精准匹配查找对应的PITEntry
 //
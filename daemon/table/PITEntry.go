@@ -49,6 +49,7 @@ type InRecord struct {
 type OutRecord struct {
 	LogicFace  *lf.LogicFace   //流出LogicFace指针
 	ExpireTime uint64          //超时时间 应用层设置 底层不用
+	SendTime   uint64          //最后一次往该LogicFace发送兴趣包的时间 用于计算RTT
 	LastNonce  component.Nonce //与InRecord中的LastNonce一致
 	NackHeader *component.NackHeader
 }
@@ -64,6 +65,7 @@ type PITEntry struct {
 	OutRecordList map[uint64]*OutRecord //流出记录表
 	isSatisfied   bool                  // 是否已被满足
 	isDeleted     bool                  // 是否已经从 PIT 表中移除
+	strategyInfo  interface{}           // 策略保存在 PIT 条目上的私有状态，随 PIT 条目一起被移除
 	//ExpireTime    time.Duration         //超时时间 底层设置 过期删除
 	//InRWlock               *sync.RWMutex         //流入读写锁
 	//OutRWlock              *sync.RWMutex         //流出读写锁
@@ -133,6 +135,30 @@ func (p *PITEntry) SetDeleted(isDeleted bool) {
 	p.isDeleted = isDeleted
 }
 
+// GetStrategyInfo
+// 获取策略保存在当前 PITEntry 上的私有状态
+//
+// @Description:
+//  PIT 条目只会被其所在转发分片的协程访问，所以不需要加锁；策略在使用之前应该先做类型断言，类型不符时（例如前缀的策略被更换）
+//  应该视为没有状态
+// @receiver p
+// @return interface{}
+//
+func (p *PITEntry) GetStrategyInfo() interface{} {
+	return p.strategyInfo
+}
+
+// SetStrategyInfo
+// 设置策略保存在当前 PITEntry 上的私有状态
+//
+// @Description:
+// @receiver p
+// @param strategyInfo
+//
+func (p *PITEntry) SetStrategyInfo(strategyInfo interface{}) {
+	p.strategyInfo = strategyInfo
+}
+
 //// SetExpiryTimer
 //// 设置超时定时器 经过duration时间段 自动调用函数f 并且可以在中途调用CancelTimer取消
 ////
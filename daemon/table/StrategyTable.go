@@ -26,10 +26,12 @@ import (
 	"github.com/sirupsen/logrus"
 	common2 "minlib/common"
 	"minlib/component"
+	"sync/atomic"
 )
 
 type StrategyTable struct {
-	lpm *LpmMatcher //最长前缀匹配器
+	version uint64      //版本号，管理模块和转发分片会并发访问，需要使用 atomic 读写
+	lpm     *LpmMatcher //最长前缀匹配器
 }
 
 func CreateStrategyTable() *StrategyTable {
@@ -42,6 +44,7 @@ func CreateStrategyTable() *StrategyTable {
 func (s *StrategyTable) Init() {
 	s.lpm = &LpmMatcher{} //初始化
 	s.lpm.Create()        //初始化锁
+	atomic.StoreUint64(&s.version, 0)
 }
 
 // Size 获得StrategyTable的大小
@@ -87,10 +90,12 @@ func (s *StrategyTable) Insert(identifier *component.Identifier, strategyName st
 			val = strategyTableEntry
 		}
 		entry := (val).(*StrategyTableEntry)
+		entry.Identifier = identifier
 		entry.StrategyName = strategyName
 		entry.IStrategy = istrategy
 		return entry
 	})
+	atomic.AddUint64(&s.version, 1)
 	return val.(*StrategyTableEntry)
 
 }
@@ -101,7 +106,47 @@ func (s *StrategyTable) Erase(identifier *component.Identifier) error {
 	for _, v := range identifier.GetComponents() {
 		PrefixList = append(PrefixList, v.ToString())
 	}
-	return s.lpm.Delete(PrefixList)
+	if err := s.lpm.Delete(PrefixList); err != nil {
+		return err
+	}
+	atomic.AddUint64(&s.version, 1)
+	return nil
+}
+
+// FindExactMatch 查询和一个指定的名称前缀精确匹配的策略条目
+func (s *StrategyTable) FindExactMatch(identifier *component.Identifier) *StrategyTableEntry {
+	var PrefixList []string
+	for _, v := range identifier.GetComponents() {
+		PrefixList = append(PrefixList, v.ToString())
+	}
+	if v, ok := s.lpm.FindExactMatch(PrefixList); ok {
+		if strategyTableEntry, ok := v.(*StrategyTableEntry); ok {
+			return strategyTableEntry
+		}
+	}
+	return nil
+}
+
+// GetAllEntry 返回策略表中所有的表项
+func (s *StrategyTable) GetAllEntry() []*StrategyTableEntry {
+	var entries []*StrategyTableEntry
+	s.lpm.TraverseFunc(func(val interface{}) uint64 {
+		if strategyTableEntry, ok := val.(*StrategyTableEntry); ok {
+			entries = append(entries, strategyTableEntry)
+			return 1
+		} else {
+			common2.LogErrorWithFields(logrus.Fields{
+				"value": val,
+			}, "StrategyTableEntry transform fail")
+		}
+		return 0
+	})
+	return entries
+}
+
+// GetVersion 返回策略表当前的版本号
+func (s *StrategyTable) GetVersion() uint64 {
+	return atomic.LoadUint64(&s.version)
 }
 
 // FindEffectiveStrategyEntry 查询和一个指定的名称前缀匹配的策略条目 最长前缀匹配
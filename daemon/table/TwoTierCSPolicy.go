@@ -0,0 +1,751 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 7:40 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package table
+
+import (
+	"container/list"
+	"fmt"
+	"github.com/bluele/gcache"
+	"github.com/sirupsen/logrus"
+	common2 "minlib/common"
+	"minlib/component"
+	"minlib/encoding"
+	"minlib/packet"
+	"sync"
+)
+
+// twoTierCSItem
+// 两级缓存中的一个条目
+//
+// @Description:
+//
+type twoTierCSItem struct {
+	key        string           // 数据包标识的 URI
+	entry      *CSEntry         // 在内存层或者还没有写入磁盘时为完整的条目；写入磁盘之后为只包含数据包标识和不新鲜时间的条目
+	size       int64            // 数据包编码之后的长度，单位 Byte
+	onDisk     bool             // 是否在磁盘层
+	pendingPut *twoTierCSDiskOp // 在磁盘层中但是还没有写入磁盘时，为等待执行的写入操作
+	element    *list.Element    // 在所在层的 LRU 链表中的位置
+}
+
+// twoTierCSDiskOp
+// 一个等待后台协程执行的磁盘操作
+//
+// @Description:
+//
+type twoTierCSDiskOp struct {
+	item      *twoTierCSItem // 写入操作对应的条目，删除操作为 nil
+	key       string         // 数据包标识的 URI
+	staleTime int64          // 写入的条目的不新鲜时间
+	value     []byte         // 写入的数据包编码之后的字节数组，为 nil 时表示删除
+}
+
+// TwoTierCSPolicy
+// 内存加磁盘的两级缓存策略
+//
+// @Description:
+//  1. 内存层和磁盘层的容量都以字节为单位，一个条目只会在其中一层；
+//  2. 新插入的条目进入内存层，内存层超出容量时将最久未被访问的条目降级到磁盘层，磁盘层超出容量时淘汰最久未被访问的条目；
+//  3. 超出内存层容量的大数据包（例如大的视频对象）直接进入磁盘层，不会挤占内存层中的其它条目；
+//  4. 磁盘层中的条目被命中时升级到内存层；
+//  5. 两层共用一个有序的名字树索引以支持 CanBePrefix 的前缀查找，查找时只根据内存中的标识和不新鲜时间筛选候选条目，
+//     选中磁盘层中的条目之后才在锁外读出数据包，再判断是否满足 CSEntry.CanSatisfy ；
+//  6. 写入和删除磁盘层中的条目由一个后台协程按顺序在锁外执行，条目在写入完成之前保留完整的数据包，所以查找不需要等待写入；
+//  7. 磁盘层基于只追加的段文件 CSDiskStore ，重启之后从段文件中恢复，关闭时内存层中的条目也会降级到磁盘层，所以重启之后缓存不是冷的。
+//
+type TwoTierCSPolicy struct {
+	memoryCapacity int64                     // 内存层的容量，单位 Byte
+	diskCapacity   int64                     // 磁盘层的容量，单位 Byte
+	memorySize     int64                     // 内存层中条目的总长度，单位 Byte
+	diskSize       int64                     // 磁盘层中条目的总长度，包括还没有写入磁盘的条目，单位 Byte
+	memoryLru      *list.List                // 内存层的 LRU 链表，链表头为最近被访问的条目
+	diskLru        *list.List                // 磁盘层的 LRU 链表，链表头为最近被访问的条目
+	items          map[string]*twoTierCSItem // 数据包标识的 URI => 条目
+	index          *CSNameTree               // 有序的名字树索引
+	disk           *CSDiskStore              // 磁盘层的存储
+	pending        []*twoTierCSDiskOp        // 等待后台协程执行的磁盘操作，按照加入的顺序执行
+	writing        bool                      // 后台协程是否正在执行一个磁盘操作
+	closed         bool                      // 是否已经关闭，关闭之后后台协程执行完剩余的磁盘操作后退出
+	cond           *sync.Cond                // 通知后台协程有新的磁盘操作，以及通知等待者磁盘操作已经执行完
+	writerWg       sync.WaitGroup            // 等待后台协程退出
+	lock           sync.Mutex
+}
+
+// NewTwoTierCSPolicy
+// 新建一个 TwoTierCSPolicy
+//
+// @Description:
+// @param memoryCapacity		内存层的容量，单位 Byte
+// @param diskCapacity		磁盘层的容量，单位 Byte
+// @param diskPath			磁盘层的存储目录
+// @return *TwoTierCSPolicy
+// @return error
+//
+func NewTwoTierCSPolicy(memoryCapacity int64, diskCapacity int64, diskPath string) (*TwoTierCSPolicy, error) {
+	twoTierCSPolicy := new(TwoTierCSPolicy)
+	return twoTierCSPolicy, twoTierCSPolicy.Init(memoryCapacity, diskCapacity, diskPath)
+}
+
+// Init
+// 初始化 TwoTierCSPolicy ，从磁盘层的段文件中恢复条目
+//
+// @Description:
+//  恢复的条目按照写入的先后顺序进入磁盘层的 LRU 链表，最后写入的条目最近被访问；磁盘层的容量变小时淘汰多出的条目
+// @receiver t
+// @param memoryCapacity
+// @param diskCapacity
+// @param diskPath
+// @return error
+//
+func (t *TwoTierCSPolicy) Init(memoryCapacity int64, diskCapacity int64, diskPath string) error {
+	if memoryCapacity < 0 || diskCapacity < 0 {
+		return createTwoTierCSPolicyErrorByType(InvalidTwoTierCSCapacityError, fmt.Sprintf("memory: %d, disk: %d",
+			memoryCapacity, diskCapacity))
+	}
+	disk, err := OpenCSDiskStore(diskPath)
+	if err != nil {
+		return err
+	}
+	t.memoryCapacity = memoryCapacity
+	t.diskCapacity = diskCapacity
+	t.memoryLru = list.New()
+	t.diskLru = list.New()
+	t.items = make(map[string]*twoTierCSItem)
+	t.index = CreateCSNameTree()
+	t.disk = disk
+	t.cond = sync.NewCond(&t.lock)
+
+	for _, record := range disk.Records() {
+		identifier, err := t.recoverIdentifier(record.Key)
+		if err != nil {
+			_ = disk.Delete(record.Key)
+			continue
+		}
+		item := &twoTierCSItem{
+			key:    record.Key,
+			entry:  newCSEntryWithStaleTime(packet.NewDataByName(identifier), record.StaleTime),
+			size:   record.ValueSize(),
+			onDisk: true,
+		}
+		item.element = t.diskLru.PushFront(item)
+		t.diskSize += item.size
+		t.items[item.key] = item
+		t.index.Insert(getCSNameComponents(identifier), item.entry)
+	}
+	t.writerWg.Add(1)
+	go t.diskWriter()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.shrinkDisk()
+	return nil
+}
+
+// Insert
+// 缓存一个数据包
+//
+// @Description:
+//  1. 标识相同的条目已经存在时，使用新的数据包替换该条目，重新计算其不新鲜时间；
+//  2. 数据包不超过内存层的容量时进入内存层，否则直接进入磁盘层。
+// @receiver t
+// @param data
+// @return *CSEntry
+// @return error
+//
+func (t *TwoTierCSPolicy) Insert(data *packet.Data) (*CSEntry, error) {
+	value, err := encodeCSData(data)
+	if err != nil {
+		return nil, err
+	}
+	key := data.GetName().ToUri()
+	size := int64(len(value))
+	if size > t.memoryCapacity && size > t.diskCapacity {
+		return nil, createTwoTierCSPolicyErrorByType(TwoTierCSDataTooLargeError, fmt.Sprintf("%s (%d bytes)", key, size))
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if item, ok := t.items[key]; ok {
+		t.remove(item)
+	}
+	item := &twoTierCSItem{key: key, entry: NewCSEntry(data), size: size}
+	t.items[key] = item
+	t.index.Insert(getCSNameComponents(data.GetName()), item.entry)
+	if size > t.memoryCapacity {
+		t.writeToDisk(item, value)
+		t.shrinkDisk()
+		return item.entry, nil
+	}
+	item.element = t.memoryLru.PushFront(item)
+	t.memorySize += size
+	return item.entry, t.shrinkMemory()
+}
+
+// Find
+// 根据传入的 Interest 查询CS表中是否缓存有与之匹配的 data
+//
+// @Description:
+//  1. CanBePrefix = false 时精确匹配，CanBePrefix = true 时在名字树索引中按照顺序返回第一个以兴趣包的名字为前缀的条目；
+//  2. 筛选候选条目时只使用内存中的条目判断 CSEntry.CanSatisfy ，已经写入磁盘的条目只包含标识和不新鲜时间；
+//  3. 选中已经写入磁盘的条目之后释放锁读出数据包，重新加锁之后用完整的条目再判断一次，读取期间条目被删除或者替换时视为未命中；
+//  4. 命中内存层中的条目时将其移到 LRU 链表头，命中磁盘层中的条目时将其升级到内存层。
+// @receiver t
+// @param interest
+// @return *CSEntry
+// @return error
+//
+func (t *TwoTierCSPolicy) Find(interest *packet.Interest) (*CSEntry, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	predicate := func(entry *CSEntry) bool {
+		if _, ok := t.items[entry.GetIdentifier().ToUri()]; !ok {
+			return false
+		}
+		return entry.CanSatisfy(interest)
+	}
+	components := getCSNameComponents(interest.GetName())
+	var csEntry *CSEntry
+	if interest.GetCanBePrefix() {
+		csEntry = t.index.FindPrefix(components, getCSFreshAt(interest), predicate)
+	} else if entry := t.index.FindExact(components); entry != nil && predicate(entry) {
+		csEntry = entry
+	}
+	if csEntry == nil {
+		return nil, gcache.KeyNotFoundError
+	}
+
+	item := t.items[csEntry.GetIdentifier().ToUri()]
+	if !item.onDisk {
+		t.memoryLru.MoveToFront(item.element)
+		return item.entry, nil
+	}
+	fullEntry := item.entry
+	if item.pendingPut == nil {
+		// 读取磁盘期间释放锁，不阻塞其它的查找和插入
+		staleTime := item.entry.GetStaleTime()
+		t.lock.Unlock()
+		var err error
+		fullEntry, err = t.readFromDisk(item.key, staleTime)
+		t.lock.Lock()
+		if t.items[item.key] != item {
+			return nil, gcache.KeyNotFoundError
+		}
+		if !item.onDisk {
+			// 读取期间已经被其它的查找升级到内存层
+			t.memoryLru.MoveToFront(item.element)
+			return item.entry, nil
+		}
+		if err != nil {
+			t.remove(item)
+			return nil, gcache.KeyNotFoundError
+		}
+		if !fullEntry.CanSatisfy(interest) {
+			return nil, gcache.KeyNotFoundError
+		}
+	}
+	if item.size > t.memoryCapacity {
+		// 超出内存层容量的条目留在磁盘层
+		t.diskLru.MoveToFront(item.element)
+		return fullEntry, nil
+	}
+	return fullEntry, t.promote(item, fullEntry)
+}
+
+// Size
+// 返回已缓存的数据包的数量
+//
+// @Description:
+// @receiver t
+// @return int
+//
+func (t *TwoTierCSPolicy) Size() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return len(t.items)
+}
+
+// Erase
+// 删除以 prefix 为前缀的条目，包括内存层和磁盘层中的条目
+//
+// @Description:
+// @receiver t
+// @param prefix
+// @param limit		最多删除的条目个数，小于等于0时删除所有匹配的条目
+// @return int		删除的条目个数
+//
+func (t *TwoTierCSPolicy) Erase(prefix *component.Identifier, limit int) int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	entries := t.index.CollectPrefix(getCSNameComponents(prefix), limit)
+	for _, entry := range entries {
+		if item, ok := t.items[entry.GetIdentifier().ToUri()]; ok {
+			t.remove(item)
+		}
+	}
+	return len(entries)
+}
+
+// SetCapacity
+// 修改内存层的容量，单位 Byte ，超出新容量的条目降级到磁盘层
+//
+// @Description:
+//  容量必须大于0，磁盘层的容量只能通过配置文件修改
+// @receiver t
+// @param capacity
+// @return error
+//
+func (t *TwoTierCSPolicy) SetCapacity(capacity int64) error {
+	if capacity <= 0 {
+		return createTwoTierCSPolicyErrorByType(InvalidTwoTierCSCapacityError, fmt.Sprintf("memory: %d", capacity))
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.memoryCapacity = capacity
+	return t.shrinkMemory()
+}
+
+// GetCapacity
+// 获取内存层的容量，单位 Byte
+//
+// @Description:
+// @receiver t
+// @return int64
+//
+func (t *TwoTierCSPolicy) GetCapacity() int64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.memoryCapacity
+}
+
+// MemorySize
+// 返回内存层中条目的总长度，单位 Byte
+//
+// @Description:
+// @receiver t
+// @return int64
+//
+func (t *TwoTierCSPolicy) MemorySize() int64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.memorySize
+}
+
+// DiskSize
+// 返回磁盘层中条目的总长度，单位 Byte
+//
+// @Description:
+//  包括已经降级但是还没有写入磁盘的条目
+// @receiver t
+// @return int64
+//
+func (t *TwoTierCSPolicy) DiskSize() int64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.diskSize
+}
+
+// Close
+// 将内存层中的条目降级到磁盘层，等待后台协程执行完所有的磁盘操作，并关闭磁盘层的存储
+//
+// @Description:
+//  按照从最久未被访问到最近被访问的顺序降级，所以重启之后内存层中最近被访问的条目在磁盘层中也是最近被访问的
+// @receiver t
+// @return error
+//
+func (t *TwoTierCSPolicy) Close() error {
+	t.lock.Lock()
+	var demoteErr error
+	for t.memoryLru.Len() > 0 {
+		// 降级失败的条目会被删除，所以不会死循环
+		if err := t.demote(t.memoryLru.Back().Value.(*twoTierCSItem)); err != nil && demoteErr == nil {
+			demoteErr = err
+		}
+	}
+	t.closed = true
+	t.cond.Broadcast()
+	t.lock.Unlock()
+	t.writerWg.Wait()
+	if err := t.disk.Close(); err != nil {
+		return err
+	}
+	return demoteErr
+}
+
+//
+// 将内存层中最久未被访问的条目降级到磁盘层，直到内存层不超出容量
+//
+// @Description:
+//  只会在持有 lock 时调用
+// @receiver t
+// @return error
+//
+func (t *TwoTierCSPolicy) shrinkMemory() error {
+	for t.memorySize > t.memoryCapacity && t.memoryLru.Len() > 0 {
+		if err := t.demote(t.memoryLru.Back().Value.(*twoTierCSItem)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//
+// 淘汰磁盘层中最久未被访问的条目，直到磁盘层不超出容量
+//
+// @Description:
+//  只会在持有 lock 时调用
+// @receiver t
+//
+func (t *TwoTierCSPolicy) shrinkDisk() {
+	for t.diskSize > t.diskCapacity && t.diskLru.Len() > 0 {
+		t.remove(t.diskLru.Back().Value.(*twoTierCSItem))
+	}
+}
+
+//
+// 将内存层中的一个条目降级到磁盘层，超出磁盘层容量的条目直接淘汰
+//
+// @Description:
+//  只会在持有 lock 时调用
+// @receiver t
+// @param item
+// @return error
+//
+func (t *TwoTierCSPolicy) demote(item *twoTierCSItem) error {
+	if item.size > t.diskCapacity {
+		t.remove(item)
+		return nil
+	}
+	value, err := encodeCSData(item.entry.GetData())
+	if err != nil {
+		t.remove(item)
+		return err
+	}
+	t.memoryLru.Remove(item.element)
+	t.memorySize -= item.size
+	t.writeToDisk(item, value)
+	t.shrinkDisk()
+	return nil
+}
+
+//
+// 将磁盘层中的一个条目升级到内存层
+//
+// @Description:
+//  只会在持有 lock 时调用，磁盘中的记录由后台协程删除
+// @receiver t
+// @param item
+// @param fullEntry		完整的条目
+// @return error
+//
+func (t *TwoTierCSPolicy) promote(item *twoTierCSItem, fullEntry *CSEntry) error {
+	t.diskLru.Remove(item.element)
+	t.diskSize -= item.size
+	t.enqueueDiskOp(&twoTierCSDiskOp{key: item.key})
+	item.onDisk = false
+	item.pendingPut = nil
+	item.entry = fullEntry
+	item.element = t.memoryLru.PushFront(item)
+	t.memorySize += item.size
+	t.index.Insert(getCSNameComponents(fullEntry.GetIdentifier()), fullEntry)
+	return t.shrinkMemory()
+}
+
+//
+// 将一个条目放到磁盘层的 LRU 链表头，并交给后台协程写入磁盘
+//
+// @Description:
+//  只会在持有 lock 时调用，写入完成之前条目保留完整的数据包，写入完成之后在内存中只保留数据包的标识
+// @receiver t
+// @param item
+// @param value		数据包编码之后的字节数组
+//
+func (t *TwoTierCSPolicy) writeToDisk(item *twoTierCSItem, value []byte) {
+	op := &twoTierCSDiskOp{item: item, key: item.key, staleTime: item.entry.GetStaleTime(), value: value}
+	item.onDisk = true
+	item.pendingPut = op
+	item.element = t.diskLru.PushFront(item)
+	t.diskSize += item.size
+	t.enqueueDiskOp(op)
+}
+
+//
+// 从磁盘层中读出一个条目的完整数据包
+//
+// @Description:
+//  不需要持有 lock
+// @receiver t
+// @param key
+// @param staleTime
+// @return *CSEntry
+// @return error
+//
+func (t *TwoTierCSPolicy) readFromDisk(key string, staleTime int64) (*CSEntry, error) {
+	value, err := t.disk.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := decodeCSData(value)
+	if err != nil {
+		return nil, err
+	}
+	return newCSEntryWithStaleTime(data, staleTime), nil
+}
+
+//
+// 删除一个条目
+//
+// @Description:
+//  只会在持有 lock 时调用，磁盘中的记录由后台协程删除
+// @receiver t
+// @param item
+//
+func (t *TwoTierCSPolicy) remove(item *twoTierCSItem) {
+	if item.onDisk {
+		t.diskLru.Remove(item.element)
+		t.diskSize -= item.size
+		t.enqueueDiskOp(&twoTierCSDiskOp{key: item.key})
+		item.pendingPut = nil
+	} else {
+		t.memoryLru.Remove(item.element)
+		t.memorySize -= item.size
+	}
+	t.forget(item)
+}
+
+//
+// 从 items 和名字树索引中删除一个已经不在任何一层的 LRU 链表中的条目
+//
+// @Description:
+//  只会在持有 lock 时调用
+// @receiver t
+// @param item
+//
+func (t *TwoTierCSPolicy) forget(item *twoTierCSItem) {
+	if t.items[item.key] != item {
+		return
+	}
+	delete(t.items, item.key)
+	components := getCSNameComponents(item.entry.GetIdentifier())
+	if t.index.FindExact(components) == item.entry {
+		t.index.Erase(components)
+	}
+}
+
+//
+// 加入一个等待后台协程执行的磁盘操作
+//
+// @Description:
+//  只会在持有 lock 时调用
+// @receiver t
+// @param op
+//
+func (t *TwoTierCSPolicy) enqueueDiskOp(op *twoTierCSDiskOp) {
+	t.pending = append(t.pending, op)
+	t.cond.Broadcast()
+}
+
+//
+// 后台协程，按照加入的顺序在锁外执行磁盘操作
+//
+// @Description:
+//  关闭之后执行完剩余的磁盘操作再退出
+// @receiver t
+//
+func (t *TwoTierCSPolicy) diskWriter() {
+	defer t.writerWg.Done()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for {
+		for len(t.pending) == 0 && !t.closed {
+			t.cond.Wait()
+		}
+		if len(t.pending) == 0 {
+			return
+		}
+		op := t.pending[0]
+		t.pending[0] = nil
+		t.pending = t.pending[1:]
+		t.writing = true
+		t.lock.Unlock()
+
+		var err error
+		if op.value != nil {
+			err = t.disk.Put(op.key, op.staleTime, op.value)
+		} else {
+			err = t.disk.Delete(op.key)
+		}
+
+		t.lock.Lock()
+		t.writing = false
+		if err != nil {
+			common2.LogErrorWithFields(logrus.Fields{
+				"key":   op.key,
+				"error": err,
+			}, "write cs disk tier failed")
+		}
+		if op.item != nil {
+			t.finishPut(op, err)
+		}
+		t.cond.Broadcast()
+	}
+}
+
+//
+// 写入操作执行完之后更新对应的条目
+//
+// @Description:
+//  1. 只会在持有 lock 时调用；
+//  2. 条目在写入期间被删除、升级或者再次降级时不做处理，之后加入的磁盘操作会覆盖这次写入；
+//  3. 写入成功时条目在内存中只保留数据包的标识和不新鲜时间，写入失败时删除条目。
+// @receiver t
+// @param op
+// @param err
+//
+func (t *TwoTierCSPolicy) finishPut(op *twoTierCSDiskOp, err error) {
+	item := op.item
+	if t.items[item.key] != item || !item.onDisk || item.pendingPut != op {
+		return
+	}
+	item.pendingPut = nil
+	if err != nil {
+		t.diskLru.Remove(item.element)
+		t.diskSize -= item.size
+		t.forget(item)
+		return
+	}
+	identifier := item.entry.GetIdentifier()
+	item.entry = newCSEntryWithStaleTime(packet.NewDataByName(identifier), op.staleTime)
+	t.index.Insert(getCSNameComponents(identifier), item.entry)
+}
+
+//
+// 等待后台协程执行完所有已经加入的磁盘操作
+//
+// @Description:
+// @receiver t
+//
+func (t *TwoTierCSPolicy) flush() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for len(t.pending) > 0 || t.writing {
+		t.cond.Wait()
+	}
+}
+
+//
+// 根据磁盘层中条目的键恢复数据包的标识，键无法解析时从数据包中读取
+//
+// @Description:
+// @receiver t
+// @param key
+// @return *component.Identifier
+// @return error
+//
+func (t *TwoTierCSPolicy) recoverIdentifier(key string) (*component.Identifier, error) {
+	if identifier, err := component.CreateIdentifierByString(key); err == nil && identifier.ToUri() == key {
+		return identifier, nil
+	}
+	value, err := t.disk.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := decodeCSData(value)
+	if err != nil {
+		return nil, err
+	}
+	return data.GetName(), nil
+}
+
+//
+// 将数据包编码成字节数组
+//
+// @Description:
+// @param data
+// @return []byte
+// @return error
+//
+func encodeCSData(data *packet.Data) ([]byte, error) {
+	var encoder encoding.Encoder
+	if err := encoder.EncoderReset(encoding.MaxPacketSize, 0); err != nil {
+		return nil, err
+	}
+	bufLen, err := data.WireEncode(&encoder)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := encoder.GetBuffer()
+	if err != nil {
+		return nil, err
+	}
+	value := make([]byte, bufLen)
+	copy(value, buf[:bufLen])
+	return value, nil
+}
+
+//
+// 将字节数组解码成数据包
+//
+// @Description:
+// @param value
+// @return *packet.Data
+// @return error
+//
+func decodeCSData(value []byte) (*packet.Data, error) {
+	block, err := encoding.CreateBlockByBuffer(value, true)
+	if err != nil {
+		return nil, err
+	}
+	var minPacket packet.MINPacket
+	if err := minPacket.WireDecode(block); err != nil {
+		return nil, err
+	}
+	return packet.NewDataByMINPacket(&minPacket)
+}
+
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+///// 错误处理
+/////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	InvalidTwoTierCSCapacityError = iota
+	TwoTierCSDataTooLargeError
+)
+
+type TwoTierCSPolicyError struct {
+	msg string
+}
+
+func (t TwoTierCSPolicyError) Error() string {
+	return fmt.Sprintf("TwoTierCSPolicyError: %s", t.msg)
+}
+
+func createTwoTierCSPolicyErrorByType(errorType int, detail string) (err TwoTierCSPolicyError) {
+	switch errorType {
+	case InvalidTwoTierCSCapacityError:
+		err.msg = fmt.Sprintf("invalid capacity => %s", detail)
+	case TwoTierCSDataTooLargeError:
+		err.msg = fmt.Sprintf("data larger than both tiers => %s", detail)
+	default:
+		err.msg = "Unknown error"
+	}
+	return
+}
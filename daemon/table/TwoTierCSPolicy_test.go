@@ -0,0 +1,94 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 8:30 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package table
+
+import (
+	"fmt"
+	"minlib/packet"
+	"os"
+	"testing"
+)
+
+func TestTwoTierCSPolicy(t *testing.T) {
+	dir, err := os.MkdirTemp("", "cs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	first := createTestCSData("/min/video/seg1", 10000)
+	value, _ := encodeCSData(first)
+	size := int64(len(value))
+
+	// 内存层只能放下一个条目
+	policy, err := NewTwoTierCSPolicy(size, 10*size, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = policy.Insert(first)
+	_, _ = policy.Insert(createTestCSData("/min/video/seg2", 10000))
+	if policy.Size() != 2 || policy.MemorySize() != size || policy.DiskSize() != size {
+		t.Fatal("expect /min/video/seg1 demoted to disk")
+	}
+	if err := policy.SetCapacity(0); err == nil {
+		t.Fatal("expect SetCapacity(0) rejected")
+	}
+
+	// 等待写入磁盘之后，查找需要从磁盘中读出数据包
+	policy.flush()
+	if !policy.items[first.GetName().ToUri()].onDisk || policy.items[first.GetName().ToUri()].pendingPut != nil {
+		t.Fatal("expect /min/video/seg1 written to disk")
+	}
+
+	// 命中磁盘层中的条目时升级到内存层
+	interest := &packet.Interest{}
+	interest.SetName(first.GetName())
+	csEntry, err := policy.Find(interest)
+	if err != nil || csEntry.GetIdentifier().ToUri() != first.GetName().ToUri() {
+		t.Fatal("expect /min/video/seg1 found", err)
+	}
+	if _, ok := policy.items[first.GetName().ToUri()]; !ok || policy.items[first.GetName().ToUri()].onDisk {
+		t.Fatal("expect /min/video/seg1 promoted to memory")
+	}
+
+	// 重启之后从磁盘层恢复
+	if err := policy.Close(); err != nil {
+		t.Fatal(err)
+	}
+	policy, err = NewTwoTierCSPolicy(size, 10*size, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer policy.Close()
+	if policy.Size() != 2 {
+		t.Fatal("expect 2 entries recovered, got", policy.Size())
+	}
+	prefix := createTestCSData("/min/video", 0)
+	interest.SetName(prefix.GetName())
+	interest.SetCanBePrefix(true)
+	csEntry, err = policy.Find(interest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(csEntry.GetData().ToUri())
+}
@@ -22,16 +22,33 @@
 package table
 
 import (
+	"minlib/component"
 	"minlib/packet"
 	"mir-go/daemon/common"
+	"mir-go/daemon/utils"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
-// UniversalCS 基于Hash表实现的 ContentStore
+const (
+	CSStorageMemory  = "memory"   // 只在内存中缓存
+	CSStorageTwoTier = "two-tier" // 内存加磁盘的两级缓存
+)
+
+// UniversalCS 基于Hash表和有序名字树索引实现的 ContentStore
 //
 // @Description:
+//  缓存策略由 TableConfig.CSStorage 选择：memory => 只在内存中缓存的 UniversalCSPolicy ，two-tier => 内存加磁盘的 TwoTierCSPolicy
 //
 type UniversalCS struct {
-	csPolicy ICSPolicy
+	hits            uint64 // 命中次数，64位的原子变量放在结构体开头以保证在32位平台上对齐
+	misses          uint64 // 未命中次数
+	csPolicy        ICSPolicy
+	admissionPolicy ICSAdmissionPolicy // 准入策略，决定一个数据包是否可以被缓存
+	admissionLock   sync.RWMutex       // 保护 admissionPolicy ，使得准入策略可以在运行时被替换
+	disableAdmit    uint32             // 为1时不缓存任何数据包
+	disableServe    uint32             // 为1时不使用缓存的数据包响应兴趣包
 }
 
 // NewUniversalCS 新建一个 UniversalCS
@@ -53,11 +70,35 @@ func NewUniversalCS(config *common.MIRConfig) (*UniversalCS, error) {
 // @return error
 //
 func (h *UniversalCS) Init(config *common.MIRConfig) error {
-	if policy, err := NewUniversalCSPolicy(config.TableConfig.CSSize, config.TableConfig.CSReplaceStrategy); err != nil {
+	switch strings.ToLower(config.TableConfig.CSStorage) {
+	case "", CSStorageMemory:
+		if policy, err := NewUniversalCSPolicy(config.TableConfig.CSSize, config.TableConfig.CSReplaceStrategy); err != nil {
+			return err
+		} else {
+			h.csPolicy = policy
+		}
+	case CSStorageTwoTier:
+		if policy, err := NewTwoTierCSPolicy(config.TableConfig.CSMemoryCapacity, config.TableConfig.CSDiskCapacity,
+			utils.GetRelPath(config.TableConfig.CSDiskPath)); err != nil {
+			return err
+		} else {
+			h.csPolicy = policy
+		}
+	default:
+		return UniversalCSPolicyError{
+			msg: "Not support cs storage: " + config.TableConfig.CSStorage + ", require: " + CSStorageMemory + ", " + CSStorageTwoTier,
+		}
+	}
+	admissionPolicy, err := CreateCSAdmissionPolicy(&CSAdmissionPolicyConfig{
+		Name:          config.TableConfig.CSAdmissionPolicy,
+		Probability:   config.TableConfig.CSAdmissionProbability,
+		AllowPrefixes: config.TableConfig.CSAdmissionAllowPrefixes,
+		DenyPrefixes:  config.TableConfig.CSAdmissionDenyPrefixes,
+	})
+	if err != nil {
 		return err
-	} else {
-		h.csPolicy = policy
 	}
+	h.SetAdmissionPolicy(admissionPolicy)
 	return nil
 }
 
@@ -74,24 +115,177 @@ func (h *UniversalCS) Size() int {
 // Find 根据传入的 Interest 查询CS表中是否缓存有与之匹配的 data
 //
 // @Description:
-// 根据设计的不同，Interest和CS条目匹配的规则也可以有不同的定义：
-//  1. NDN里面，Interest支持CanBePrefix字段，所以查询CS的时候不是精确匹配，是前缀匹配；
-//  2. 在MIN当前的设计里面，为了加快并行转发并简化设计，可能会把Interest查询CS设计为hash查找；
-//     (MIN里面也定义了CanBePrefix字段，但是暂时没有实现该效果，所以这个字段目前是无效字段）
+//  1. Interest 的 CanBePrefix = false 时精确匹配，CanBePrefix = true 时在有序名字树索引中进行前缀匹配；
+//  2. 匹配的条目还需要满足 CSEntry.CanSatisfy ，例如 MustBeFresh = true 时不新鲜的条目不能匹配；
+//  3. 未命中时通知准入策略，second-hit 等策略据此统计请求次数；
+//  4. 关闭 serve 之后不再查找，直接返回错误，也不计入命中和未命中次数。
 // @param interest
 // @return *CSEntry
 //
 func (h *UniversalCS) Find(interest *packet.Interest) (*CSEntry, error) {
-	return h.csPolicy.Find(interest)
+	if !h.IsServeEnabled() {
+		return nil, UniversalCSPolicyError{msg: "CS serve is disabled"}
+	}
+	csEntry, err := h.csPolicy.Find(interest)
+	if err != nil {
+		atomic.AddUint64(&h.misses, 1)
+		h.GetAdmissionPolicy().AfterLookupMiss(interest)
+	} else {
+		atomic.AddUint64(&h.hits, 1)
+	}
+	return csEntry, err
 }
 
 // Insert 将传入的 data 缓存到CS当中
 //
 // @Description:
+// 关闭 admit 之后不缓存任何数据包；插入之前先由准入策略判断是否可以缓存，不被准入的数据包直接返回错误；
 // 插入过程需要根据CS自己定义的缓存替换策略，来替换、踢出或者更新CS条目
 // @param data
 // @return *CSEntry
 //
 func (h *UniversalCS) Insert(data *packet.Data) (*CSEntry, error) {
+	if !h.IsAdmitEnabled() {
+		return nil, UniversalCSPolicyError{msg: "CS admit is disabled"}
+	}
+	admissionPolicy := h.GetAdmissionPolicy()
+	if !admissionPolicy.Admit(data) {
+		return nil, createCSAdmissionPolicyErrorByType(DataNotAdmittedError, admissionPolicy.GetName())
+	}
 	return h.csPolicy.Insert(data)
 }
+
+// Erase 删除以 prefix 为前缀的条目
+//
+// @Description:
+// @receiver h
+// @param prefix
+// @param limit		最多删除的条目个数，小于等于0时删除所有匹配的条目
+// @return int		删除的条目个数
+//
+func (h *UniversalCS) Erase(prefix *component.Identifier, limit int) int {
+	return h.csPolicy.Erase(prefix, limit)
+}
+
+// SetCapacity 修改缓存的容量，memory 缓存的单位为包个数，two-tier 缓存为内存层的字节数
+//
+// @Description:
+// @receiver h
+// @param capacity
+// @return error
+//
+func (h *UniversalCS) SetCapacity(capacity int64) error {
+	return h.csPolicy.SetCapacity(capacity)
+}
+
+// GetCapacity 获取缓存的容量，memory 缓存的单位为包个数，two-tier 缓存为内存层的字节数
+//
+// @Description:
+// @receiver h
+// @return int64
+//
+func (h *UniversalCS) GetCapacity() int64 {
+	return h.csPolicy.GetCapacity()
+}
+
+// SetEnableAdmit 设置是否缓存数据包
+//
+// @Description:
+// @receiver h
+// @param enable
+//
+func (h *UniversalCS) SetEnableAdmit(enable bool) {
+	atomic.StoreUint32(&h.disableAdmit, boolToCSFlag(!enable))
+}
+
+// IsAdmitEnabled 判断是否缓存数据包
+//
+// @Description:
+// @receiver h
+// @return bool
+//
+func (h *UniversalCS) IsAdmitEnabled() bool {
+	return atomic.LoadUint32(&h.disableAdmit) == 0
+}
+
+// SetEnableServe 设置是否使用缓存的数据包响应兴趣包
+//
+// @Description:
+// @receiver h
+// @param enable
+//
+func (h *UniversalCS) SetEnableServe(enable bool) {
+	atomic.StoreUint32(&h.disableServe, boolToCSFlag(!enable))
+}
+
+// IsServeEnabled 判断是否使用缓存的数据包响应兴趣包
+//
+// @Description:
+// @receiver h
+// @return bool
+//
+func (h *UniversalCS) IsServeEnabled() bool {
+	return atomic.LoadUint32(&h.disableServe) == 0
+}
+
+// GetHits 获取命中次数
+//
+// @Description:
+// @receiver h
+// @return uint64
+//
+func (h *UniversalCS) GetHits() uint64 {
+	return atomic.LoadUint64(&h.hits)
+}
+
+// GetMisses 获取未命中次数
+//
+// @Description:
+// @receiver h
+// @return uint64
+//
+func (h *UniversalCS) GetMisses() uint64 {
+	return atomic.LoadUint64(&h.misses)
+}
+
+// Close 关闭缓存策略，two-tier 缓存会将内存层中的条目持久化到磁盘
+//
+// @Description:
+// @receiver h
+// @return error
+//
+func (h *UniversalCS) Close() error {
+	return h.csPolicy.Close()
+}
+
+// SetAdmissionPolicy 设置准入策略，可以在运行时调用
+//
+// @Description:
+// @receiver h
+// @param policy
+//
+func (h *UniversalCS) SetAdmissionPolicy(policy ICSAdmissionPolicy) {
+	h.admissionLock.Lock()
+	defer h.admissionLock.Unlock()
+	h.admissionPolicy = policy
+}
+
+// GetAdmissionPolicy 获取当前使用的准入策略
+//
+// @Description:
+// @receiver h
+// @return ICSAdmissionPolicy
+//
+func (h *UniversalCS) GetAdmissionPolicy() ICSAdmissionPolicy {
+	h.admissionLock.RLock()
+	defer h.admissionLock.RUnlock()
+	return h.admissionPolicy
+}
+
+// boolToCSFlag 将 bool 转换成可以原子读写的标志位
+func boolToCSFlag(value bool) uint32 {
+	if value {
+		return 1
+	}
+	return 0
+}
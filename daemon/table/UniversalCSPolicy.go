@@ -22,18 +22,32 @@
 package table
 
 import (
+	"container/heap"
 	"fmt"
 	"github.com/bluele/gcache"
+	"minlib/component"
 	"minlib/packet"
+	"mir-go/daemon/common"
+	"sort"
 	"strings"
+	"sync"
 )
 
 // UniversalCSPolicy 统一的缓存策略实现，基于gcache实现了LFU, LRU and ARC缓存替换策略
 //
 // @Description:
+//  1. gcache 负责缓存替换，键为数据包标识的 URI ；
+//  2. 同时维护一个有序的名字树索引，用于支持 CanBePrefix 的前缀查找，gcache 替换或者删除条目时通过 EvictedFunc 同步删除索引中的条目；
+//  3. 转发器的多个分片会并发的查找和插入，所以 gcache 和索引的访问都在 lock 的保护下进行，保证两者一致；
+//  4. 缓存已满时优先淘汰已经不新鲜的条目，没有不新鲜的条目时才由 gcache 按照替换策略淘汰，所有条目按照不新鲜时间维护在一个小顶堆中。
 //
 type UniversalCSPolicy struct {
-	cache gcache.Cache
+	cache     gcache.Cache
+	index     *CSNameTree // 有序的名字树索引
+	stale     csStaleHeap // 按照不新鲜时间排列的条目，条目被刷新或者被淘汰之后，其旧的记录会在出堆时被丢弃
+	capacity  int         // 最多缓存的数据包数
+	cacheType string      // 缓存替换策略 LRU | LFU | ARC
+	lock      sync.Mutex
 }
 
 // NewUniversalCSPolicy 新建一个 UniversalCSPolicy
@@ -53,62 +67,83 @@ func NewUniversalCSPolicy(capacity int, cacheType string) (*UniversalCSPolicy, e
 // @param capacity
 //
 func (L *UniversalCSPolicy) Init(capacity int, cacheType string) error {
-	cacheBuilder := gcache.New(capacity)
-	switch strings.ToLower(cacheType) {
-	case "lru":
-		cacheBuilder = cacheBuilder.LRU()
-	case "lfu":
-		cacheBuilder = cacheBuilder.LFU()
-	case "arc":
-		cacheBuilder = cacheBuilder.ARC()
-	default:
-		return UniversalCSPolicyError{
-			msg: "Not support cache policy: " + cacheType + ", require: LRU, LFU, ARC",
-		}
+	L.cacheType = cacheType
+	cache, err := L.buildCache(capacity)
+	if err != nil {
+		return err
 	}
-	L.cache = cacheBuilder.
-		Build()
+	L.index = CreateCSNameTree()
+	L.stale = make(csStaleHeap, 0)
+	L.capacity = capacity
+	L.cache = cache
 	return nil
 }
 
 // Insert 缓存一个数据包
 //
 // @Description:
+//  1. 标识相同的条目已经存在时，使用新的数据包刷新该条目，重新计算其不新鲜时间；
+//  2. 插入新的条目时如果缓存已满，优先淘汰一个已经不新鲜的条目。
 // @param data
 // @return *CSEntry 返回缓存成功的CS条目
 //
 func (L *UniversalCSPolicy) Insert(data *packet.Data) (*CSEntry, error) {
+	L.lock.Lock()
+	defer L.lock.Unlock()
 	key := data.GetName().ToUri()
 	if item, err := L.cache.Get(key); err != nil {
 		// 不存在，则构建一个 CSEntry 插入
+		if L.capacity > 0 && L.cache.Len(false) >= L.capacity {
+			L.evictStale(int64(common.GetCurrentTime()))
+		}
 		csEntry := NewCSEntry(data)
 		if err := L.cache.Set(key, csEntry); err != nil {
 			return nil, err
 		}
+		L.index.Insert(getCSNameComponents(data.GetName()), csEntry)
+		L.pushStale(csEntry)
 		return csEntry, nil
 	} else {
-		// 存在
-		return item.(*CSEntry), nil
+		// 存在，则刷新
+		csEntry := item.(*CSEntry)
+		csEntry.Refresh(data)
+		// 不新鲜时间变化之后需要更新名字树索引中记录的子树最晚的不新鲜时间
+		L.index.Insert(getCSNameComponents(data.GetName()), csEntry)
+		L.pushStale(csEntry)
+		return csEntry, nil
 	}
 }
 
 // Find 根据传入的 Interest 查询CS表中是否缓存有与之匹配的 data
 //
 // @Description:
-// 根据设计的不同，Interest和CS条目匹配的规则也可以有不同的定义：
-//  1. NDN里面，Interest支持CanBePrefix字段，所以查询CS的时候不是精确匹配，是前缀匹配；
-//  2. 在MIN当前的设计里面，为了加快并行转发并简化设计，可能会把Interest查询CS设计为hash查找；
-//     (MIN里面也定义了CanBePrefix字段，但是暂时没有实现该效果，所以这个字段目前是无效字段）
+//  1. CanBePrefix = false 时精确匹配，只有标识与兴趣包的名字相同的条目才能匹配；
+//  2. CanBePrefix = true 时前缀匹配，在名字树索引中按照顺序返回第一个以兴趣包的名字为前缀的条目，
+//     MustBeFresh = true 时跳过所有条目都已经不新鲜的子树；
+//  3. 两种情况下条目都需要满足 CSEntry.CanSatisfy ，例如 MustBeFresh = true 时不新鲜的条目不能匹配；
+//  4. 命中的条目会通过 gcache 访问一次，以更新 LRU 、 LFU 或者 ARC 的统计信息。
 // @param interest
 // @return *CSEntry
 //
 func (L *UniversalCSPolicy) Find(interest *packet.Interest) (*CSEntry, error) {
-	key := interest.GetName().ToUri()
-	if item, err := L.cache.Get(key); err != nil {
+	L.lock.Lock()
+	defer L.lock.Unlock()
+	components := getCSNameComponents(interest.GetName())
+	var csEntry *CSEntry
+	if interest.GetCanBePrefix() {
+		csEntry = L.index.FindPrefix(components, getCSFreshAt(interest), func(entry *CSEntry) bool {
+			return entry.CanSatisfy(interest)
+		})
+	} else if entry := L.index.FindExact(components); entry != nil && entry.CanSatisfy(interest) {
+		csEntry = entry
+	}
+	if csEntry == nil {
+		return nil, gcache.KeyNotFoundError
+	}
+	if _, err := L.cache.Get(csEntry.GetIdentifier().ToUri()); err != nil {
 		return nil, err
-	} else {
-		return item.(*CSEntry), nil
 	}
+	return csEntry, nil
 }
 
 // Size 返回已缓存的数据包的数量
@@ -120,6 +155,250 @@ func (L *UniversalCSPolicy) Size() int {
 	return L.cache.Len(false)
 }
 
+// Erase 删除以 prefix 为前缀的条目
+//
+// @Description:
+// @param prefix
+// @param limit		最多删除的条目个数，小于等于0时删除所有匹配的条目
+// @return int		删除的条目个数
+//
+func (L *UniversalCSPolicy) Erase(prefix *component.Identifier, limit int) int {
+	L.lock.Lock()
+	defer L.lock.Unlock()
+	entries := L.index.CollectPrefix(getCSNameComponents(prefix), limit)
+	for _, entry := range entries {
+		L.cache.Remove(entry.GetIdentifier().ToUri())
+		L.index.Erase(getCSNameComponents(entry.GetIdentifier()))
+	}
+	return len(entries)
+}
+
+// SetCapacity 修改最多缓存的数据包数
+//
+// @Description:
+//  gcache 不支持修改容量，所以使用新的容量重建 gcache ，按照不新鲜时间从早到晚的顺序重新插入现有的条目，
+//  容量变小时由 gcache 按照替换策略淘汰，越早变得不新鲜的条目越先被淘汰
+// @param capacity
+// @return error
+//
+func (L *UniversalCSPolicy) SetCapacity(capacity int64) error {
+	if capacity <= 0 {
+		return UniversalCSPolicyError{msg: fmt.Sprintf("Invalid capacity: %d", capacity)}
+	}
+	L.lock.Lock()
+	defer L.lock.Unlock()
+	cache, err := L.buildCache(int(capacity))
+	if err != nil {
+		return err
+	}
+	entries := make([]*CSEntry, 0, L.cache.Len(false))
+	for _, value := range L.cache.GetALL(false) {
+		if entry, ok := value.(*CSEntry); ok {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].GetStaleTime() < entries[j].GetStaleTime()
+	})
+
+	L.cache = cache
+	L.capacity = int(capacity)
+	L.index = CreateCSNameTree()
+	for _, entry := range entries {
+		if err := L.cache.Set(entry.GetIdentifier().ToUri(), entry); err != nil {
+			continue
+		}
+		L.index.Insert(getCSNameComponents(entry.GetIdentifier()), entry)
+	}
+	L.stale = L.stale[:0]
+	for _, value := range L.cache.GetALL(false) {
+		if entry, ok := value.(*CSEntry); ok {
+			L.stale = append(L.stale, csStaleItem{staleTime: entry.GetStaleTime(), entry: entry})
+		}
+	}
+	heap.Init(&L.stale)
+	return nil
+}
+
+// GetCapacity 获取最多缓存的数据包数
+//
+// @Description:
+// @return int64
+//
+func (L *UniversalCSPolicy) GetCapacity() int64 {
+	L.lock.Lock()
+	defer L.lock.Unlock()
+	return int64(L.capacity)
+}
+
+// Close UniversalCSPolicy 只在内存中缓存，没有需要释放的资源
+//
+// @Description:
+// @return error
+//
+func (L *UniversalCSPolicy) Close() error {
+	return nil
+}
+
+//
+// 根据缓存替换策略构建一个容量为 capacity 的 gcache
+//
+// @Description:
+// @receiver L
+// @param capacity
+// @return gcache.Cache
+// @return error
+//
+func (L *UniversalCSPolicy) buildCache(capacity int) (gcache.Cache, error) {
+	cacheBuilder := gcache.New(capacity)
+	switch strings.ToLower(L.cacheType) {
+	case "lru":
+		cacheBuilder = cacheBuilder.LRU()
+	case "lfu":
+		cacheBuilder = cacheBuilder.LFU()
+	case "arc":
+		cacheBuilder = cacheBuilder.ARC()
+	default:
+		return nil, UniversalCSPolicyError{
+			msg: "Not support cache policy: " + L.cacheType + ", require: LRU, LFU, ARC",
+		}
+	}
+	return cacheBuilder.
+		EvictedFunc(L.onEvicted).
+		Build(), nil
+}
+
+//
+// gcache 替换或者删除一个条目时，同步删除名字树索引中的条目
+//
+// @Description:
+//  只会在持有 lock 时被 gcache 调用
+// @receiver L
+// @param key
+// @param value
+//
+func (L *UniversalCSPolicy) onEvicted(key interface{}, value interface{}) {
+	csEntry, ok := value.(*CSEntry)
+	if !ok {
+		return
+	}
+	components := getCSNameComponents(csEntry.GetIdentifier())
+	if L.index.FindExact(components) == csEntry {
+		L.index.Erase(components)
+	}
+}
+
+//
+// 淘汰一个在 now 时刻已经不新鲜的条目
+//
+// @Description:
+//  只会在持有 lock 时调用
+// @receiver L
+// @param now
+// @return bool		是否淘汰了一个条目
+//
+func (L *UniversalCSPolicy) evictStale(now int64) bool {
+	for L.stale.Len() > 0 && L.stale[0].staleTime <= now {
+		item := heap.Pop(&L.stale).(csStaleItem)
+		components := getCSNameComponents(item.entry.GetIdentifier())
+		if L.index.FindExact(components) != item.entry || item.entry.GetStaleTime() != item.staleTime {
+			// 条目已经被淘汰或者被刷新，丢弃旧的记录
+			continue
+		}
+		L.cache.Remove(item.entry.GetIdentifier().ToUri())
+		L.index.Erase(components)
+		return true
+	}
+	return false
+}
+
+//
+// 记录条目当前的不新鲜时间
+//
+// @Description:
+//  只会在持有 lock 时调用，旧的记录过多时根据缓存中现有的条目重建小顶堆
+// @receiver L
+// @param csEntry
+//
+func (L *UniversalCSPolicy) pushStale(csEntry *CSEntry) {
+	heap.Push(&L.stale, csStaleItem{staleTime: csEntry.GetStaleTime(), entry: csEntry})
+	if L.stale.Len() <= 2*L.capacity+csStaleHeapSlack {
+		return
+	}
+	L.stale = L.stale[:0]
+	for _, value := range L.cache.GetALL(false) {
+		if entry, ok := value.(*CSEntry); ok {
+			L.stale = append(L.stale, csStaleItem{staleTime: entry.GetStaleTime(), entry: entry})
+		}
+	}
+	heap.Init(&L.stale)
+}
+
+//
+// 获取标识的组件列表，用于在名字树索引中查找
+//
+// @Description:
+// @param identifier
+// @return []string
+//
+func getCSNameComponents(identifier *component.Identifier) []string {
+	components := make([]string, 0, identifier.Size())
+	for _, v := range identifier.GetComponents() {
+		components = append(components, v.ToString())
+	}
+	return components
+}
+
+//
+// 获取兴趣包要求条目保持新鲜的时刻，用于在名字树索引中跳过不新鲜的子树
+//
+// @Description:
+// @param interest
+// @return int64		MustBeFresh = true 时为当前时间，否则为 0
+//
+func getCSFreshAt(interest *packet.Interest) int64 {
+	if interest.GetMustBeRefresh() {
+		return int64(common.GetCurrentTime())
+	}
+	return 0
+}
+
+// csStaleHeapSlack 小顶堆中允许的旧记录数超过两倍容量的余量
+const csStaleHeapSlack = 16
+
+// csStaleItem 条目在某个时刻的不新鲜时间
+type csStaleItem struct {
+	staleTime int64    // 记录时条目的不新鲜时间
+	entry     *CSEntry // 条目
+}
+
+// csStaleHeap 按照不新鲜时间排列的小顶堆，实现了 heap.Interface
+type csStaleHeap []csStaleItem
+
+func (h csStaleHeap) Len() int {
+	return len(h)
+}
+
+func (h csStaleHeap) Less(i, j int) bool {
+	return h[i].staleTime < h[j].staleTime
+}
+
+func (h csStaleHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *csStaleHeap) Push(x interface{}) {
+	*h = append(*h, x.(csStaleItem))
+}
+
+func (h *csStaleHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = csStaleItem{}
+	*h = old[:len(old)-1]
+	return item
+}
+
 /////////////////////////////////////////////////////////////////////////////////////////////////////////
 ///// 错误处理
 /////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,100 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package table
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/19 6:00 上午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package table
+
+import (
+	"fmt"
+	"minlib/component"
+	"minlib/packet"
+	"testing"
+)
+
+func createTestCSData(name string, freshnessPeriod uint64) *packet.Data {
+	identifier, _ := component.CreateIdentifierByString(name)
+	data := &packet.Data{}
+	data.SetName(identifier)
+	data.FreshnessPeriod.SetFreshnessPeriod(freshnessPeriod)
+	return data
+}
+
+func TestUniversalCSPolicy_Freshness(t *testing.T) {
+	policy, err := NewUniversalCSPolicy(2, "LRU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	staleEntry, _ := policy.Insert(createTestCSData("/min/stale", 0))
+	freshEntry, _ := policy.Insert(createTestCSData("/min/fresh", 10000))
+	if !staleEntry.IsStale() || freshEntry.IsStale() {
+		t.Fatal("expect /min/stale stale and /min/fresh fresh")
+	}
+
+	// 再次插入时刷新不新鲜时间
+	policy.Insert(createTestCSData("/min/stale", 10000))
+	if staleEntry.IsStale() {
+		t.Fatal("expect /min/stale refreshed")
+	}
+	policy.Insert(createTestCSData("/min/stale", 0))
+
+	// 缓存已满时优先淘汰不新鲜的条目，即使它是最近被访问的
+	policy.Insert(createTestCSData("/min/new", 10000))
+	interest := &packet.Interest{}
+	interest.SetName(freshEntry.GetIdentifier())
+	if _, err := policy.Find(interest); err != nil {
+		t.Fatal("expect /min/fresh not evicted")
+	}
+	interest.SetName(staleEntry.GetIdentifier())
+	if _, err := policy.Find(interest); err == nil {
+		t.Fatal("expect /min/stale evicted")
+	}
+	fmt.Println(policy.Size())
+}
+
+func TestUniversalCSPolicy_EraseAndSetCapacity(t *testing.T) {
+	policy, err := NewUniversalCSPolicy(10, "LRU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy.Insert(createTestCSData("/video/seg1", 10000))
+	policy.Insert(createTestCSData("/video/seg2", 10000))
+	policy.Insert(createTestCSData("/video/seg3", 0))
+	policy.Insert(createTestCSData("/audio/seg1", 10000))
+
+	// 最多删除一个条目
+	prefix := createTestCSData("/video", 0).GetName()
+	if erased := policy.Erase(prefix, 1); erased != 1 || policy.Size() != 3 {
+		t.Fatal("expect 1 entry erased, got", erased)
+	}
+
+	// 容量变小时不新鲜的条目先被淘汰
+	if err := policy.SetCapacity(2); err != nil {
+		t.Fatal(err)
+	}
+	interest := &packet.Interest{}
+	interest.SetName(createTestCSData("/video/seg3", 0).GetName())
+	if _, err := policy.Find(interest); err == nil || policy.Size() != 2 {
+		t.Fatal("expect /video/seg3 evicted")
+	}
+	if erased := policy.Erase(prefix, 0); erased != 1 {
+		t.Fatal("expect remaining /video entry erased, got", erased)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package utils
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 7:10 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+package utils
+
+import (
+	"container/heap"
+	"time"
+)
+
+// timerEvent 一个定时事件
+type timerEvent struct {
+	key      string    // 事件的键，同一个键同时只能存在一个事件
+	deadline time.Time // 事件的到期时间
+	callback func()    // 到期时执行的回调
+	index    int       // 在堆中的下标
+}
+
+// timerEventHeap 按到期时间排序的小顶堆，实现 heap.Interface
+type timerEventHeap []*timerEvent
+
+func (h timerEventHeap) Len() int { return len(h) }
+
+func (h timerEventHeap) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+
+func (h timerEventHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *timerEventHeap) Push(x interface{}) {
+	event := x.(*timerEvent)
+	event.index = len(*h)
+	*h = append(*h, event)
+}
+
+func (h *timerEventHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	event := old[n-1]
+	old[n-1] = nil
+	event.index = -1
+	*h = old[:n-1]
+	return event
+}
+
+// DeadlineTimer
+// 基于小顶堆的定时器，可以获取最早的到期时间
+//
+// @Description:
+//  1. 定时器本身不会启动任何协程，也不加锁，需要由使用者在同一个协程中调用 DealEvent 处理到期事件；
+//  2. 使用者可以通过 NextDeadline 获取最早的到期时间，据此设置一个 time.Timer 在 select 中等待，从而避免轮询。
+//
+type DeadlineTimer struct {
+	events timerEventHeap         // 按到期时间排序的事件堆
+	keyMap map[string]*timerEvent // 键 => 事件
+}
+
+// NewDeadlineTimer
+// 新建一个定时器
+//
+// @Description:
+// @return *DeadlineTimer
+//
+func NewDeadlineTimer() *DeadlineTimer {
+	return &DeadlineTimer{
+		events: make(timerEventHeap, 0),
+		keyMap: make(map[string]*timerEvent),
+	}
+}
+
+// AddTimeoutEvent
+// 添加一个定时事件，如果已经存在相同键的事件，则替换之
+//
+// @Description:
+// @receiver t
+// @param duration		多久之后到期，单位 ms
+// @param key			事件的键
+// @param callback		到期时执行的回调
+//
+func (t *DeadlineTimer) AddTimeoutEvent(duration int64, key string, callback func()) {
+	t.CancelEvent(key)
+	event := &timerEvent{
+		key:      key,
+		deadline: time.Now().Add(time.Duration(duration) * time.Millisecond),
+		callback: callback,
+	}
+	heap.Push(&t.events, event)
+	t.keyMap[key] = event
+}
+
+// CancelEvent
+// 取消一个定时事件
+//
+// @Description:
+// @receiver t
+// @param key
+//
+func (t *DeadlineTimer) CancelEvent(key string) {
+	if event, ok := t.keyMap[key]; ok {
+		heap.Remove(&t.events, event.index)
+		delete(t.keyMap, key)
+	}
+}
+
+// NextDeadline
+// 获取最早的到期时间
+//
+// @Description:
+// @receiver t
+// @return time.Time
+// @return bool			没有任何事件时返回 false
+//
+func (t *DeadlineTimer) NextDeadline() (time.Time, bool) {
+	if len(t.events) == 0 {
+		return time.Time{}, false
+	}
+	return t.events[0].deadline, true
+}
+
+// DealEvent
+// 处理所有已经到期的事件
+//
+// @Description:
+//  回调中可以继续添加或取消事件，新添加的已到期事件也会在本次调用中被处理
+// @receiver t
+//
+func (t *DeadlineTimer) DealEvent() {
+	for len(t.events) > 0 {
+		event := t.events[0]
+		if event.deadline.After(time.Now()) {
+			return
+		}
+		heap.Pop(&t.events)
+		delete(t.keyMap, event.key)
+		event.callback()
+	}
+}
+
+// Size
+// 获取当前事件的数量
+//
+// @Description:
+// @receiver t
+// @return int
+//
+func (t *DeadlineTimer) Size() int {
+	return len(t.events)
+}
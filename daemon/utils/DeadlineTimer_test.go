@@ -0,0 +1,60 @@
+// Copyright [2022] [MIN-Group -- Peking University Shenzhen Graduate School Multi-Identifier Network Development Group]
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package utils
+// @Author: Jianming Que
+// @Description:
+// @Version: 1.0.0
+// @Date: 2026/10/18 7:40 下午
+// @Copyright: MIN-Group；国家重大科技基础设施——未来网络北大实验室；深圳市信息论与未来网络重点实验室
+//
+
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadlineTimer(t *testing.T) {
+	timer := NewDeadlineTimer()
+	fired := make([]string, 0)
+	timer.AddTimeoutEvent(20, "/a", func() { fired = append(fired, "/a") })
+	timer.AddTimeoutEvent(10, "/b", func() { fired = append(fired, "/b") })
+	timer.AddTimeoutEvent(0, "/c", func() { fired = append(fired, "/c") })
+	timer.AddTimeoutEvent(5, "/d", func() { fired = append(fired, "/d") })
+	timer.CancelEvent("/d")
+
+	// 相同的键会替换之前的事件
+	timer.AddTimeoutEvent(30, "/a", func() { fired = append(fired, "/a") })
+
+	timer.DealEvent()
+	if len(fired) != 1 || fired[0] != "/c" {
+		t.Fatal("only /c should fire immediately", fired)
+	}
+
+	deadline, ok := timer.NextDeadline()
+	if !ok || time.Until(deadline) > 10*time.Millisecond {
+		t.Fatal("next deadline should be /b")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	timer.DealEvent()
+	if len(fired) != 3 || fired[1] != "/b" || fired[2] != "/a" {
+		t.Fatal("events should fire in deadline order", fired)
+	}
+	if _, ok := timer.NextDeadline(); ok || timer.Size() != 0 {
+		t.Fatal("timer should be empty")
+	}
+}
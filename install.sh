@@ -59,6 +59,12 @@ cp "$GOPATH"/bin/mirgen "$usr_bin_path"/mirgen # 拷贝到 /usr/local/bin
 echo "mirc install to $GOPATH/bin/mirgen and $usr_bin_path/mirgen"
 echo ""
 
+echo "======================== compile and install miraudit ==========================="
+go install ./daemon/mircmd/miraudit
+cp "$GOPATH"/bin/miraudit "$usr_bin_path"/miraudit # 拷贝到 /usr/local/bin
+echo "miraudit install to $GOPATH/bin/miraudit and $usr_bin_path/miraudit"
+echo ""
+
 echo "======================== compile and install mirc ==========================="
 go install ./daemon/mgmt/mirc
 cp "$GOPATH"/bin/mirc "$usr_bin_path"/mirc # 拷贝到 /usr/local/bin
@@ -98,4 +104,14 @@ else
 fi
 echo ""
 
+echo "======================== copy trustSchema config file ==========================="
+# 如果配置文件不存在，则将配置文件拷贝到指定目录下
+if [ ! -f /usr/local/etc/mir/trustSchema.xml ]; then
+  sudo cp trustSchema.xml /usr/local/etc/mir/trustSchema.xml
+  echo "file trustSchema.xml already copy to /usr/local/etc/mir/trustSchema.xml"
+else
+  echo "file trustSchema.xml already exists~"
+fi
+echo ""
+
 sudo "$GOPATH"/bin/mirgen